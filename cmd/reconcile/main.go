@@ -0,0 +1,60 @@
+// Command reconcile menghitung ulang counter sold/held pada tabel events
+// berdasarkan isi tabel tickets. Jalankan sekali setelah upgrade:
+//
+//	go run ./cmd/reconcile
+//
+// Tambahkan -restore-capacity untuk mengembalikan kapasitas awal event yang sebelumnya
+// dikurangi langsung oleh pembelian tiket. Pemulihan hanya bisa berjalan sekali dan hanya
+// menghitung tiket sebelum upgrade (atau sebelum -restore-before).
+package main
+
+import (
+	"flag"
+	"log"
+	"ticketing/config"
+	"ticketing/repository"
+	"time"
+)
+
+func main() {
+	restoreCapacity := flag.Bool("restore-capacity", false, "add legacy purchased qty back to event capacity")
+	restoreBefore := flag.String("restore-before", "", "with -restore-capacity: count tickets created before this RFC 3339 time (default: recorded upgrade time)")
+	flag.Parse()
+
+	var before *time.Time
+	if *restoreBefore != "" {
+		cutoff, err := time.Parse(time.RFC3339, *restoreBefore)
+		if err != nil {
+			log.Fatalf("Invalid -restore-before: %v", err)
+		}
+		before = &cutoff
+	}
+
+	cfg := config.LoadConfig()
+
+	db, err := config.ConnectDB(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	eventRepo := repository.NewEventRepository(db)
+
+	if *restoreCapacity {
+		cutoff, err := eventRepo.RestoreLegacyCapacity(before)
+		if err != nil {
+			log.Fatalf("Failed to restore legacy capacity: %v", err)
+		}
+		log.Printf("Restored legacy event capacity for tickets created before %s", cutoff.Format(time.RFC3339))
+	}
+
+	changed, err := eventRepo.ReconcileCounters()
+	if err != nil {
+		log.Fatalf("Failed to reconcile event counters: %v", err)
+	}
+
+	for _, event := range changed {
+		log.Printf("Event %d (%s): sold=%d held=%d offered=%d available=%d",
+			event.ID, event.Name, event.Sold, event.Held, event.Offered, event.Available())
+	}
+	log.Printf("Reconciled %d event(s)", len(changed))
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"log"
 	"os"
+	"time"
+	_ "time/tzdata" // database zona waktu IANA ikut di-embed agar event timezone tetap valid tanpa tzdata sistem
 
 	"github.com/joho/godotenv"
 )
@@ -14,6 +16,25 @@ type Config struct {
 	DBPassword string
 	DBName     string
 	JWTSecret  string
+	// AppEnv adalah lingkungan aplikasi (production, development, test)
+	AppEnv string
+
+	// HoldSweepInterval mengatur seberapa sering tiket yang lewat batas pembayaran dibatalkan
+	HoldSweepInterval time.Duration
+	// WaitlistOfferWindow adalah lama tawaran waitlist berlaku sebelum diteruskan ke antrean berikutnya
+	WaitlistOfferWindow time.Duration
+	// EventStatusInterval mengatur seberapa sering status event disesuaikan dengan jadwalnya
+	EventStatusInterval time.Duration
+	// EventDefaultDuration dipakai sebagai lama event jika end_date_time tidak diisi
+	EventDefaultDuration time.Duration
+	// DefaultTimezone dipakai untuk event tanpa timezone dan untuk membaca data waktu lama saat migrasi
+	DefaultTimezone string
+
+	PaymentProvider      string
+	PaymentCurrency      string
+	PaymentWebhookSecret string
+	FakePaymentMode      string
+	FakePaymentDelay     time.Duration
 }
 
 func LoadConfig() *Config {
@@ -30,5 +51,40 @@ func LoadConfig() *Config {
 		DBPassword: os.Getenv("DB_PASSWORD"),
 		DBName:     os.Getenv("DB_NAME"),
 		JWTSecret:  os.Getenv("JWT_SECRET"),
+		AppEnv:     getString("APP_ENV", "production"),
+
+		HoldSweepInterval:    getDuration("HOLD_SWEEP_INTERVAL", time.Minute),
+		WaitlistOfferWindow:  getDuration("WAITLIST_OFFER_WINDOW", 30*time.Minute),
+		EventStatusInterval:  getDuration("EVENT_STATUS_INTERVAL", time.Minute),
+		EventDefaultDuration: getDuration("EVENT_DEFAULT_DURATION", 3*time.Hour),
+		DefaultTimezone:      getString("DEFAULT_TIMEZONE", "UTC"),
+
+		PaymentProvider:      getString("PAYMENT_PROVIDER", "fake"),
+		PaymentCurrency:      getString("PAYMENT_CURRENCY", "USD"),
+		PaymentWebhookSecret: os.Getenv("PAYMENT_WEBHOOK_SECRET"),
+		FakePaymentMode:      getString("FAKE_PAYMENT_MODE", "delayed"),
+		FakePaymentDelay:     getDuration("FAKE_PAYMENT_DELAY", 5*time.Second),
+	}
+}
+
+func getString(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getDuration membaca durasi (mis. "30s", "5m") dari env, atau fallback jika kosong/tidak valid
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("Invalid %s %q, using %s", key, value, fallback)
+		return fallback
 	}
+	return d
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"ticketing/model"
+	"time"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -28,12 +29,33 @@ func ConnectDB(cfg *Config) (*gorm.DB, error) {
 	if err := AutoMigrate(db); err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
+	if err := MigrateLegacyTimes(db, cfg); err != nil {
+		log.Fatalf("Failed to migrate legacy time columns: %v", err)
+	}
 
 	return db, nil
 }
 
 // AutoMigrate menjalankan migrasi otomatis untuk model-model yang telah ditentukan
 func AutoMigrate(db *gorm.DB) error {
-	// Migrasi semua model yang digunakan
-	return db.AutoMigrate(&model.User{}, &model.Event{}, &model.Ticket{})
+	// Database lama belum punya counter sold/held dan masih mengurangi kapasitas di setiap pembelian
+	legacyCapacity := db.Migrator().HasTable(&model.Event{}) && !db.Migrator().HasColumn(&model.Event{}, "Sold")
+
+	// Migrasi semua model yang digunakan; tabel yang dirujuk foreign key (mis. event_series
+	// oleh events.series_id) harus dibuat lebih dulu
+	if err := db.AutoMigrate(&model.DataMigration{}, &model.User{}, &model.Venue{}, &model.Category{}, &model.Tag{}, &model.EventSeries{}, &model.Event{}, &model.TicketType{}, &model.Order{}, &model.OrderItem{},
+		&model.Ticket{}, &model.Seat{}, &model.Payment{},
+		&model.WebhookDelivery{}, &model.CancellationRule{}, &model.Refund{},
+		&model.PromoCode{}, &model.PromoRedemption{}, &model.WaitlistEntry{},
+		&model.TicketTransfer{}, &model.SigningKey{}, &model.TicketCredential{},
+		&model.StaffAssignment{}, &model.CheckIn{}, &model.AttendeePass{},
+		&model.RegistrationQuestion{}, &model.TicketAnswer{}); err != nil {
+		return err
+	}
+
+	// Waktu upgrade menjadi batas tiket yang dipulihkan oleh reconcile -restore-capacity
+	if legacyCapacity {
+		return db.Create(&model.DataMigration{Name: model.InventoryCountersMigration, AppliedAt: time.Now()}).Error
+	}
+	return nil
 }
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"ticketing/model"
+	"ticketing/utils"
+
+	"gorm.io/gorm"
+)
+
+// MigrateLegacyTimes mengonversi kolom waktu lama berbentuk string (events.date_time,
+// events.end_date_time, tickets.booking_date) ke kolom DATETIME baru lalu menghapus
+// kolom lama. Aman dijalankan berulang kali: tidak melakukan apa pun jika kolom lama
+// sudah tidak ada.
+//
+// Jadwal event lama dibaca di DEFAULT_TIMEZONE; tanggal booking lama ditulis dengan
+// jam server sehingga dibaca di zona waktu lokal server.
+func MigrateLegacyTimes(db *gorm.DB, cfg *Config) error {
+	loc, err := time.LoadLocation(cfg.DefaultTimezone)
+	if err != nil {
+		return fmt.Errorf("invalid DEFAULT_TIMEZONE %q: %w", cfg.DefaultTimezone, err)
+	}
+
+	if err := migrateEventTimes(db, loc, cfg.DefaultTimezone, cfg.EventDefaultDuration); err != nil {
+		return err
+	}
+	return migrateBookingDates(db)
+}
+
+func migrateEventTimes(db *gorm.DB, loc *time.Location, timezone string, defaultDuration time.Duration) error {
+	m := db.Migrator()
+	if !m.HasColumn(&model.Event{}, "date_time") {
+		return nil
+	}
+	hasEnd := m.HasColumn(&model.Event{}, "end_date_time")
+
+	type legacyEvent struct {
+		ID          uint
+		DateTime    string
+		EndDateTime string
+	}
+	columns := "id, date_time"
+	if hasEnd {
+		columns += ", end_date_time"
+	}
+	var rows []legacyEvent
+	if err := db.Table("events").Select(columns).Where("starts_at IS NULL").Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	// Semua event dikonversi dalam satu transaksi; satu tanggal yang tidak valid membatalkan migrasi
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			start, err := utils.ParseTimeIn(row.DateTime, loc)
+			if err != nil {
+				return fmt.Errorf("event %d date_time: %w", row.ID, err)
+			}
+			end := start.Add(defaultDuration)
+			if row.EndDateTime != "" {
+				if parsed, err := utils.ParseTimeIn(row.EndDateTime, loc); err == nil && parsed.After(start) {
+					end = parsed
+				}
+			}
+
+			if err := tx.Table("events").Where("id = ?", row.ID).Updates(map[string]interface{}{
+				"starts_at": start,
+				"ends_at":   end,
+				"timezone":  timezone,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := m.DropColumn(&model.Event{}, "date_time"); err != nil {
+		return err
+	}
+	if hasEnd {
+		if err := m.DropColumn(&model.Event{}, "end_date_time"); err != nil {
+			return err
+		}
+	}
+	log.Printf("Migrated schedule of %d event(s) to starts_at/ends_at", len(rows))
+	return nil
+}
+
+func migrateBookingDates(db *gorm.DB) error {
+	m := db.Migrator()
+	if !m.HasColumn(&model.Ticket{}, "booking_date") {
+		return nil
+	}
+
+	type legacyTicket struct {
+		ID          uint
+		BookingDate string
+		CreatedAt   time.Time
+	}
+	var rows []legacyTicket
+	if err := db.Table("tickets").Select("id, booking_date, created_at").
+		Where("booked_at IS NULL").Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			// Tanggal booking yang tidak bisa dibaca diganti waktu pembuatan tiket
+			bookedAt, err := utils.ParseTimeIn(row.BookingDate, time.Local)
+			if err != nil {
+				bookedAt = row.CreatedAt
+			}
+			if err := tx.Table("tickets").Where("id = ?", row.ID).Update("booked_at", bookedAt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := m.DropColumn(&model.Ticket{}, "booking_date"); err != nil {
+		return err
+	}
+	log.Printf("Migrated booking date of %d ticket(s) to booked_at", len(rows))
+	return nil
+}
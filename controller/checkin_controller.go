@@ -0,0 +1,214 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"ticketing/dto"
+	"ticketing/middleware"
+	"ticketing/model"
+	"ticketing/service"
+	"ticketing/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CheckInController struct {
+	checkInService service.CheckInService
+}
+
+func NewCheckInController(checkInService service.CheckInService) *CheckInController {
+	return &CheckInController{checkInService: checkInService}
+}
+
+// CheckIn mengembalikan 200 jika tiket diterima dan 409 beserta alasannya jika ditolak
+func (c *CheckInController) CheckIn(ctx *gin.Context) {
+	var req dto.CheckInRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	isAdmin := middleware.GetUserRole(ctx) == string(model.Admin)
+	result, err := c.checkInService.CheckIn(middleware.GetUserID(ctx), isAdmin, req)
+	if errors.Is(err, service.ErrNotAssignedStaff) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if result.Result == string(model.CheckInRejected) {
+		ctx.JSON(http.StatusConflict, result)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+func (c *CheckInController) GetBundle(ctx *gin.Context) {
+	eventID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	isAdmin := middleware.GetUserRole(ctx) == string(model.Admin)
+	bundle, err := c.checkInService.GetBundle(middleware.GetUserID(ctx), isAdmin, uint(eventID))
+	if errors.Is(err, service.ErrNotAssignedStaff) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, bundle)
+}
+
+func (c *CheckInController) SyncOffline(ctx *gin.Context) {
+	eventID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	var req dto.OfflineSyncRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	isAdmin := middleware.GetUserRole(ctx) == string(model.Admin)
+	result, err := c.checkInService.SyncOffline(middleware.GetUserID(ctx), isAdmin, uint(eventID), req)
+	if errors.Is(err, service.ErrNotAssignedStaff) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+func (c *CheckInController) GetSummary(ctx *gin.Context) {
+	eventID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	summary, err := c.checkInService.GetSummary(uint(eventID))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, summary)
+}
+
+func (c *CheckInController) GetScans(ctx *gin.Context) {
+	eventID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+	page, limit := utils.ParsePaginationQuery(ctx)
+
+	scans, pagination, err := c.checkInService.GetScans(uint(eventID), page, limit, ctx.Query("result"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       scans,
+		"pagination": pagination,
+	})
+}
+
+func (c *CheckInController) ReviewConflict(ctx *gin.Context) {
+	checkInID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid check-in ID"})
+		return
+	}
+
+	var req dto.CheckInReviewRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	scan, err := c.checkInService.ReviewConflict(middleware.GetUserID(ctx), uint(checkInID), req)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scan)
+}
+
+func (c *CheckInController) AssignStaff(ctx *gin.Context) {
+	eventID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	var req dto.StaffAssignmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assignment, err := c.checkInService.AssignStaff(uint(eventID), req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, assignment)
+}
+
+func (c *CheckInController) GetStaff(ctx *gin.Context) {
+	eventID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	staff, err := c.checkInService.GetStaff(uint(eventID))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": staff})
+}
+
+func (c *CheckInController) UnassignStaff(ctx *gin.Context) {
+	eventID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+	userID, err := strconv.Atoi(ctx.Param("userId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	if err := c.checkInService.UnassignStaff(uint(eventID), uint(userID)); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "staff unassigned successfully"})
+}
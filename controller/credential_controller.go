@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"ticketing/middleware"
+	"ticketing/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CredentialController struct {
+	credentialService service.CredentialService
+}
+
+func NewCredentialController(credentialService service.CredentialService) *CredentialController {
+	return &CredentialController{credentialService: credentialService}
+}
+
+func (c *CredentialController) GetPublicKeys(ctx *gin.Context) {
+	keys, err := c.credentialService.GetPublicKeys()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, keys)
+}
+
+func (c *CredentialController) RotateKey(ctx *gin.Context) {
+	key, err := c.credentialService.RotateKey()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, key)
+}
+
+func (c *CredentialController) GetCredential(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+	isAdmin := middleware.GetUserRole(ctx) == "admin"
+
+	ticketID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ticket ID"})
+		return
+	}
+
+	issued, err := c.credentialService.GetCredential(userID, isAdmin, uint(ticketID))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, issued)
+}
+
+func (c *CredentialController) GetRevoked(ctx *gin.Context) {
+	eventID, _ := strconv.Atoi(ctx.Query("event_id"))
+
+	revoked, err := c.credentialService.GetRevoked(uint(eventID))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": revoked})
+}
@@ -1,9 +1,12 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
+	"slices"
 	"strconv"
 	"ticketing/dto"
+	"ticketing/repository"
 	"ticketing/service"
 	"ticketing/utils"
 
@@ -11,11 +14,12 @@ import (
 )
 
 type EventController struct {
-	eventService service.EventService
+	eventService  service.EventService
+	seriesService service.SeriesService
 }
 
-func NewEventController(eventService service.EventService) *EventController {
-	return &EventController{eventService: eventService}
+func NewEventController(eventService service.EventService, seriesService service.SeriesService) *EventController {
+	return &EventController{eventService: eventService, seriesService: seriesService}
 }
 
 func (c *EventController) CreateEvent(ctx *gin.Context) {
@@ -27,7 +31,7 @@ func (c *EventController) CreateEvent(ctx *gin.Context) {
 
 	res, err := c.eventService.CreateEvent(req)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.JSON(venueErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -36,9 +40,26 @@ func (c *EventController) CreateEvent(ctx *gin.Context) {
 
 func (c *EventController) GetAllEvents(ctx *gin.Context) {
 	page, limit := utils.ParsePaginationQuery(ctx)
-	search := ctx.Query("search")
 
-	events, pagination, err := c.eventService.GetAllEvents(page, limit, search)
+	// Hanya parameter yang terdokumentasi yang diterima
+	for key := range ctx.Request.URL.Query() {
+		if !slices.Contains(dto.EventSearchParams, key) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "unknown query parameter " + key})
+			return
+		}
+	}
+
+	var query dto.EventSearchQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	events, pagination, facets, err := c.eventService.GetAllEvents(page, limit, query)
+	if errors.Is(err, service.ErrInvalidEventQuery) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -47,6 +68,7 @@ func (c *EventController) GetAllEvents(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{
 		"data":       events,
 		"pagination": pagination,
+		"facets":     facets,
 	})
 }
 
@@ -79,26 +101,68 @@ func (c *EventController) UpdateEvent(ctx *gin.Context) {
 		return
 	}
 
-	event, err := c.eventService.UpdateEvent(uint(id), req)
+	// ?scope=following|all meneruskan perubahan ke kejadian lain dalam seri yang sama
+	switch scope := ctx.Query("scope"); scope {
+	case "", service.EditScopeThis:
+		event, err := c.eventService.UpdateEvent(uint(id), req)
+		if err != nil {
+			ctx.JSON(venueErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, event)
+	case service.EditScopeFollowing, service.EditScopeAll:
+		events, err := c.seriesService.UpdateOccurrences(uint(id), req, scope)
+		if err != nil {
+			ctx.JSON(venueErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"data": events})
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "scope must be this, following or all"})
+	}
+}
+
+func (c *EventController) DeleteEvent(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	if err := c.eventService.DeleteEvent(uint(id)); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, event)
+	ctx.JSON(http.StatusOK, gin.H{"message": "event deleted successfully"})
 }
 
-func (c *EventController) DeleteEvent(ctx *gin.Context) {
+func (c *EventController) SetStatus(ctx *gin.Context) {
 	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
 		return
 	}
 
-	if err := c.eventService.DeleteEvent(uint(id)); err != nil {
+	var req dto.EventStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "event deleted successfully"})
+	event, err := c.eventService.SetStatus(uint(id), req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, event)
+}
+
+// venueErrorStatus memetakan venue yang sudah terpakai di jam yang sama ke 409, error lain ke 400
+func venueErrorStatus(err error) int {
+	if errors.Is(err, repository.ErrVenueDoubleBooked) {
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
 }
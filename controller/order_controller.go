@@ -0,0 +1,175 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"ticketing/dto"
+	"ticketing/middleware"
+	"ticketing/repository"
+	"ticketing/service"
+	"ticketing/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OrderController struct {
+	orderService service.OrderService
+}
+
+func NewOrderController(orderService service.OrderService) *OrderController {
+	return &OrderController{orderService: orderService}
+}
+
+func (c *OrderController) GetCart(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	cart, err := c.orderService.GetCart(userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+func (c *OrderController) AddToCart(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req dto.CartItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cart, err := c.orderService.AddToCart(userID, req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+func (c *OrderController) RemoveFromCart(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	itemID, err := strconv.Atoi(ctx.Param("itemId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid cart item ID"})
+		return
+	}
+
+	cart, err := c.orderService.RemoveFromCart(userID, uint(itemID))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cart)
+}
+
+func (c *OrderController) Checkout(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	order, err := c.orderService.Checkout(userID)
+	if errors.Is(err, repository.ErrSoldOut) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, order)
+}
+
+func (c *OrderController) GetUserOrders(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	page, limit := utils.ParsePaginationQuery(ctx)
+
+	orders, pagination, err := c.orderService.GetUserOrders(userID, page, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       orders,
+		"pagination": pagination,
+	})
+}
+
+func (c *OrderController) GetOrderByID(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+	orderID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+		return
+	}
+
+	order, err := c.orderService.GetOrderByID(userID, uint(orderID))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, order)
+}
+
+func (c *OrderController) PayOrder(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+	orderID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+		return
+	}
+
+	order, err := c.orderService.PayOrder(userID, uint(orderID))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, order)
+}
+
+func (c *OrderController) CancelOrder(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+	orderID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+		return
+	}
+
+	order, err := c.orderService.CancelOrder(userID, uint(orderID))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, order)
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"ticketing/dto"
+	"ticketing/middleware"
+	"ticketing/model"
+	"ticketing/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PassController struct {
+	passService service.PassService
+}
+
+func NewPassController(passService service.PassService) *PassController {
+	return &PassController{passService: passService}
+}
+
+func (c *PassController) GetPasses(ctx *gin.Context) {
+	ticketID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ticket ID"})
+		return
+	}
+
+	isAdmin := middleware.GetUserRole(ctx) == string(model.Admin)
+	passes, err := c.passService.GetPasses(middleware.GetUserID(ctx), isAdmin, uint(ticketID))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": passes})
+}
+
+func (c *PassController) UpdatePass(ctx *gin.Context) {
+	ticketID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ticket ID"})
+		return
+	}
+	passID, err := strconv.Atoi(ctx.Param("passId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid pass ID"})
+		return
+	}
+
+	var req dto.AttendeePassRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pass, err := c.passService.UpdatePass(middleware.GetUserID(ctx), uint(ticketID), uint(passID), req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pass)
+}
+
+func (c *PassController) GetPassCredential(ctx *gin.Context) {
+	ticketID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ticket ID"})
+		return
+	}
+	passID, err := strconv.Atoi(ctx.Param("passId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid pass ID"})
+		return
+	}
+
+	isAdmin := middleware.GetUserRole(ctx) == string(model.Admin)
+	credential, err := c.passService.GetPassCredential(middleware.GetUserID(ctx), isAdmin, uint(ticketID), uint(passID))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, credential)
+}
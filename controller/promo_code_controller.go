@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"ticketing/dto"
+	"ticketing/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PromoCodeController struct {
+	promoCodeService service.PromoCodeService
+}
+
+func NewPromoCodeController(promoCodeService service.PromoCodeService) *PromoCodeController {
+	return &PromoCodeController{promoCodeService: promoCodeService}
+}
+
+func (c *PromoCodeController) GetPromoCodes(ctx *gin.Context) {
+	promos, err := c.promoCodeService.GetPromoCodes()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": promos})
+}
+
+func (c *PromoCodeController) GetPromoCodeByID(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid promo code ID"})
+		return
+	}
+
+	promo, err := c.promoCodeService.GetPromoCodeByID(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, promo)
+}
+
+func (c *PromoCodeController) CreatePromoCode(ctx *gin.Context) {
+	var req dto.PromoCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	promo, err := c.promoCodeService.CreatePromoCode(req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, promo)
+}
+
+func (c *PromoCodeController) UpdatePromoCode(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid promo code ID"})
+		return
+	}
+
+	var req dto.PromoCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	promo, err := c.promoCodeService.UpdatePromoCode(uint(id), req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, promo)
+}
+
+func (c *PromoCodeController) DeletePromoCode(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid promo code ID"})
+		return
+	}
+
+	if err := c.promoCodeService.DeletePromoCode(uint(id)); err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "promo code deleted successfully"})
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"ticketing/dto"
+	"ticketing/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QuestionController struct {
+	questionService service.QuestionService
+}
+
+func NewQuestionController(questionService service.QuestionService) *QuestionController {
+	return &QuestionController{questionService: questionService}
+}
+
+func (c *QuestionController) GetForm(ctx *gin.Context) {
+	eventID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	form, err := c.questionService.GetForm(uint(eventID))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, form)
+}
+
+func (c *QuestionController) SetForm(ctx *gin.Context) {
+	eventID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	var req dto.RegistrationFormRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	form, err := c.questionService.SetForm(uint(eventID), req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, form)
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"strconv"
 	"ticketing/service"
 
 	"github.com/gin-gonic/gin"
@@ -38,6 +39,16 @@ func (r *ReportController) GetEventReports(c *gin.Context) {
 	c.JSON(200, reports)
 }
 
+// Endpoint untuk laporan pemakaian kode promo
+func (r *ReportController) GetPromoCodeReports(c *gin.Context) {
+	reports, err := r.reportService.GetPromoCodeReports(r.db)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to get promo code reports"})
+		return
+	}
+	c.JSON(200, reports)
+}
+
 // Method untuk generate summary report dalam format PDF
 func (ctrl *ReportController) GenerateSummaryReportPDF(c *gin.Context) {
 	pdfBytes, err := ctrl.reportService.GenerateSummaryReportPDF(ctrl.db)
@@ -59,3 +70,41 @@ func (ctrl *ReportController) GenerateEventReportPDF(c *gin.Context) {
 	}
 	c.JSON(200, gin.H{"message": "Event report PDF generated successfully"})
 }
+
+// Endpoint laporan attendee per event; ?format=xlsx atau ?format=csv untuk unduhan
+func (r *ReportController) GetAttendeeReport(c *gin.Context) {
+	eventID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	fileName := "AttendeeReport-" + c.Param("id")
+	switch c.Query("format") {
+	case "xlsx":
+		data, err := r.reportService.GenerateAttendeeReportExcel(r.db, uint(eventID))
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("Content-Disposition", "attachment; filename="+fileName+".xlsx")
+		c.Data(200, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", data)
+	case "csv":
+		data, err := r.reportService.GenerateAttendeeReportCSV(r.db, uint(eventID))
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("Content-Disposition", "attachment; filename="+fileName+".csv")
+		c.Data(200, "text/csv", data)
+	case "":
+		report, err := r.reportService.GetAttendeeReport(r.db, uint(eventID))
+		if err != nil {
+			c.JSON(404, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(200, report)
+	default:
+		c.JSON(400, gin.H{"error": "format must be xlsx or csv"})
+	}
+}
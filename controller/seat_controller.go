@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"ticketing/dto"
+	"ticketing/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SeatController struct {
+	seatService service.SeatService
+}
+
+func NewSeatController(seatService service.SeatService) *SeatController {
+	return &SeatController{seatService: seatService}
+}
+
+func (c *SeatController) GetSeatMap(ctx *gin.Context) {
+	eventID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	seatMap, err := c.seatService.GetSeatMap(uint(eventID))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, seatMap)
+}
+
+func (c *SeatController) ImportSeatMap(ctx *gin.Context) {
+	eventID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	var req dto.SeatMapRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	seatMap, err := c.seatService.ImportSeatMap(uint(eventID), req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, seatMap)
+}
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"ticketing/dto"
+	"ticketing/middleware"
+	"ticketing/service"
+	"ticketing/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SeriesController struct {
+	seriesService service.SeriesService
+}
+
+func NewSeriesController(seriesService service.SeriesService) *SeriesController {
+	return &SeriesController{seriesService: seriesService}
+}
+
+func (c *SeriesController) CreateSeries(ctx *gin.Context) {
+	var req dto.SeriesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	series, err := c.seriesService.CreateSeries(req)
+	if err != nil {
+		ctx.JSON(venueErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, series)
+}
+
+func (c *SeriesController) GetAllSeries(ctx *gin.Context) {
+	page, limit := utils.ParsePaginationQuery(ctx)
+
+	series, pagination, err := c.seriesService.GetAllSeries(page, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       series,
+		"pagination": pagination,
+	})
+}
+
+func (c *SeriesController) GetSeriesByID(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid series ID"})
+		return
+	}
+
+	series, err := c.seriesService.GetSeriesByID(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, series)
+}
+
+func (c *SeriesController) PurchasePass(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid series ID"})
+		return
+	}
+
+	var req dto.SeriesPassRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := c.seriesService.PurchasePass(userID, uint(id), req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, order)
+}
@@ -1,11 +1,13 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"ticketing/dto"
 	"ticketing/middleware"
+	"ticketing/repository"
 	"ticketing/service"
 	"ticketing/utils"
 
@@ -34,6 +36,10 @@ func (c *TicketController) PurchaseTicket(ctx *gin.Context) {
 	}
 
 	ticket, err := c.ticketService.PurchaseTicket(userID, req)
+	if errors.Is(err, repository.ErrSoldOut) || errors.Is(err, repository.ErrSeatUnavailable) || errors.Is(err, repository.ErrPromoCodeExhausted) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -99,12 +105,22 @@ func (c *TicketController) CancelTicket(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.ticketService.CancelTicket(userID, uint(ticketID)); err != nil {
+	// Body opsional, hanya berisi alasan pembatalan
+	var req dto.CancelTicketRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	refund, err := c.ticketService.CancelTicket(userID, uint(ticketID), req.Reason)
+	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "ticket cancelled successfully"})
+	ctx.JSON(http.StatusOK, gin.H{"message": "ticket cancelled successfully", "refund": refund})
 }
 
 func (c *TicketController) UpdatePayment(ctx *gin.Context) {
@@ -151,3 +167,23 @@ func (c *TicketController) GetAllTickets(ctx *gin.Context) {
 		"pagination": pagination,
 	})
 }
+
+func (c *TicketController) DownloadTicketPDF(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+	isAdmin := middleware.GetUserRole(ctx) == "admin"
+
+	ticketID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ticket ID"})
+		return
+	}
+
+	pdfData, err := c.ticketService.GetTicketPDF(userID, isAdmin, uint(ticketID))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("Content-Disposition", "attachment; filename=ETicket-"+strconv.Itoa(ticketID)+".pdf")
+	ctx.Data(http.StatusOK, "application/pdf", pdfData)
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"ticketing/dto"
+	"ticketing/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TicketTypeController struct {
+	ticketTypeService service.TicketTypeService
+}
+
+func NewTicketTypeController(ticketTypeService service.TicketTypeService) *TicketTypeController {
+	return &TicketTypeController{ticketTypeService: ticketTypeService}
+}
+
+func (c *TicketTypeController) GetTicketTypes(ctx *gin.Context) {
+	eventID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	ticketTypes, err := c.ticketTypeService.GetTicketTypes(uint(eventID))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": ticketTypes})
+}
+
+func (c *TicketTypeController) CreateTicketType(ctx *gin.Context) {
+	eventID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	var req dto.TicketTypeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ticketType, err := c.ticketTypeService.CreateTicketType(uint(eventID), req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, ticketType)
+}
+
+func (c *TicketTypeController) UpdateTicketType(ctx *gin.Context) {
+	eventID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	typeID, err := strconv.Atoi(ctx.Param("typeId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ticket type ID"})
+		return
+	}
+
+	var req dto.TicketTypeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ticketType, err := c.ticketTypeService.UpdateTicketType(uint(eventID), uint(typeID), req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ticketType)
+}
+
+func (c *TicketTypeController) DeleteTicketType(ctx *gin.Context) {
+	eventID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	typeID, err := strconv.Atoi(ctx.Param("typeId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ticket type ID"})
+		return
+	}
+
+	if err := c.ticketTypeService.DeleteTicketType(uint(eventID), uint(typeID)); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "ticket type deleted successfully"})
+}
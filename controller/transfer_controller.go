@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"ticketing/dto"
+	"ticketing/middleware"
+	"ticketing/repository"
+	"ticketing/service"
+	"ticketing/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TransferController struct {
+	transferService service.TransferService
+}
+
+func NewTransferController(transferService service.TransferService) *TransferController {
+	return &TransferController{transferService: transferService}
+}
+
+func (c *TransferController) InitiateTransfer(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+	ticketID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ticket ID"})
+		return
+	}
+
+	var req dto.TransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transfer, err := c.transferService.InitiateTransfer(userID, uint(ticketID), req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, transfer)
+}
+
+func (c *TransferController) CancelTransfer(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+	ticketID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ticket ID"})
+		return
+	}
+
+	if err := c.transferService.CancelTransfer(userID, uint(ticketID)); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "transfer cancelled successfully"})
+}
+
+func (c *TransferController) GetIncomingTransfers(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+
+	transfers, err := c.transferService.GetIncomingTransfers(userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": transfers})
+}
+
+func (c *TransferController) AcceptTransfer(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+	transferID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid transfer ID"})
+		return
+	}
+
+	ticket, err := c.transferService.AcceptTransfer(userID, uint(transferID))
+	if errors.Is(err, repository.ErrTransferNotPending) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ticket)
+}
+
+func (c *TransferController) GetTransfers(ctx *gin.Context) {
+	page, limit := utils.ParsePaginationQuery(ctx)
+	ticketID, _ := strconv.Atoi(ctx.Query("ticket_id"))
+
+	transfers, pagination, err := c.transferService.GetTransfers(page, limit, uint(ticketID))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       transfers,
+		"pagination": pagination,
+	})
+}
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"ticketing/dto"
+	"ticketing/repository"
+	"ticketing/service"
+	"ticketing/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VenueController struct {
+	venueService service.VenueService
+}
+
+func NewVenueController(venueService service.VenueService) *VenueController {
+	return &VenueController{venueService: venueService}
+}
+
+func (c *VenueController) CreateVenue(ctx *gin.Context) {
+	var req dto.VenueRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	venue, err := c.venueService.CreateVenue(req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, venue)
+}
+
+func (c *VenueController) GetAllVenues(ctx *gin.Context) {
+	page, limit := utils.ParsePaginationQuery(ctx)
+
+	venues, pagination, err := c.venueService.GetAllVenues(page, limit, ctx.Query("city"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       venues,
+		"pagination": pagination,
+	})
+}
+
+func (c *VenueController) GetVenueByID(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid venue ID"})
+		return
+	}
+
+	venue, err := c.venueService.GetVenueByID(uint(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, venue)
+}
+
+func (c *VenueController) UpdateVenue(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid venue ID"})
+		return
+	}
+
+	var req dto.VenueRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	venue, err := c.venueService.UpdateVenue(uint(id), req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, venue)
+}
+
+func (c *VenueController) DeleteVenue(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid venue ID"})
+		return
+	}
+
+	err = c.venueService.DeleteVenue(uint(id))
+	if errors.Is(err, repository.ErrVenueInUse) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "venue deleted successfully"})
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"ticketing/dto"
+	"ticketing/middleware"
+	"ticketing/repository"
+	"ticketing/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WaitlistController struct {
+	waitlistService service.WaitlistService
+	ticketService   service.TicketService
+}
+
+func NewWaitlistController(waitlistService service.WaitlistService, ticketService service.TicketService) *WaitlistController {
+	return &WaitlistController{waitlistService: waitlistService, ticketService: ticketService}
+}
+
+func (c *WaitlistController) Join(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+	eventID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	var req dto.WaitlistJoinRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, err := c.waitlistService.Join(userID, uint(eventID), req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, entry)
+}
+
+func (c *WaitlistController) GetPosition(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+	eventID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	entry, err := c.waitlistService.GetPosition(userID, uint(eventID))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entry)
+}
+
+func (c *WaitlistController) Leave(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+	eventID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	if err := c.waitlistService.Leave(userID, uint(eventID)); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "left the waitlist successfully"})
+}
+
+func (c *WaitlistController) AcceptOffer(ctx *gin.Context) {
+	userID := middleware.GetUserID(ctx)
+	entryID, err := strconv.Atoi(ctx.Param("entryId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid waitlist entry ID"})
+		return
+	}
+
+	var req dto.WaitlistAcceptRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ticket, err := c.ticketService.AcceptWaitlistOffer(userID, uint(entryID), req)
+	if errors.Is(err, repository.ErrOfferNotActive) || errors.Is(err, repository.ErrSoldOut) ||
+		errors.Is(err, repository.ErrSeatUnavailable) || errors.Is(err, repository.ErrPromoCodeExhausted) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, ticket)
+}
+
+func (c *WaitlistController) GetQueue(ctx *gin.Context) {
+	eventID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid event ID"})
+		return
+	}
+
+	entries, err := c.waitlistService.GetQueue(uint(eventID))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": entries})
+}
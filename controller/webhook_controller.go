@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"ticketing/payment"
+	"ticketing/service"
+	"ticketing/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SignatureHeader berisi HMAC-SHA256 (hex) atas raw body webhook
+const SignatureHeader = "X-Signature"
+
+type WebhookController struct {
+	webhookService service.WebhookService
+}
+
+func NewWebhookController(webhookService service.WebhookService) *WebhookController {
+	return &WebhookController{webhookService: webhookService}
+}
+
+func (c *WebhookController) ReceivePaymentWebhook(ctx *gin.Context) {
+	payload, err := ctx.GetRawData()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "unable to read request body"})
+		return
+	}
+
+	delivery, err := c.webhookService.ReceivePaymentWebhook(ctx.Param("provider"), payload, ctx.GetHeader(SignatureHeader))
+	switch {
+	case errors.Is(err, service.ErrUnknownProvider):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, payment.ErrInvalidSignature):
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	case delivery == nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Kegagalan pemrosesan tetap dijawab 200: delivery tersimpan dan bisa di-replay admin
+	ctx.JSON(http.StatusOK, gin.H{
+		"id":     delivery.ID,
+		"status": delivery.Status,
+	})
+}
+
+func (c *WebhookController) GetDeliveries(ctx *gin.Context) {
+	page, limit := utils.ParsePaginationQuery(ctx)
+
+	deliveries, pagination, err := c.webhookService.GetDeliveries(page, limit, ctx.Query("status"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":       deliveries,
+		"pagination": pagination,
+	})
+}
+
+func (c *WebhookController) ReplayDelivery(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook ID"})
+		return
+	}
+
+	delivery, err := c.webhookService.Replay(uint(id))
+	if delivery == nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "data": delivery})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, delivery)
+}
@@ -0,0 +1,107 @@
+// Package credential membuat dan memverifikasi kredensial tiket bertanda tangan
+// Ed25519 yang bisa dicek scanner tanpa menghubungi API.
+//
+// Format kredensial: "v1.<payload>.<signature>", keduanya base64url tanpa padding.
+// Tanda tangan dibuat atas string "v1.<payload>".
+package credential
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const version = "v1"
+
+var (
+	ErrMalformed    = errors.New("malformed ticket credential")
+	ErrUnknownKey   = errors.New("ticket credential signed by unknown key")
+	ErrBadSignature = errors.New("invalid ticket credential signature")
+	ErrExpired      = errors.New("ticket credential is not valid at this time")
+)
+
+// Claims adalah isi kredensial; nama field dibuat pendek agar muat di QR code
+type Claims struct {
+	ID        string `json:"jti"` // ID unik kredensial, dipakai di daftar revokasi
+	KeyID     string `json:"kid"`
+	TicketID  uint   `json:"tid"`
+	EventID   uint   `json:"eid"`
+	PassID    uint   `json:"pid,omitempty"` // diisi untuk kredensial satu attendee pass
+	Qty       int    `json:"qty"`
+	Version   int    `json:"ver"` // versi kredensial tiket, naik saat tiket ditransfer
+	NotBefore int64  `json:"nbf"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// GenerateKey membuat pasangan kunci baru beserta key ID yang diturunkan dari public key
+func GenerateKey() (string, ed25519.PublicKey, ed25519.PrivateKey, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8]), pub, priv, nil
+}
+
+// NewID membuat ID acak untuk kredensial baru
+func NewID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Sign menandatangani claims dengan private key
+func Sign(key ed25519.PrivateKey, claims Claims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signed := version + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature := ed25519.Sign(key, []byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Verify memeriksa tanda tangan dan masa berlaku kredensial. lookup mengembalikan
+// public key untuk key ID tertentu (biasanya kunci aktif dan sebelumnya).
+func Verify(token string, lookup func(keyID string) (ed25519.PublicKey, bool), now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != version {
+		return nil, ErrMalformed
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrMalformed
+	}
+
+	key, ok := lookup(claims.KeyID)
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	if !ed25519.Verify(key, []byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrBadSignature
+	}
+
+	if now.Unix() < claims.NotBefore || now.Unix() > claims.ExpiresAt {
+		return &claims, ErrExpired
+	}
+	return &claims, nil
+}
@@ -4,7 +4,7 @@ type RegisterRequest struct {
 	Name     string `json:"name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
-	Role     string `json:"role" binding:"omitempty,oneof=admin user"` // tambah ini
+	Role     string `json:"role" binding:"omitempty,oneof=admin user staff"` // tambah ini
 }
 
 type LoginRequest struct {
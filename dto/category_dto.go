@@ -0,0 +1,28 @@
+package dto
+
+type CategoryRequest struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	Slug     string `json:"slug" binding:"omitempty,max=100"` // default dari name
+	ParentID *uint  `json:"parent_id"`                        // nil untuk kategori akar
+	Position int    `json:"position"`
+}
+
+type CategoryResponse struct {
+	ID       uint               `json:"id"`
+	Name     string             `json:"name"`
+	Slug     string             `json:"slug"`
+	ParentID *uint              `json:"parent_id"`
+	Position int                `json:"position"`
+	Children []CategoryResponse `json:"children,omitempty"`
+}
+
+type TagRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+	Slug string `json:"slug" binding:"omitempty,max=50"` // default dari name
+}
+
+type TagResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
@@ -0,0 +1,132 @@
+package dto
+
+import "time"
+
+type CheckInRequest struct {
+	Credential string `json:"credential" binding:"required"`
+	Gate       string `json:"gate" binding:"required,max=100"`
+	Admit      int    `json:"admit" binding:"omitempty,min=1"` // default: semua sisa kuota tiket
+}
+
+type CheckInResponse struct {
+	ID        uint      `json:"id,omitempty"`
+	Result    string    `json:"result"`
+	Reason    string    `json:"reason,omitempty"`
+	TicketID  uint      `json:"ticket_id,omitempty"`
+	PassID    *uint     `json:"pass_id,omitempty"`
+	Attendee  string    `json:"attendee,omitempty"` // nama pada attendee pass, untuk dicocokkan petugas
+	EventID   uint      `json:"event_id,omitempty"`
+	Gate      string    `json:"gate"`
+	Admitted  int       `json:"admitted"`
+	Qty       int       `json:"qty,omitempty"`
+	CheckedIn int       `json:"checked_in"`
+	Remaining int       `json:"remaining"`
+	ScannedAt time.Time `json:"scanned_at"`
+}
+
+type CheckInScanResponse struct {
+	ID           uint       `json:"id"`
+	TicketID     uint       `json:"ticket_id"`
+	PassID       *uint      `json:"pass_id,omitempty"`
+	CredentialID string     `json:"credential_id,omitempty"`
+	Gate         string     `json:"gate"`
+	ScannerID    uint       `json:"scanner_id"`
+	ScannerEmail string     `json:"scanner_email,omitempty"`
+	Result       string     `json:"result"`
+	Reason       string     `json:"reason,omitempty"`
+	Admitted     int        `json:"admitted"`
+	ScannedAt    time.Time  `json:"scanned_at"`
+	DeviceID     *string    `json:"device_id,omitempty"` // diisi untuk scan dari sync offline
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNote   string     `json:"review_note,omitempty"`
+}
+
+type GateCountResponse struct {
+	Gate     string `json:"gate"`
+	Admitted int    `json:"admitted"`
+}
+
+type CheckInSummaryResponse struct {
+	EventID    uint                `json:"event_id"`
+	EventName  string              `json:"event_name"`
+	Capacity   int                 `json:"capacity"`
+	Booked     int                 `json:"booked"`     // jumlah orang pada tiket booked
+	CheckedIn  int                 `json:"checked_in"` // jumlah orang yang sudah masuk
+	Remaining  int                 `json:"remaining"`
+	Rejected   int64               `json:"rejected_scans"`
+	Conflicts  int64               `json:"open_conflicts"` // konflik sync offline yang belum ditinjau
+	Gates      []GateCountResponse `json:"gates"`
+	LastScanAt *time.Time          `json:"last_scan_at,omitempty"`
+}
+
+type StaffAssignmentRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type StaffAssignmentResponse struct {
+	UserID     uint      `json:"user_id"`
+	Name       string    `json:"name"`
+	Email      string    `json:"email"`
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+type BundlePassResponse struct {
+	PassID    uint `json:"pass_id"`
+	CheckedIn bool `json:"checked_in"`
+}
+
+type BundleTicketResponse struct {
+	TicketID  uint                 `json:"ticket_id"`
+	Qty       int                  `json:"qty"`
+	CheckedIn int                  `json:"checked_in"`
+	Version   int                  `json:"version"` // kredensial dengan ver lain sudah tidak berlaku
+	Passes    []BundlePassResponse `json:"passes"`
+}
+
+// ScannerBundleResponse berisi semua yang dibutuhkan scanner untuk check-in tanpa koneksi
+type ScannerBundleResponse struct {
+	EventID     uint                        `json:"event_id"`
+	EventName   string                      `json:"event_name"`
+	GeneratedAt time.Time                   `json:"generated_at"`
+	Keys        []SigningKeyResponse        `json:"keys"`
+	Tickets     []BundleTicketResponse      `json:"tickets"`
+	Revoked     []RevokedCredentialResponse `json:"revoked"`
+}
+
+type OfflineScanRequest struct {
+	ScanID     string    `json:"scan_id" binding:"required,max=100"` // ID unik dari device, dipakai agar upload ulang aman
+	Credential string    `json:"credential" binding:"required"`
+	Gate       string    `json:"gate" binding:"required,max=100"`
+	Admit      int       `json:"admit" binding:"omitempty,min=1"`                    // default 1
+	Result     string    `json:"result" binding:"omitempty,oneof=admitted rejected"` // keputusan scanner saat offline
+	Reason     string    `json:"reason" binding:"max=255"`
+	ScannedAt  time.Time `json:"scanned_at" binding:"required"`
+}
+
+type OfflineSyncRequest struct {
+	DeviceID string               `json:"device_id" binding:"required,max=100"`
+	Scans    []OfflineScanRequest `json:"scans" binding:"required,min=1,max=1000,dive"`
+}
+
+type OfflineScanResult struct {
+	ScanID    string `json:"scan_id"`
+	Status    string `json:"status"` // admitted, rejected, conflict, duplicate atau invalid
+	CheckInID uint   `json:"check_in_id,omitempty"`
+	Admitted  int    `json:"admitted"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+type OfflineSyncResponse struct {
+	DeviceID   string              `json:"device_id"`
+	Received   int                 `json:"received"`
+	Admitted   int                 `json:"admitted"`
+	Rejected   int                 `json:"rejected"`
+	Conflicts  int                 `json:"conflicts"`
+	Duplicates int                 `json:"duplicates"`
+	Invalid    int                 `json:"invalid"`
+	Results    []OfflineScanResult `json:"results"`
+}
+
+type CheckInReviewRequest struct {
+	Note string `json:"note" binding:"max=255"`
+}
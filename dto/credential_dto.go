@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+// SigningKeyResponse memakai format JWK (RFC 8037) untuk kunci Ed25519
+type SigningKeyResponse struct {
+	KeyID       string    `json:"kid"`
+	KeyType     string    `json:"kty"`
+	Curve       string    `json:"crv"`
+	X           string    `json:"x"` // public key, base64url
+	Use         string    `json:"use"`
+	Status      string    `json:"status"`
+	ActivatedAt time.Time `json:"activated_at"`
+}
+
+type SigningKeysResponse struct {
+	Keys []SigningKeyResponse `json:"keys"`
+}
+
+type CredentialResponse struct {
+	CredentialID string    `json:"jti"`
+	TicketID     uint      `json:"ticket_id"`
+	PassID       *uint     `json:"pass_id,omitempty"`
+	KeyID        string    `json:"kid"`
+	Version      int       `json:"version"`
+	Token        string    `json:"credential"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type RevokedCredentialResponse struct {
+	CredentialID string    `json:"jti"`
+	TicketID     uint      `json:"ticket_id"`
+	PassID       *uint     `json:"pass_id,omitempty"`
+	EventID      uint      `json:"event_id"`
+	Version      int       `json:"version"`
+	RevokedAt    time.Time `json:"revoked_at"`
+	Reason       string    `json:"reason"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
@@ -1,22 +1,95 @@
 package dto
 
+import "time"
+
 type EventRequest struct {
-	Name        string  `json:"name" binding:"required"`
-	Description string  `json:"description" binding:"required"`
-	Location    string  `json:"location" binding:"required"`
-	DateTime    string  `json:"date_time" binding:"required"`
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description" binding:"required"`
+	// Location opsional jika VenueID diisi; default nama dan kota venue
+	Location string `json:"location" binding:"required_without=VenueID"`
+	VenueID  *uint  `json:"venue_id"`
+	// DateTime/EndDateTime menerima ISO-8601 dengan offset, atau waktu lokal tanpa offset
+	// ("2006-01-02 15:04:05") yang dibaca di Timezone event.
+	// EndDateTime opsional; default DateTime + EVENT_DEFAULT_DURATION (atau durasi lama saat update)
+	DateTime    string `json:"date_time" binding:"required"`
+	EndDateTime string `json:"end_date_time"`
+	// Timezone adalah nama zona IANA (mis. "Asia/Jakarta"); default zona venue, lalu DEFAULT_TIMEZONE
+	Timezone    string  `json:"timezone" binding:"omitempty,timezone"`
 	Capacity    int     `json:"capacity" binding:"required,min=1"`
 	Price       float64 `json:"price" binding:"required,min=0"`
+	HoldMinutes int     `json:"hold_minutes" binding:"omitempty,min=1,max=1440"`
+	// TransfersAllowed default true; nil saat update berarti tidak diubah
+	TransfersAllowed *bool `json:"transfers_allowed"`
+	// CategoryIDs dan Tags (nama bebas; tag baru dibuat otomatis). Saat update, field yang
+	// tidak dikirim berarti tidak diubah dan array kosong menghapus semuanya.
+	CategoryIDs []uint   `json:"category_ids"`
+	Tags        []string `json:"tags" binding:"omitempty,dive,required,max=50"`
 }
 
 type EventResponse struct {
-	ID          uint    `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Location    string  `json:"location"`
-	DateTime    string  `json:"date_time"`
-	Capacity    int     `json:"capacity"`
-	Available   int     `json:"available"`
-	Price       float64 `json:"price"`
-	Status      string  `json:"status"`
+	ID               uint       `json:"id"`
+	Name             string     `json:"name"`
+	Description      string     `json:"description"`
+	Location         string     `json:"location"`
+	DateTime         time.Time  `json:"date_time"` // UTC
+	EndDateTime      time.Time  `json:"end_date_time"`
+	DateTimeLocal    string     `json:"date_time_local"` // ISO-8601 dengan offset zona waktu event
+	EndDateTimeLocal string     `json:"end_date_time_local"`
+	Timezone         string     `json:"timezone"`
+	SeriesID         *uint      `json:"series_id,omitempty"`
+	VenueID          *uint      `json:"venue_id,omitempty"`
+	DistanceKm       *float64   `json:"distance_km,omitempty"` // hanya pada pencarian near
+	Capacity         int        `json:"capacity"`
+	Sold             int        `json:"sold"`
+	Held             int        `json:"held"`
+	Offered          int        `json:"offered"` // dicadangkan untuk tawaran waitlist
+	Available        int        `json:"available"`
+	Price            float64    `json:"price"`
+	Status           string     `json:"status"`
+	StatusOverride   bool       `json:"status_override"`
+	StatusChangedAt  *time.Time `json:"status_changed_at"`
+	HoldMinutes      int        `json:"hold_minutes"`
+	TransfersAllowed bool       `json:"transfers_allowed"`
+
+	Venue       *VenueResponse       `json:"venue,omitempty"`
+	Categories  []CategoryResponse   `json:"categories"`
+	Tags        []TagResponse        `json:"tags"`
+	TicketTypes []TicketTypeResponse `json:"ticket_types,omitempty"` // ketersediaan per tier
+}
+
+// EventStatusRequest dipakai admin untuk memaksa status event (status) atau
+// mengembalikannya ke jadwal otomatis (auto)
+type EventStatusRequest struct {
+	Status string `json:"status" binding:"omitempty,oneof=upcoming ongoing completed"`
+	Auto   bool   `json:"auto"`
+}
+
+// EventSearchQuery adalah query string GET /events. Semua filter opsional dan digabung
+// dengan AND; daftar dipisah koma (mis. status=upcoming,ongoing). Lihat README untuk grammar lengkap.
+type EventSearchQuery struct {
+	Search    string   `form:"search"`
+	From      string   `form:"from"` // tanggal (YYYY-MM-DD) atau ISO-8601; dibandingkan dengan waktu mulai
+	To        string   `form:"to"`   // tanggal bersifat inklusif sampai akhir hari
+	MinPrice  *float64 `form:"min_price" binding:"omitempty,min=0"`
+	MaxPrice  *float64 `form:"max_price" binding:"omitempty,min=0"`
+	Status    string   `form:"status"`
+	VenueID   string   `form:"venue_id"`
+	City      string   `form:"city"`
+	Category  string   `form:"category"` // slug kategori; termasuk subkategorinya
+	Tag       string   `form:"tag"`      // slug tag
+	Available *bool    `form:"available"`
+	Near      string   `form:"near"` // "lat,lng"; hanya event di venue dengan koordinat
+	RadiusKm  *float64 `form:"radius_km" binding:"omitempty,gt=0,max=500"`
+	Sort      string   `form:"sort"` // date, price, popularity, name, created, distance; awalan "-" untuk descending
+}
+
+// EventSearchParams adalah daftar parameter yang boleh dipakai di GET /events;
+// parameter lain ditolak agar salah ketik tidak diam-diam diabaikan
+var EventSearchParams = []string{"page", "limit", "search", "from", "to", "min_price", "max_price",
+	"status", "venue_id", "city", "category", "tag", "available", "near", "radius_km", "sort"}
+
+// FacetCount adalah jumlah event untuk satu nilai facet
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
 }
@@ -0,0 +1,34 @@
+package dto
+
+import "time"
+
+type CartItemRequest struct {
+	EventID      uint                   `json:"event_id" binding:"required"`
+	TicketTypeID uint                   `json:"ticket_type_id"`
+	Qty          int                    `json:"qty" binding:"required,min=1"`
+	Answers      map[string]interface{} `json:"answers"` // jawaban formulir registrasi event
+}
+
+type OrderItemResponse struct {
+	ID           uint              `json:"id"`
+	EventID      uint              `json:"event_id"`
+	EventName    string            `json:"event_name"`
+	TicketTypeID *uint             `json:"ticket_type_id,omitempty"`
+	TicketType   string            `json:"ticket_type,omitempty"`
+	Qty          int               `json:"qty"`
+	UnitPrice    float64           `json:"unit_price"`
+	LineTotal    float64           `json:"line_total"`
+	Answers      map[string]string `json:"answers,omitempty"`
+}
+
+type OrderResponse struct {
+	ID        uint                `json:"id"`
+	Status    string              `json:"status"`
+	Items     []OrderItemResponse `json:"items"`
+	TotalQty  int                 `json:"total_qty"`
+	Total     float64             `json:"total"`
+	ExpiresAt *time.Time          `json:"expires_at,omitempty"`
+	SeriesID  *uint               `json:"series_id,omitempty"` // order series pass
+	Tickets   []TicketResponse    `json:"tickets,omitempty"`
+	Payment   *PaymentResponse    `json:"payment,omitempty"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+type PromoCodeRequest struct {
+	Code           string     `json:"code" binding:"required,max=50"`
+	DiscountType   string     `json:"discount_type" binding:"required,oneof=percent fixed"`
+	Value          float64    `json:"value" binding:"required,gt=0"`
+	ValidFrom      *time.Time `json:"valid_from"`
+	ValidUntil     *time.Time `json:"valid_until"`
+	MaxRedemptions int        `json:"max_redemptions" binding:"min=0"`
+	MaxPerUser     int        `json:"max_per_user" binding:"min=0"`
+	MinQty         int        `json:"min_qty" binding:"omitempty,min=1"`
+	Active         *bool      `json:"active"`          // default true
+	EventIDs       []uint     `json:"event_ids"`       // kosong = semua event
+	TicketTypeIDs  []uint     `json:"ticket_type_ids"` // kosong = semua tier
+}
+
+type PromoCodeResponse struct {
+	ID             uint       `json:"id"`
+	Code           string     `json:"code"`
+	DiscountType   string     `json:"discount_type"`
+	Value          float64    `json:"value"`
+	ValidFrom      *time.Time `json:"valid_from"`
+	ValidUntil     *time.Time `json:"valid_until"`
+	MaxRedemptions int        `json:"max_redemptions"`
+	MaxPerUser     int        `json:"max_per_user"`
+	Redeemed       int        `json:"redeemed"`
+	MinQty         int        `json:"min_qty"`
+	Active         bool       `json:"active"`
+	EventIDs       []uint     `json:"event_ids"`
+	TicketTypeIDs  []uint     `json:"ticket_type_ids"`
+}
@@ -0,0 +1,51 @@
+package dto
+
+import "time"
+
+type CancellationRuleRequest struct {
+	HoursBefore   int     `json:"hours_before" binding:"min=0"`
+	RefundPercent float64 `json:"refund_percent" binding:"min=0,max=100"`
+}
+
+type CancellationPolicyRequest struct {
+	Rules []CancellationRuleRequest `json:"rules" binding:"dive"`
+}
+
+type CancellationRuleResponse struct {
+	HoursBefore   int     `json:"hours_before"`
+	RefundPercent float64 `json:"refund_percent"`
+}
+
+type CancellationPolicyResponse struct {
+	EventID uint                       `json:"event_id"`
+	Rules   []CancellationRuleResponse `json:"rules"`
+}
+
+type CancelTicketRequest struct {
+	Reason string `json:"reason"`
+}
+
+type AdminRefundRequest struct {
+	Amount float64 `json:"amount" binding:"required,gt=0"`
+	Reason string  `json:"reason" binding:"required"`
+}
+
+type RefundPreviewResponse struct {
+	TicketID         uint    `json:"ticket_id"`
+	PaidAmount       float64 `json:"paid_amount"`
+	AlreadyRefunded  float64 `json:"already_refunded"`
+	HoursBeforeEvent float64 `json:"hours_before_event"`
+	RefundPercent    float64 `json:"refund_percent"`
+	RefundAmount     float64 `json:"refund_amount"`
+}
+
+type RefundResponse struct {
+	ID        uint      `json:"id"`
+	TicketID  uint      `json:"ticket_id"`
+	Amount    float64   `json:"amount"`
+	Percent   float64   `json:"percent"`
+	Reason    string    `json:"reason"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,62 @@
+package dto
+
+import "time"
+
+type RegistrationQuestionRequest struct {
+	Key       string   `json:"key" binding:"required,max=50"`
+	Label     string   `json:"label" binding:"required,max=255"`
+	Type      string   `json:"type" binding:"required,oneof=text number select checkbox"`
+	Required  bool     `json:"required"`
+	Options   []string `json:"options"` // wajib untuk tipe select
+	MinLength *int     `json:"min_length" binding:"omitempty,min=0"`
+	MaxLength *int     `json:"max_length" binding:"omitempty,min=1"`
+	Min       *float64 `json:"min"`
+	Max       *float64 `json:"max"`
+	Pattern   string   `json:"pattern" binding:"max=255"`
+}
+
+// RegistrationFormRequest mengganti seluruh formulir event; urutan array menjadi urutan tampil
+type RegistrationFormRequest struct {
+	Questions []RegistrationQuestionRequest `json:"questions" binding:"dive"`
+}
+
+type RegistrationQuestionResponse struct {
+	ID        uint     `json:"id"`
+	Key       string   `json:"key"`
+	Label     string   `json:"label"`
+	Type      string   `json:"type"`
+	Required  bool     `json:"required"`
+	Options   []string `json:"options,omitempty"`
+	MinLength *int     `json:"min_length,omitempty"`
+	MaxLength *int     `json:"max_length,omitempty"`
+	Min       *float64 `json:"min,omitempty"`
+	Max       *float64 `json:"max,omitempty"`
+	Pattern   string   `json:"pattern,omitempty"`
+	Position  int      `json:"position"`
+}
+
+type RegistrationFormResponse struct {
+	EventID   uint                           `json:"event_id"`
+	Questions []RegistrationQuestionResponse `json:"questions"`
+}
+
+type AttendeeReportRow struct {
+	TicketID      uint              `json:"ticket_id"`
+	PassSeq       int               `json:"pass_seq"`
+	AttendeeName  string            `json:"attendee_name"`
+	AttendeeEmail string            `json:"attendee_email"`
+	BuyerName     string            `json:"buyer_name"`
+	BuyerEmail    string            `json:"buyer_email"`
+	TicketType    string            `json:"ticket_type,omitempty"`
+	BookingDate   time.Time         `json:"booking_date"`
+	CheckedIn     bool              `json:"checked_in"`
+	Answers       map[string]string `json:"answers"` // jawaban pembeli per key pertanyaan
+}
+
+type AttendeeReportResponse struct {
+	EventID   uint                           `json:"event_id"`
+	EventName string                         `json:"event_name"`
+	Timezone  string                         `json:"timezone"` // zona waktu booking_date di file ekspor
+	Questions []RegistrationQuestionResponse `json:"questions"`
+	Rows      []AttendeeReportRow            `json:"rows"`
+}
@@ -1,18 +1,58 @@
 package dto
 
 type SummaryReportResponse struct {
-	TotalEvents  int     `json:"total_events"`
-	TotalTickets int     `json:"total_tickets"`
-	TotalRevenue float64 `json:"total_revenue"`
-	Upcoming     int     `json:"upcoming_events"`
-	Ongoing      int     `json:"ongoing_events"`
-	Completed    int     `json:"completed_events"`
+	TotalEvents   int     `json:"total_events"`
+	TotalTickets  int     `json:"total_tickets"`
+	GrossRevenue  float64 `json:"gross_revenue"`
+	TotalRefunded float64 `json:"total_refunded"`
+	TotalRevenue  float64 `json:"total_revenue"` // pendapatan bersih setelah refund
+	Upcoming      int     `json:"upcoming_events"`
+	Ongoing       int     `json:"ongoing_events"`
+	Completed     int     `json:"completed_events"`
+
+	Categories []CategoryReportResponse `json:"categories"`
+}
+
+// CategoryReportResponse adalah rekap per kategori; event di subkategori ikut dihitung
+// di setiap kategori induknya
+type CategoryReportResponse struct {
+	ID          uint    `json:"id"`
+	Name        string  `json:"name"`
+	Slug        string  `json:"slug"`
+	ParentID    *uint   `json:"parent_id"`
+	Events      int     `json:"events"`
+	TicketsSold int     `json:"tickets_sold"`
+	Revenue     float64 `json:"revenue"`
 }
 
 type EventReportResponse struct {
-	EventName     string  `json:"event_name"`
-	TotalCapacity int     `json:"total_capacity"`
-	TicketsSold   int     `json:"tickets_sold"`
-	Revenue       float64 `json:"revenue"`
-	OccupancyRate float64 `json:"occupancy_rate"`
+	EventName     string   `json:"event_name"`
+	TotalCapacity int      `json:"total_capacity"`
+	TicketsSold   int      `json:"tickets_sold"`
+	Revenue       float64  `json:"revenue"`
+	OccupancyRate float64  `json:"occupancy_rate"`
+	Categories    []string `json:"categories"` // nama kategori yang ditautkan langsung
+	Tags          []string `json:"tags"`
+
+	TicketTypes []TicketTypeReportResponse `json:"ticket_types,omitempty"`
+}
+
+type TicketTypeReportResponse struct {
+	Name        string  `json:"name"`
+	Price       float64 `json:"price"`
+	Quota       int     `json:"quota"`
+	TicketsSold int     `json:"tickets_sold"`
+	Revenue     float64 `json:"revenue"`
+}
+
+type PromoCodeReportResponse struct {
+	Code           string  `json:"code"`
+	DiscountType   string  `json:"discount_type"`
+	Value          float64 `json:"value"`
+	Active         bool    `json:"active"`
+	Redemptions    int     `json:"redemptions"`
+	MaxRedemptions int     `json:"max_redemptions"`
+	UniqueUsers    int     `json:"unique_users"`
+	TotalDiscount  float64 `json:"total_discount"`
+	Revenue        float64 `json:"revenue"` // pendapatan dari tiket berbayar yang memakai kode
 }
@@ -0,0 +1,42 @@
+package dto
+
+// SeatMapRequest adalah format JSON import denah kursi sebuah event
+type SeatMapRequest struct {
+	Sections []SeatSectionRequest `json:"sections" binding:"required,min=1,dive"`
+}
+
+type SeatSectionRequest struct {
+	Name         string           `json:"name" binding:"required"`
+	TicketTypeID uint             `json:"ticket_type_id"`
+	Rows         []SeatRowRequest `json:"rows" binding:"required,min=1,dive"`
+}
+
+type SeatRowRequest struct {
+	Name  string   `json:"name" binding:"required"`
+	Seats []string `json:"seats" binding:"required,min=1"`
+}
+
+type SeatMapResponse struct {
+	EventID   uint                  `json:"event_id"`
+	Total     int                   `json:"total"`
+	Available int                   `json:"available"`
+	Sections  []SeatSectionResponse `json:"sections"`
+}
+
+type SeatSectionResponse struct {
+	Name string            `json:"name"`
+	Rows []SeatRowResponse `json:"rows"`
+}
+
+type SeatRowResponse struct {
+	Name  string         `json:"name"`
+	Seats []SeatResponse `json:"seats"`
+}
+
+type SeatResponse struct {
+	ID           uint   `json:"id"`
+	Label        string `json:"label"`
+	Number       string `json:"number"`
+	TicketTypeID *uint  `json:"ticket_type_id,omitempty"`
+	Status       string `json:"status"`
+}
@@ -0,0 +1,43 @@
+package dto
+
+type SeriesRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description" binding:"required"`
+	Location    string `json:"location" binding:"required_without=VenueID"`
+	VenueID     *uint  `json:"venue_id"` // setiap kejadian dicek terhadap kapasitas dan jadwal venue
+	// DateTime adalah waktu mulai kejadian pertama (DTSTART), format sama dengan EventRequest
+	DateTime        string   `json:"date_time" binding:"required"`
+	DurationMinutes int      `json:"duration_minutes" binding:"omitempty,min=1,max=10080"`
+	Timezone        string   `json:"timezone" binding:"omitempty,timezone"`
+	RRule           string   `json:"rrule" binding:"required"`
+	ExDates         []string `json:"exdates"` // tanggal ("2006-01-02") atau waktu mulai kejadian yang dilewati
+	Capacity        int      `json:"capacity" binding:"required,min=1"`
+	Price           float64  `json:"price" binding:"min=0"`
+	HoldMinutes     int      `json:"hold_minutes" binding:"omitempty,min=1,max=1440"`
+	// TransfersAllowed default true
+	TransfersAllowed *bool    `json:"transfers_allowed"`
+	PassPrice        *float64 `json:"pass_price" binding:"omitempty,min=0"`
+}
+
+type SeriesResponse struct {
+	ID               uint            `json:"id"`
+	Name             string          `json:"name"`
+	Description      string          `json:"description"`
+	Location         string          `json:"location"`
+	Timezone         string          `json:"timezone"`
+	RRule            string          `json:"rrule"`
+	ExDates          []string        `json:"exdates"`
+	DurationMinutes  int             `json:"duration_minutes"`
+	Capacity         int             `json:"capacity"`
+	Price            float64         `json:"price"`
+	HoldMinutes      int             `json:"hold_minutes"`
+	TransfersAllowed bool            `json:"transfers_allowed"`
+	PassPrice        *float64        `json:"pass_price"`
+	VenueID          *uint           `json:"venue_id,omitempty"`
+	Occurrences      []EventResponse `json:"occurrences,omitempty"`
+}
+
+type SeriesPassRequest struct {
+	Qty     int                    `json:"qty" binding:"required,min=1"`
+	Answers map[string]interface{} `json:"answers"` // dipakai untuk formulir registrasi setiap sesi
+}
@@ -1,25 +1,73 @@
 package dto
 
+import "time"
+
 type TicketRequest struct {
-	EventID uint `json:"event_id" binding:"required"`
-	Qty     int  `json:"qty" binding:"required"`
+	EventID      uint   `json:"event_id" binding:"required"`
+	TicketTypeID uint   `json:"ticket_type_id"` // wajib jika event memiliki ticket types
+	Qty          int    `json:"qty" binding:"omitempty,min=1"`
+	SeatIDs      []uint `json:"seat_ids"` // wajib untuk event dengan reserved seating
+	PromoCode    string `json:"promo_code"`
+	// Answers berisi jawaban formulir registrasi event, per key pertanyaan
+	Answers map[string]interface{} `json:"answers"`
 }
 
 type PaymentUpdateResponse struct {
-	ID            uint   `json:"id"`
-	Status        string `json:"status"`
-	PaymentStatus string `json:"payment_status"`
+	ID            uint             `json:"id"`
+	Status        string           `json:"status"`
+	PaymentStatus string           `json:"payment_status"`
+	Payment       *PaymentResponse `json:"payment,omitempty"`
 }
 
 type TicketResponse struct {
-	ID            uint    `json:"id"`
-	EventName     string  `json:"event_name"`
-	EventDate     string  `json:"event_date"`
-	Location      string  `json:"location"`
-	Price         float64 `json:"price"`
-	Status        string  `json:"status"`
-	PaymentStatus string  `json:"payment_status"`
-	BookingDate   string  `json:"booking_date"`
-	Qty           int     `json:"quantity"`  // Menyertakan Quantity
-	SubTotal      float64 `json:"sub_total"` // Menyertakan SubTotal
+	ID               uint                   `json:"id"`
+	OrderID          *uint                  `json:"order_id,omitempty"`
+	EventName        string                 `json:"event_name"`
+	EventDate        time.Time              `json:"event_date"` // UTC
+	EventDateLocal   string                 `json:"event_date_local"`
+	Location         string                 `json:"location"`
+	TicketType       string                 `json:"ticket_type,omitempty"`
+	Price            float64                `json:"price"`
+	Status           string                 `json:"status"`
+	PaymentStatus    string                 `json:"payment_status"`
+	BookingDate      time.Time              `json:"booking_date"` // UTC
+	BookingDateLocal string                 `json:"booking_date_local"`
+	Qty              int                    `json:"quantity"`       // Menyertakan Quantity
+	OriginalTotal    float64                `json:"original_total"` // total sebelum diskon
+	Discount         float64                `json:"discount"`
+	PromoCode        string                 `json:"promo_code,omitempty"`
+	SubTotal         float64                `json:"sub_total"`            // Menyertakan SubTotal (total akhir)
+	ExpiresAt        *time.Time             `json:"expires_at,omitempty"` // Batas waktu pembayaran untuk countdown
+	CheckedIn        int                    `json:"checked_in"`           // jumlah orang yang sudah masuk gerbang
+	Seats            []SeatResponse         `json:"seats,omitempty"`
+	Passes           []AttendeePassResponse `json:"passes,omitempty"`
+	Answers          map[string]string      `json:"answers,omitempty"`
+}
+
+type PaymentResponse struct {
+	ID            uint       `json:"id"`
+	Provider      string     `json:"provider"`
+	ProviderRef   string     `json:"provider_ref"`
+	Amount        float64    `json:"amount"`
+	Currency      string     `json:"currency"`
+	Status        string     `json:"status"`
+	FailureReason string     `json:"failure_reason,omitempty"`
+	CapturedAt    *time.Time `json:"captured_at,omitempty"`
+}
+
+type AttendeePassRequest struct {
+	Name   string            `json:"name" binding:"max=255"`
+	Email  string            `json:"email" binding:"omitempty,email,max=255"`
+	Fields map[string]string `json:"fields"` // data tambahan bebas, mis. ukuran kaos
+}
+
+type AttendeePassResponse struct {
+	ID          uint              `json:"id"`
+	Seq         int               `json:"seq"`
+	Name        string            `json:"name"`
+	Email       string            `json:"email"`
+	Fields      map[string]string `json:"fields,omitempty"`
+	CheckedIn   bool              `json:"checked_in"`
+	CheckedInAt *time.Time        `json:"checked_in_at,omitempty"`
+	Gate        string            `json:"gate,omitempty"`
 }
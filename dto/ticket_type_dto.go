@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+type TicketTypeRequest struct {
+	Name        string     `json:"name" binding:"required"`
+	Price       float64    `json:"price" binding:"min=0"`
+	Quota       int        `json:"quota" binding:"required,min=1"`
+	MinPerOrder int        `json:"min_per_order" binding:"omitempty,min=1"`
+	MaxPerOrder int        `json:"max_per_order" binding:"omitempty,min=1"`
+	SalesStart  *time.Time `json:"sales_start"`
+	SalesEnd    *time.Time `json:"sales_end"`
+}
+
+type TicketTypeResponse struct {
+	ID          uint       `json:"id"`
+	EventID     uint       `json:"event_id"`
+	Name        string     `json:"name"`
+	Price       float64    `json:"price"`
+	Quota       int        `json:"quota"`
+	Sold        int        `json:"sold"`
+	Held        int        `json:"held"`
+	Available   int        `json:"available"`
+	MinPerOrder int        `json:"min_per_order"`
+	MaxPerOrder int        `json:"max_per_order"`
+	SalesStart  *time.Time `json:"sales_start"`
+	SalesEnd    *time.Time `json:"sales_end"`
+	OnSale      bool       `json:"on_sale"`
+}
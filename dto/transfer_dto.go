@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+type TransferRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type TransferResponse struct {
+	ID          uint       `json:"id"`
+	TicketID    uint       `json:"ticket_id"`
+	FromUserID  uint       `json:"from_user_id"`
+	FromEmail   string     `json:"from_email,omitempty"`
+	ToEmail     string     `json:"to_email"`
+	ToUserID    *uint      `json:"to_user_id,omitempty"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+type VenueRequest struct {
+	Name    string `json:"name" binding:"required,max=191"`
+	Address string `json:"address" binding:"required"`
+	City    string `json:"city" binding:"required,max=100"`
+	Country string `json:"country" binding:"required,max=100"`
+	// Latitude/Longitude opsional, tetapi harus dikirim berpasangan
+	Latitude           *float64 `json:"latitude" binding:"required_with=Longitude,omitempty,min=-90,max=90"`
+	Longitude          *float64 `json:"longitude" binding:"required_with=Latitude,omitempty,min=-180,max=180"`
+	MaxCapacity        int      `json:"max_capacity" binding:"required,min=1"`
+	Timezone           string   `json:"timezone" binding:"omitempty,timezone"` // default DEFAULT_TIMEZONE
+	AccessibilityNotes string   `json:"accessibility_notes"`
+}
+
+type VenueResponse struct {
+	ID                 uint     `json:"id"`
+	Name               string   `json:"name"`
+	Address            string   `json:"address"`
+	City               string   `json:"city"`
+	Country            string   `json:"country"`
+	Latitude           *float64 `json:"latitude"`
+	Longitude          *float64 `json:"longitude"`
+	MaxCapacity        int      `json:"max_capacity"`
+	Timezone           string   `json:"timezone"`
+	AccessibilityNotes string   `json:"accessibility_notes"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+type WaitlistJoinRequest struct {
+	Qty int `json:"qty" binding:"required,min=1"`
+}
+
+// WaitlistAcceptRequest melengkapi pembelian dari tawaran waitlist; qty mengikuti entry
+type WaitlistAcceptRequest struct {
+	TicketTypeID uint                   `json:"ticket_type_id"`
+	SeatIDs      []uint                 `json:"seat_ids"`
+	PromoCode    string                 `json:"promo_code"`
+	Answers      map[string]interface{} `json:"answers"`
+}
+
+type WaitlistEntryResponse struct {
+	ID             uint       `json:"id"`
+	EventID        uint       `json:"event_id"`
+	UserID         uint       `json:"user_id"`
+	UserEmail      string     `json:"user_email,omitempty"`
+	Qty            int        `json:"qty"`
+	Status         string     `json:"status"`
+	Position       int64      `json:"position,omitempty"` // hanya untuk entry yang masih menunggu
+	OfferedAt      *time.Time `json:"offered_at,omitempty"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty"`
+	TicketID       *uint      `json:"ticket_id,omitempty"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AttendeePass adalah satu orang pada tiket multi-qty. Tiket induk tetap menyimpan
+// pembayaran; tiap pass punya data peserta, kredensial dan status check-in sendiri.
+type AttendeePass struct {
+	gorm.Model
+	TicketID    uint              `gorm:"not null;uniqueIndex:idx_pass_ticket_seq" json:"ticket_id"`
+	EventID     uint              `gorm:"not null;index" json:"event_id"`
+	Seq         int               `gorm:"not null;uniqueIndex:idx_pass_ticket_seq" json:"seq"` // 1..Qty
+	Name        string            `gorm:"size:255" json:"name"`
+	Email       string            `gorm:"size:255;index" json:"email"`
+	Fields      map[string]string `gorm:"serializer:json;type:text" json:"fields,omitempty"`
+	CheckedInAt *time.Time        `json:"checked_in_at"`
+	CheckInGate string            `gorm:"size:100" json:"check_in_gate,omitempty"`
+}
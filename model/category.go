@@ -0,0 +1,21 @@
+package model
+
+import "gorm.io/gorm"
+
+// Category adalah node pohon kategori event (mis. Music > Jazz). ParentID nil berarti kategori
+// akar. Event di subkategori ikut terhitung di kategori induknya pada filter dan laporan.
+type Category struct {
+	gorm.Model
+	Name     string     `gorm:"size:100;not null" json:"name"`
+	Slug     string     `gorm:"size:100;not null;uniqueIndex" json:"slug"`
+	ParentID *uint      `gorm:"index" json:"parent_id"`
+	Position int        `gorm:"not null;default:0" json:"position"` // urutan di antara saudara
+	Children []Category `gorm:"foreignKey:ParentID" json:"children,omitempty"`
+}
+
+// Tag adalah label bebas untuk event (mis. "outdoor", "family-friendly")
+type Tag struct {
+	gorm.Model
+	Name string `gorm:"size:50;not null" json:"name"`
+	Slug string `gorm:"size:50;not null;uniqueIndex" json:"slug"`
+}
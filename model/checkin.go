@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StaffAssignment memberi user ber-role staff akses check-in untuk satu event
+type StaffAssignment struct {
+	gorm.Model
+	EventID uint `gorm:"not null;uniqueIndex:idx_staff_event_user" json:"event_id"`
+	UserID  uint `gorm:"not null;uniqueIndex:idx_staff_event_user" json:"user_id"`
+	User    User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+type CheckInResult string
+
+const (
+	CheckInAdmitted CheckInResult = "admitted"
+	CheckInRejected CheckInResult = "rejected"
+	CheckInConflict CheckInResult = "conflict" // scan offline yang bentrok saat disinkronkan, perlu ditinjau admin
+)
+
+// CheckIn mencatat setiap scan di gerbang, baik yang diterima maupun yang ditolak.
+// Scan dari scanner offline membawa DeviceID dan ClientScanID agar upload ulang tidak tercatat dua kali.
+type CheckIn struct {
+	gorm.Model
+	TicketID     uint          `gorm:"not null;index" json:"ticket_id"`
+	EventID      uint          `gorm:"not null;index" json:"event_id"`
+	PassID       *uint         `gorm:"index" json:"pass_id"` // diisi jika yang dipindai kredensial attendee pass
+	CredentialID string        `gorm:"size:32;index" json:"credential_id"`
+	Gate         string        `gorm:"size:100;not null" json:"gate"`
+	ScannerID    uint          `gorm:"not null;index" json:"scanner_id"` // user staff/admin yang melakukan scan
+	Scanner      User          `gorm:"foreignKey:ScannerID" json:"-"`
+	Admitted     int           `gorm:"not null;default:0" json:"admitted"` // jumlah orang yang masuk pada scan ini
+	Result       CheckInResult `gorm:"type:enum('admitted','rejected','conflict');not null" json:"result"`
+	Reason       string        `gorm:"size:255" json:"reason,omitempty"`
+	ScannedAt    time.Time     `gorm:"not null;index" json:"scanned_at"`
+	DeviceID     *string       `gorm:"size:100;uniqueIndex:idx_checkin_device_scan" json:"device_id,omitempty"`
+	ClientScanID *string       `gorm:"size:100;uniqueIndex:idx_checkin_device_scan" json:"client_scan_id,omitempty"`
+	ReviewedAt   *time.Time    `json:"reviewed_at,omitempty"`
+	ReviewedBy   *uint         `json:"reviewed_by,omitempty"`
+	ReviewNote   string        `gorm:"size:255" json:"review_note,omitempty"`
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SigningKeyStatus string
+
+const (
+	SigningKeyActive   SigningKeyStatus = "active"
+	SigningKeyPrevious SigningKeyStatus = "previous"
+	SigningKeyRetired  SigningKeyStatus = "retired"
+)
+
+// SigningKey adalah pasangan kunci Ed25519 untuk menandatangani kredensial tiket.
+// Hanya satu kunci yang aktif; kunci sebelumnya tetap dipublikasikan agar
+// kredensial lama masih bisa diverifikasi setelah rotasi.
+type SigningKey struct {
+	gorm.Model
+	KeyID       string           `gorm:"size:32;not null;uniqueIndex" json:"kid"`
+	PublicKey   string           `gorm:"size:64;not null" json:"public_key"` // base64
+	PrivateKey  string           `gorm:"size:128;not null" json:"-"`         // base64, jangan pernah dikirim ke klien
+	Status      SigningKeyStatus `gorm:"type:enum('active','previous','retired');default:'active';index" json:"status"`
+	ActivatedAt time.Time        `json:"activated_at"`
+	RetiredAt   *time.Time       `json:"retired_at"`
+}
+
+// TicketCredential adalah kredensial yang pernah diterbitkan untuk tiket. Kredensial
+// yang dicabut (pembatalan, transfer) masuk ke daftar revokasi untuk scanner.
+type TicketCredential struct {
+	gorm.Model
+	CredentialID string     `gorm:"size:32;not null;uniqueIndex" json:"jti"`
+	TicketID     uint       `gorm:"not null;index" json:"ticket_id"`
+	PassID       *uint      `gorm:"index" json:"pass_id"` // nil untuk kredensial tiket (seluruh qty)
+	EventID      uint       `gorm:"not null;index" json:"event_id"`
+	KeyID        string     `gorm:"size:32;not null" json:"kid"`
+	Version      int        `gorm:"not null" json:"version"`
+	Token        string     `gorm:"type:text;not null" json:"token"`
+	ExpiresAt    time.Time  `gorm:"index" json:"expires_at"`
+	RevokedAt    *time.Time `gorm:"index" json:"revoked_at"`
+	RevokeReason string     `gorm:"size:100" json:"revoke_reason,omitempty"`
+}
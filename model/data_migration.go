@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// Nama migrasi data satu kali yang dicatat di tabel data_migrations
+const (
+	// InventoryCountersMigration dicatat saat kolom sold/held ditambahkan ke database lama;
+	// tiket yang dibuat sebelum waktu ini masih mengurangi kapasitas event secara langsung
+	InventoryCountersMigration = "inventory_counters"
+	// LegacyCapacityMigration dicatat setelah kapasitas lama dipulihkan
+	LegacyCapacityMigration = "restore_legacy_capacity"
+)
+
+// DataMigration menandai migrasi data yang hanya boleh berjalan sekali
+type DataMigration struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"size:100;not null;uniqueIndex" json:"name"`
+	AppliedAt time.Time `gorm:"not null" json:"applied_at"`
+}
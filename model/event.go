@@ -1,6 +1,10 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type EventStatus string
 
@@ -12,12 +16,53 @@ const (
 
 type Event struct {
 	gorm.Model
-	Name        string      `gorm:"unique;not null" json:"name"`
-	Description string      `gorm:"not null" json:"description"`
-	Location    string      `gorm:"not null" json:"location"`
-	DateTime    string      `gorm:"not null" json:"date_time"` // Format: "2006-01-02 15:04:05"
-	Capacity    int         `gorm:"not null;check:capacity > 0" json:"capacity"`
-	Price       float64     `gorm:"not null;check:price >= 0" json:"price"`
-	Status      EventStatus `gorm:"type:enum('upcoming','ongoing','completed');default:'upcoming'" json:"status"`
-	Tickets     []Ticket    `json:"tickets,omitempty"`
+	// Name tidak lagi unik: kejadian dalam satu seri memakai nama yang sama
+	Name        string `gorm:"size:191;not null" json:"name"`
+	Description string `gorm:"not null" json:"description"`
+	Location    string `gorm:"not null" json:"location"`
+	// StartsAt dan EndsAt disimpan sebagai DATETIME; Timezone adalah zona IANA (mis. "Asia/Jakarta")
+	// yang dipakai untuk membaca input dan menampilkan waktu lokal event
+	StartsAt    time.Time `gorm:"index;index:idx_events_status_starts,priority:2" json:"starts_at"`
+	EndsAt      time.Time `gorm:"index" json:"ends_at"`
+	Timezone    string    `gorm:"size:64;not null;default:'UTC'" json:"timezone"`
+	Capacity    int       `gorm:"not null;check:capacity > 0" json:"capacity"`
+	Sold        int       `gorm:"not null;default:0" json:"sold"`    // qty tiket yang sudah dibayar
+	Held        int       `gorm:"not null;default:0" json:"held"`    // qty tiket yang menunggu pembayaran
+	Offered     int       `gorm:"not null;default:0" json:"offered"` // qty yang dicadangkan untuk tawaran waitlist
+	HoldMinutes int       `gorm:"not null;default:15" json:"hold_minutes"`
+	// TransfersAllowed memakai pointer agar nilai false tetap tersimpan (default kolom true)
+	TransfersAllowed *bool       `gorm:"not null;default:true" json:"transfers_allowed"`
+	Price            float64     `gorm:"not null;index;check:price >= 0" json:"price"`
+	Status           EventStatus `gorm:"type:enum('upcoming','ongoing','completed');default:'upcoming';index:idx_events_status_starts,priority:1" json:"status"`
+	// StatusOverride true berarti status diatur manual oleh admin dan tidak diubah scheduler
+	StatusOverride  bool       `gorm:"not null;default:false" json:"status_override"`
+	StatusChangedAt *time.Time `json:"status_changed_at"`
+	SeriesID        *uint      `gorm:"index" json:"series_id"` // nil untuk event tunggal
+	VenueID         *uint      `gorm:"index" json:"venue_id"`  // nil jika event hanya punya Location teks
+	Venue           *Venue     `json:"venue,omitempty"`
+	Categories      []Category `gorm:"many2many:event_categories" json:"categories,omitempty"`
+	Tags            []Tag      `gorm:"many2many:event_tags" json:"tags,omitempty"`
+	// DistanceKm hanya diisi pada pencarian near (jarak ke venue), tidak disimpan
+	DistanceKm  *float64               `gorm:"-" json:"distance_km,omitempty"`
+	Tickets     []Ticket               `json:"tickets,omitempty"`
+	TicketTypes []TicketType           `json:"ticket_types,omitempty"`
+	Questions   []RegistrationQuestion `json:"questions,omitempty"`
+}
+
+// AllowsTransfers mengembalikan apakah tiket event boleh dipindahtangankan
+func (e *Event) AllowsTransfers() bool {
+	return e.TransfersAllowed == nil || *e.TransfersAllowed
+}
+
+// Available mengembalikan sisa kuota; Capacity sendiri tidak pernah diubah oleh transaksi tiket
+func (e *Event) Available() int {
+	return e.Capacity - e.Sold - e.Held - e.Offered
+}
+
+// TimeLocation mengembalikan zona waktu event, atau UTC jika Timezone kosong/tidak dikenal
+func (e *Event) TimeLocation() *time.Location {
+	if loc, err := time.LoadLocation(e.Timezone); err == nil {
+		return loc
+	}
+	return time.UTC
 }
@@ -0,0 +1,26 @@
+package model
+
+import "gorm.io/gorm"
+
+// EventSeries adalah template event berulang (workshop mingguan, festival beberapa hari).
+// Setiap kejadian dari RRule disimpan sebagai Event biasa dengan SeriesID, sehingga
+// tiket, kursi, check-in dan laporan tetap bekerja per kejadian.
+type EventSeries struct {
+	gorm.Model
+	Name            string   `gorm:"size:191;not null" json:"name"`
+	Description     string   `gorm:"not null" json:"description"`
+	Location        string   `gorm:"not null" json:"location"`
+	Timezone        string   `gorm:"size:64;not null;default:'UTC'" json:"timezone"`
+	RRule           string   `gorm:"size:255;not null" json:"rrule"`           // subset RFC 5545, mis. FREQ=WEEKLY;BYDAY=TU;COUNT=8
+	ExDates         []string `gorm:"serializer:json;type:text" json:"exdates"` // tanggal/waktu lokal yang dikecualikan
+	DurationMinutes int      `gorm:"not null;check:duration_minutes > 0" json:"duration_minutes"`
+	Capacity        int      `gorm:"not null;check:capacity > 0" json:"capacity"`
+	Price           float64  `gorm:"not null;check:price >= 0" json:"price"`
+	HoldMinutes     int      `gorm:"not null;default:15" json:"hold_minutes"`
+	// TransfersAllowed memakai pointer agar nilai false tetap tersimpan (default kolom true)
+	TransfersAllowed *bool `gorm:"not null;default:true" json:"transfers_allowed"`
+	// PassPrice adalah harga series pass per orang untuk semua sesi yang belum dimulai; nil berarti tidak dijual
+	PassPrice *float64 `gorm:"check:pass_price >= 0" json:"pass_price"`
+	VenueID   *uint    `gorm:"index" json:"venue_id"`
+	Events    []Event  `gorm:"foreignKey:SeriesID" json:"events,omitempty"`
+}
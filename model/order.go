@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OrderStatus string
+
+const (
+	OrderCart      OrderStatus = "cart"
+	OrderPending   OrderStatus = "pending"
+	OrderPaid      OrderStatus = "paid"
+	OrderCancelled OrderStatus = "cancelled"
+)
+
+// Order mengelompokkan beberapa baris pembelian (event/tier) dalam satu checkout.
+// Selama status cart, Order berfungsi sebagai keranjang belanja user.
+type Order struct {
+	gorm.Model
+	UserID    uint        `gorm:"not null;index" json:"user_id"`
+	Status    OrderStatus `gorm:"type:enum('cart','pending','paid','cancelled');default:'cart';index" json:"status"`
+	Total     float64     `json:"total"`
+	ExpiresAt *time.Time  `json:"expires_at"`             // batas pembayaran setelah checkout
+	SeriesID  *uint       `gorm:"index" json:"series_id"` // diisi untuk order series pass
+	Items     []OrderItem `json:"items,omitempty"`
+	Tickets   []Ticket    `json:"tickets,omitempty"`
+}
+
+type OrderItem struct {
+	gorm.Model
+	OrderID      uint        `gorm:"not null;index" json:"order_id"`
+	EventID      uint        `gorm:"not null" json:"event_id"`
+	Event        Event       `gorm:"foreignKey:EventID" json:"event"`
+	TicketTypeID *uint       `json:"ticket_type_id"`
+	TicketType   *TicketType `gorm:"foreignKey:TicketTypeID" json:"ticket_type,omitempty"`
+	Qty          int         `gorm:"not null" json:"qty"`
+	UnitPrice    float64     `json:"unit_price"`
+	LineTotal    float64     `json:"line_total"`
+	// Answers menyimpan jawaban formulir registrasi per key sampai checkout
+	Answers map[string]string `gorm:"serializer:json;type:text" json:"answers,omitempty"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PaymentAttemptStatus string
+
+const (
+	PaymentAttemptPending   PaymentAttemptStatus = "pending"
+	PaymentAttemptSucceeded PaymentAttemptStatus = "succeeded"
+	PaymentAttemptFailed    PaymentAttemptStatus = "failed"
+	PaymentAttemptRefunded  PaymentAttemptStatus = "refunded"
+)
+
+// Payment mencatat setiap percobaan pembayaran ke provider untuk satu tiket atau order
+type Payment struct {
+	gorm.Model
+	UserID        uint                 `gorm:"not null;index" json:"user_id"`
+	TicketID      *uint                `gorm:"index" json:"ticket_id"`
+	OrderID       *uint                `gorm:"index" json:"order_id"`
+	Provider      string               `gorm:"size:50;not null" json:"provider"`
+	ProviderRef   string               `gorm:"size:191;not null;uniqueIndex" json:"provider_ref"`
+	Amount        float64              `gorm:"not null" json:"amount"`
+	Currency      string               `gorm:"size:3;not null" json:"currency"`
+	Status        PaymentAttemptStatus `gorm:"type:enum('pending','succeeded','failed','refunded');default:'pending'" json:"status"`
+	FailureReason string               `json:"failure_reason,omitempty"`
+	CapturedAt    *time.Time           `json:"captured_at"`
+}
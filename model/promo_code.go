@@ -0,0 +1,72 @@
+package model
+
+import (
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DiscountType string
+
+const (
+	PercentDiscount DiscountType = "percent"
+	FixedDiscount   DiscountType = "fixed"
+)
+
+// PromoCode adalah kode diskon yang dikelola admin. Jika Events/TicketTypes
+// kosong, kode berlaku untuk semua event/tier.
+type PromoCode struct {
+	gorm.Model
+	Code           string       `gorm:"size:50;not null;uniqueIndex" json:"code"`
+	DiscountType   DiscountType `gorm:"type:enum('percent','fixed');not null" json:"discount_type"`
+	Value          float64      `gorm:"not null;check:value > 0" json:"value"`
+	ValidFrom      *time.Time   `json:"valid_from"`
+	ValidUntil     *time.Time   `json:"valid_until"`
+	MaxRedemptions int          `gorm:"not null;default:0" json:"max_redemptions"` // 0 berarti tanpa batas
+	MaxPerUser     int          `gorm:"not null;default:0" json:"max_per_user"`    // 0 berarti tanpa batas
+	Redeemed       int          `gorm:"not null;default:0" json:"redeemed"`
+	MinQty         int          `gorm:"not null;default:1" json:"min_qty"`
+	// Active memakai pointer agar nilai false tetap tersimpan (default kolom true)
+	Active      *bool        `gorm:"not null;default:true" json:"active"`
+	Events      []Event      `gorm:"many2many:promo_code_events" json:"events,omitempty"`
+	TicketTypes []TicketType `gorm:"many2many:promo_code_ticket_types" json:"ticket_types,omitempty"`
+}
+
+// PromoRedemption mencatat pemakaian kode promo oleh satu tiket
+type PromoRedemption struct {
+	gorm.Model
+	PromoCodeID uint    `gorm:"not null;index" json:"promo_code_id"`
+	UserID      uint    `gorm:"not null;index" json:"user_id"`
+	TicketID    uint    `gorm:"not null;uniqueIndex" json:"ticket_id"`
+	Discount    float64 `gorm:"not null" json:"discount"`
+}
+
+// IsActive mengembalikan apakah kode promo sedang diaktifkan admin
+func (p *PromoCode) IsActive() bool {
+	return p.Active == nil || *p.Active
+}
+
+// ValidAt mengecek apakah kode aktif dan berada dalam masa berlakunya
+func (p *PromoCode) ValidAt(now time.Time) bool {
+	if !p.IsActive() {
+		return false
+	}
+	if p.ValidFrom != nil && now.Before(*p.ValidFrom) {
+		return false
+	}
+	if p.ValidUntil != nil && now.After(*p.ValidUntil) {
+		return false
+	}
+	return true
+}
+
+// DiscountFor menghitung potongan untuk total tertentu, tidak pernah melebihi total
+func (p *PromoCode) DiscountFor(total float64) float64 {
+	discount := p.Value
+	if p.DiscountType == PercentDiscount {
+		discount = total * p.Value / 100
+	}
+	discount = math.Round(discount*100) / 100
+	return math.Min(discount, total)
+}
@@ -0,0 +1,33 @@
+package model
+
+import "gorm.io/gorm"
+
+// CancellationRule: jika pembatalan dilakukan minimal HoursBefore jam sebelum event,
+// user mendapat RefundPercent dari nilai tiket. Rule dengan HoursBefore terbesar yang
+// terpenuhi yang dipakai.
+type CancellationRule struct {
+	gorm.Model
+	EventID       uint    `gorm:"not null;index" json:"event_id"`
+	HoursBefore   int     `gorm:"not null" json:"hours_before"`
+	RefundPercent float64 `gorm:"not null;check:refund_percent >= 0 AND refund_percent <= 100" json:"refund_percent"`
+}
+
+type RefundStatus string
+
+const (
+	RefundPending   RefundStatus = "pending"
+	RefundProcessed RefundStatus = "processed"
+	RefundFailed    RefundStatus = "failed"
+)
+
+type Refund struct {
+	gorm.Model
+	TicketID    uint         `gorm:"not null;index" json:"ticket_id"`
+	PaymentID   *uint        `gorm:"index" json:"payment_id"`
+	Amount      float64      `gorm:"not null" json:"amount"`
+	Percent     float64      `json:"percent"`
+	Reason      string       `json:"reason"`
+	Status      RefundStatus `gorm:"type:enum('pending','processed','failed');default:'pending'" json:"status"`
+	ProviderRef string       `json:"provider_ref,omitempty"`
+	Error       string       `json:"error,omitempty"`
+}
@@ -0,0 +1,39 @@
+package model
+
+import "gorm.io/gorm"
+
+type QuestionType string
+
+const (
+	QuestionText     QuestionType = "text"
+	QuestionNumber   QuestionType = "number"
+	QuestionSelect   QuestionType = "select"
+	QuestionCheckbox QuestionType = "checkbox"
+)
+
+// RegistrationQuestion adalah satu isian formulir yang dijawab pembeli saat membeli tiket event.
+// Key dipakai sebagai nama field jawaban dan kolom ekspor, sehingga tetap sama saat label diubah.
+type RegistrationQuestion struct {
+	gorm.Model
+	EventID   uint         `gorm:"not null;index" json:"event_id"`
+	Key       string       `gorm:"size:50;not null" json:"key"`
+	Label     string       `gorm:"size:255;not null" json:"label"`
+	Type      QuestionType `gorm:"type:enum('text','number','select','checkbox');not null" json:"type"`
+	Required  bool         `gorm:"not null" json:"required"`
+	Options   []string     `gorm:"serializer:json;type:text" json:"options,omitempty"` // pilihan untuk tipe select
+	MinLength *int         `json:"min_length,omitempty"`
+	MaxLength *int         `json:"max_length,omitempty"`
+	Min       *float64     `json:"min,omitempty"`
+	Max       *float64     `json:"max,omitempty"`
+	Pattern   string       `gorm:"size:255" json:"pattern,omitempty"` // regex untuk jawaban teks
+	Position  int          `gorm:"not null;default:0" json:"position"`
+}
+
+// TicketAnswer menyimpan jawaban pembeli dalam bentuk teks yang sudah dinormalisasi
+type TicketAnswer struct {
+	gorm.Model
+	TicketID   uint                 `gorm:"not null;uniqueIndex:idx_answer_ticket_question" json:"ticket_id"`
+	QuestionID uint                 `gorm:"not null;uniqueIndex:idx_answer_ticket_question" json:"question_id"`
+	Question   RegistrationQuestion `gorm:"foreignKey:QuestionID" json:"-"`
+	Value      string               `gorm:"type:text" json:"value"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import "gorm.io/gorm"
+
+type SeatStatus string
+
+const (
+	SeatAvailable SeatStatus = "available"
+	SeatHeld      SeatStatus = "held"
+	SeatBooked    SeatStatus = "booked"
+)
+
+// Seat adalah satu kursi pada denah event dengan reserved seating
+type Seat struct {
+	gorm.Model
+	EventID      uint       `gorm:"not null;uniqueIndex:idx_seat_position" json:"event_id"`
+	Section      string     `gorm:"size:50;not null;uniqueIndex:idx_seat_position" json:"section"`
+	Row          string     `gorm:"size:20;not null;uniqueIndex:idx_seat_position" json:"row"`
+	Number       string     `gorm:"size:20;not null;uniqueIndex:idx_seat_position" json:"number"`
+	TicketTypeID *uint      `json:"ticket_type_id"` // tier yang berlaku untuk kursi ini, opsional
+	Status       SeatStatus `gorm:"type:enum('available','held','booked');default:'available';index" json:"status"`
+	TicketID     *uint      `gorm:"index" json:"ticket_id"`
+}
+
+// Label mengembalikan nama kursi yang mudah dibaca, mis. "A-3-12"
+func (s *Seat) Label() string {
+	return s.Section + "-" + s.Row + "-" + s.Number
+}
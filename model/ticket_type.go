@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TicketType adalah tier tiket dalam satu event (mis. Early Bird, Regular, VIP)
+// dengan harga, kuota dan jendela penjualan sendiri.
+type TicketType struct {
+	gorm.Model
+	EventID     uint       `gorm:"not null;uniqueIndex:idx_ticket_type_event_name" json:"event_id"`
+	Name        string     `gorm:"size:100;not null;uniqueIndex:idx_ticket_type_event_name" json:"name"`
+	Price       float64    `gorm:"not null;check:price >= 0" json:"price"`
+	Quota       int        `gorm:"not null;check:quota > 0" json:"quota"`
+	Sold        int        `gorm:"not null;default:0" json:"sold"`
+	Held        int        `gorm:"not null;default:0" json:"held"`
+	MinPerOrder int        `gorm:"not null;default:1" json:"min_per_order"`
+	MaxPerOrder int        `gorm:"not null;default:0" json:"max_per_order"` // 0 berarti tanpa batas
+	SalesStart  *time.Time `json:"sales_start"`
+	SalesEnd    *time.Time `json:"sales_end"`
+}
+
+// Available mengembalikan sisa kuota tier
+func (t *TicketType) Available() int {
+	return t.Quota - t.Sold - t.Held
+}
+
+// OnSale mengecek apakah tier sedang dalam jendela penjualan
+func (t *TicketType) OnSale(now time.Time) bool {
+	if t.SalesStart != nil && now.Before(*t.SalesStart) {
+		return false
+	}
+	if t.SalesEnd != nil && now.After(*t.SalesEnd) {
+		return false
+	}
+	return true
+}
@@ -1,6 +1,10 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type TicketStatus string
 
@@ -20,13 +24,27 @@ const (
 
 type Ticket struct {
 	gorm.Model
-	EventID       uint          `gorm:"not null" json:"event_id"`
-	Event         Event         `gorm:"foreignKey:EventID" json:"event"`
-	UserID        uint          `gorm:"not null" json:"user_id"`
-	User          User          `gorm:"foreignKey:UserID" json:"user"`
-	Qty           int           `gorm:"not null" json:"qty"`
-	SubTotal      float64       `json:"sub_total"`
-	Status        TicketStatus  `gorm:"type:enum('available','booked','cancelled');default:'available'" json:"status"`
-	PaymentStatus PaymentStatus `gorm:"type:enum('waiting','success','cancel');default:'waiting'" json:"role"`
-	BookingDate   string        `gorm:"not null" json:"booking_date"` // Format: "2006-01-02 15:04:05"
+	EventID       uint           `gorm:"not null" json:"event_id"`
+	Event         Event          `gorm:"foreignKey:EventID" json:"event"`
+	UserID        uint           `gorm:"not null" json:"user_id"`
+	OrderID       *uint          `gorm:"index" json:"order_id"` // diisi jika tiket dibuat lewat checkout keranjang
+	User          User           `gorm:"foreignKey:UserID" json:"user"`
+	TicketTypeID  *uint          `gorm:"index" json:"ticket_type_id"`
+	TicketType    *TicketType    `gorm:"foreignKey:TicketTypeID" json:"ticket_type,omitempty"`
+	Qty           int            `gorm:"not null" json:"qty"`
+	OriginalTotal float64        `json:"original_total"` // total sebelum diskon
+	Discount      float64        `gorm:"not null;default:0" json:"discount"`
+	PromoCodeID   *uint          `gorm:"index" json:"promo_code_id"`
+	PromoCode     *PromoCode     `gorm:"foreignKey:PromoCodeID" json:"promo_code,omitempty"`
+	SubTotal      float64        `json:"sub_total"` // total yang harus dibayar setelah diskon
+	Status        TicketStatus   `gorm:"type:enum('available','booked','cancelled');default:'available'" json:"status"`
+	PaymentStatus PaymentStatus  `gorm:"type:enum('waiting','success','cancel');default:'waiting'" json:"role"`
+	BookedAt      time.Time      `gorm:"index" json:"booked_at"`
+	ExpiresAt     *time.Time     `gorm:"index" json:"expires_at"` // Batas waktu pembayaran, nil jika tidak sedang di-hold
+	Seats         []Seat         `gorm:"foreignKey:TicketID" json:"seats,omitempty"`
+	Passes        []AttendeePass `gorm:"foreignKey:TicketID" json:"passes,omitempty"`
+	Answers       []TicketAnswer `gorm:"foreignKey:TicketID" json:"answers,omitempty"`
+	// CredentialVersion dinaikkan setiap kepemilikan berpindah sehingga kredensial lama tidak berlaku
+	CredentialVersion int `gorm:"not null;default:1" json:"credential_version"`
+	CheckedIn         int `gorm:"not null;default:0" json:"checked_in"` // jumlah orang yang sudah masuk gerbang
 }
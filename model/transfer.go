@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TransferStatus string
+
+const (
+	TransferPending   TransferStatus = "pending"
+	TransferAccepted  TransferStatus = "accepted"
+	TransferCancelled TransferStatus = "cancelled"
+)
+
+// TicketTransfer mencatat pemindahan kepemilikan tiket ke user lain berdasarkan email.
+// Baris tidak pernah dihapus sehingga menjadi riwayat kepemilikan tiket.
+type TicketTransfer struct {
+	gorm.Model
+	TicketID    uint           `gorm:"not null;index" json:"ticket_id"`
+	FromUserID  uint           `gorm:"not null;index" json:"from_user_id"`
+	FromUser    User           `gorm:"foreignKey:FromUserID" json:"-"`
+	ToEmail     string         `gorm:"size:255;not null;index" json:"to_email"`
+	ToUserID    *uint          `gorm:"index" json:"to_user_id"` // diisi saat transfer diterima
+	ToUser      *User          `gorm:"foreignKey:ToUserID" json:"-"`
+	Status      TransferStatus `gorm:"type:enum('pending','accepted','cancelled');default:'pending'" json:"status"`
+	RespondedAt *time.Time     `json:"responded_at"`
+}
@@ -7,6 +7,7 @@ type Role string
 const (
 	Admin Role = "admin"
 	Users Role = "user"
+	Staff Role = "staff" // petugas gerbang, hanya bisa check-in di event yang ditugaskan
 )
 
 type User struct {
@@ -14,6 +15,6 @@ type User struct {
 	Name     string   `gorm:"not null" json:"name"`
 	Password string   `gorm:"not null" json:"-"`
 	Email    string   `gorm:"unique;not null" json:"email"`
-	Role     Role     `gorm:"type:enum('admin','user');default:'user'" json:"role"`
+	Role     Role     `gorm:"type:enum('admin','user','staff');default:'user'" json:"role"`
 	Tickets  []Ticket `json:"tickets,omitempty"`
 }
@@ -0,0 +1,19 @@
+package model
+
+import "gorm.io/gorm"
+
+// Venue adalah lokasi yang bisa dipakai ulang oleh banyak event. MaxCapacity membatasi
+// kapasitas event di venue ini, dan dua event di venue yang sama tidak boleh bertabrakan waktunya.
+type Venue struct {
+	gorm.Model
+	Name    string `gorm:"size:191;not null" json:"name"`
+	Address string `gorm:"not null" json:"address"`
+	City    string `gorm:"size:100;not null;index" json:"city"`
+	Country string `gorm:"size:100;not null;index" json:"country"`
+	// Koordinat opsional dalam derajat desimal (WGS84)
+	Latitude           *float64 `gorm:"type:decimal(9,6);index:idx_venue_coords" json:"latitude"`
+	Longitude          *float64 `gorm:"type:decimal(9,6);index:idx_venue_coords" json:"longitude"`
+	MaxCapacity        int      `gorm:"not null;check:max_capacity > 0" json:"max_capacity"`
+	Timezone           string   `gorm:"size:64;not null;default:'UTC'" json:"timezone"`
+	AccessibilityNotes string   `gorm:"type:text" json:"accessibility_notes"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WaitlistStatus string
+
+const (
+	WaitlistWaiting  WaitlistStatus = "waiting"
+	WaitlistOffered  WaitlistStatus = "offered"
+	WaitlistAccepted WaitlistStatus = "accepted"
+	WaitlistExpired  WaitlistStatus = "expired"
+	WaitlistLeft     WaitlistStatus = "left"
+)
+
+// WaitlistEntry adalah antrean user untuk event yang habis terjual. Urutan antrean
+// mengikuti ID (FIFO). Saat ditawari, qty entry dicadangkan di Event.Offered
+// sampai OfferExpiresAt.
+type WaitlistEntry struct {
+	gorm.Model
+	EventID        uint           `gorm:"not null;index:idx_waitlist_event_status" json:"event_id"`
+	Event          Event          `gorm:"foreignKey:EventID" json:"-"`
+	UserID         uint           `gorm:"not null;index" json:"user_id"`
+	User           User           `gorm:"foreignKey:UserID" json:"-"`
+	Qty            int            `gorm:"not null;check:qty > 0" json:"qty"`
+	Status         WaitlistStatus `gorm:"type:enum('waiting','offered','accepted','expired','left');default:'waiting';index:idx_waitlist_event_status" json:"status"`
+	OfferedAt      *time.Time     `json:"offered_at"`
+	OfferExpiresAt *time.Time     `gorm:"index" json:"offer_expires_at"`
+	TicketID       *uint          `json:"ticket_id"` // tiket yang dibuat saat tawaran diterima
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WebhookStatus string
+
+const (
+	WebhookReceived  WebhookStatus = "received"
+	WebhookProcessed WebhookStatus = "processed"
+	WebhookFailed    WebhookStatus = "failed"
+	WebhookRejected  WebhookStatus = "rejected" // signature tidak valid atau nominal tidak cocok dengan Payment
+)
+
+// WebhookDelivery menyimpan setiap webhook yang diterima beserta hasil verifikasi dan pemrosesannya
+type WebhookDelivery struct {
+	gorm.Model
+	Provider    string        `gorm:"size:50;not null;uniqueIndex:idx_webhook_provider_event" json:"provider"`
+	EventID     *string       `gorm:"size:191;uniqueIndex:idx_webhook_provider_event" json:"event_id"` // nil jika payload tidak terverifikasi
+	Signature   string        `json:"signature"`
+	Payload     string        `gorm:"type:text" json:"payload"`
+	Verified    bool          `json:"verified"`
+	Status      WebhookStatus `gorm:"type:enum('received','processed','failed','rejected');default:'received';index" json:"status"`
+	Error       string        `json:"error,omitempty"`
+	Attempts    int           `gorm:"not null;default:0" json:"attempts"`
+	ProcessedAt *time.Time    `json:"processed_at"`
+}
@@ -0,0 +1,181 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Mode mengatur hasil yang disimulasikan oleh FakeProvider
+type Mode string
+
+const (
+	ModeSuccess Mode = "success"
+	ModeFailure Mode = "failure"
+	ModeDelayed Mode = "delayed" // capture pending, lalu sukses setelah Delay lewat notifikasi
+)
+
+// FakeProvider berjalan in-process untuk development lokal dan testing; pembayaran disetujui
+// tanpa uang sungguhan sehingga tidak boleh dipakai di production
+type FakeProvider struct {
+	mu      sync.Mutex
+	mode    Mode
+	delay   time.Duration
+	secret  []byte
+	intents map[string]*fakeIntent
+	notify  func(event WebhookEvent, payload []byte, signature string)
+}
+
+type fakeIntent struct {
+	amount   float64
+	currency string
+	refunded float64
+	status   Status
+}
+
+func NewFakeProvider(mode Mode, delay time.Duration, secret string) *FakeProvider {
+	if mode == "" {
+		mode = ModeDelayed
+	}
+	return &FakeProvider{
+		mode:    mode,
+		delay:   delay,
+		secret:  []byte(secret),
+		intents: make(map[string]*fakeIntent),
+	}
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+// SetMode mengubah perilaku provider saat runtime
+func (p *FakeProvider) SetMode(mode Mode) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.mode = mode
+}
+
+// SetNotifier mendaftarkan penerima notifikasi untuk capture yang tertunda (ModeDelayed)
+func (p *FakeProvider) SetNotifier(notify func(event WebhookEvent, payload []byte, signature string)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.notify = notify
+}
+
+func (p *FakeProvider) CreateIntent(intent Intent) (*IntentResult, error) {
+	ref := "fake_" + randomHex(12)
+
+	p.mu.Lock()
+	p.intents[ref] = &fakeIntent{amount: intent.Amount, currency: intent.Currency, status: StatusPending}
+	p.mu.Unlock()
+
+	return &IntentResult{
+		ProviderRef:  ref,
+		Status:       StatusPending,
+		ClientSecret: ref + "_secret",
+	}, nil
+}
+
+func (p *FakeProvider) Capture(providerRef string) (Status, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, ok := p.intents[providerRef]
+	if !ok {
+		return "", ErrUnknownIntent
+	}
+
+	switch p.mode {
+	case ModeFailure:
+		intent.status = StatusFailed
+	case ModeDelayed:
+		intent.status = StatusPending
+		go p.confirmLater(providerRef)
+	default:
+		intent.status = StatusSucceeded
+	}
+
+	return intent.status, nil
+}
+
+func (p *FakeProvider) Refund(providerRef string, amount float64) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, ok := p.intents[providerRef]
+	if !ok {
+		return "", ErrUnknownIntent
+	}
+	if intent.status != StatusSucceeded && intent.status != StatusRefunded {
+		return "", fmt.Errorf("cannot refund payment in status %s", intent.status)
+	}
+	if intent.refunded+amount > intent.amount {
+		return "", fmt.Errorf("refund amount exceeds captured amount")
+	}
+
+	intent.refunded += amount
+	if intent.refunded >= intent.amount {
+		intent.status = StatusRefunded
+	}
+	return "fake_re_" + randomHex(12), nil
+}
+
+func (p *FakeProvider) VerifyWebhook(payload []byte, signature string) (*WebhookEvent, error) {
+	if !hmac.Equal([]byte(p.Sign(payload)), []byte(signature)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// Sign menghasilkan signature HMAC-SHA256 (hex) yang sama dengan yang dicek VerifyWebhook
+func (p *FakeProvider) Sign(payload []byte) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// confirmLater mensimulasikan konfirmasi asinkron dari gateway
+func (p *FakeProvider) confirmLater(providerRef string) {
+	time.Sleep(p.delay)
+
+	p.mu.Lock()
+	intent, ok := p.intents[providerRef]
+	if ok {
+		intent.status = StatusSucceeded
+	}
+	notify := p.notify
+	p.mu.Unlock()
+
+	if !ok || notify == nil {
+		return
+	}
+
+	event := WebhookEvent{
+		ID:          "evt_" + randomHex(12),
+		ProviderRef: providerRef,
+		Status:      StatusSucceeded,
+		Amount:      intent.amount,
+		Currency:    intent.currency,
+	}
+	payload, _ := json.Marshal(event)
+	notify(event, payload, p.Sign(payload))
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
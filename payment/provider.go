@@ -0,0 +1,49 @@
+// Package payment berisi abstraksi payment gateway yang dipakai service tiket.
+package payment
+
+import "errors"
+
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusRefunded  Status = "refunded"
+)
+
+var (
+	ErrUnknownIntent    = errors.New("payment intent not found at provider")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+)
+
+// Intent adalah permintaan pembayaran yang dikirim ke provider
+type Intent struct {
+	Reference string // referensi internal, mis. "ticket:12" atau "order:7"
+	Amount    float64
+	Currency  string
+}
+
+type IntentResult struct {
+	ProviderRef  string
+	Status       Status
+	ClientSecret string // diteruskan ke frontend jika provider butuh konfirmasi di sisi klien
+}
+
+// WebhookEvent adalah notifikasi perubahan status pembayaran dari provider
+type WebhookEvent struct {
+	ID          string  `json:"id"`
+	ProviderRef string  `json:"provider_ref"`
+	Status      Status  `json:"status"`
+	Amount      float64 `json:"amount"`
+	Currency    string  `json:"currency"`
+}
+
+// Provider adalah kontrak yang harus dipenuhi setiap payment gateway
+type Provider interface {
+	Name() string
+	CreateIntent(intent Intent) (*IntentResult, error)
+	Capture(providerRef string) (Status, error)
+	Refund(providerRef string, amount float64) (string, error)
+	VerifyWebhook(payload []byte, signature string) (*WebhookEvent, error)
+}
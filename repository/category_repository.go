@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"errors"
+
+	"ticketing/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrCategoryHasChildren dikembalikan ketika kategori yang masih punya subkategori akan dihapus
+var ErrCategoryHasChildren = errors.New("category still has subcategories")
+
+type CategoryRepository interface {
+	Create(category *model.Category) error
+	FindAll() ([]model.Category, error)
+	FindByID(id uint) (*model.Category, error)
+	FindByIDs(ids []uint) ([]model.Category, error)
+	FindBySlug(slug string) (*model.Category, error)
+	Update(category *model.Category) error
+	Delete(id uint) error
+}
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (r *categoryRepository) Create(category *model.Category) error {
+	return r.db.Omit("Children").Create(category).Error
+}
+
+// FindAll mengembalikan semua kategori (datar) berurutan; pohon disusun di service
+func (r *categoryRepository) FindAll() ([]model.Category, error) {
+	var categories []model.Category
+	err := r.db.Order("position, name, id").Find(&categories).Error
+	return categories, err
+}
+
+func (r *categoryRepository) FindByID(id uint) (*model.Category, error) {
+	var category model.Category
+	err := r.db.First(&category, id).Error
+	return &category, err
+}
+
+func (r *categoryRepository) FindByIDs(ids []uint) ([]model.Category, error) {
+	var categories []model.Category
+	err := r.db.Where("id IN ?", ids).Order("position, name, id").Find(&categories).Error
+	return categories, err
+}
+
+func (r *categoryRepository) FindBySlug(slug string) (*model.Category, error) {
+	var category model.Category
+	err := r.db.Where("slug = ?", slug).First(&category).Error
+	return &category, err
+}
+
+func (r *categoryRepository) Update(category *model.Category) error {
+	return r.db.Omit("Children").Save(category).Error
+}
+
+// Delete menghapus kategori secara permanen (agar slug bisa dipakai lagi) beserta tautannya
+// ke event. Kategori yang masih punya subkategori tidak boleh dihapus.
+func (r *categoryRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var category model.Category
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&category, id).Error; err != nil {
+			return err
+		}
+
+		var children int64
+		if err := tx.Model(&model.Category{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+			return err
+		}
+		if children > 0 {
+			return ErrCategoryHasChildren
+		}
+
+		if err := tx.Exec("DELETE FROM event_categories WHERE category_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&category).Error
+	})
+}
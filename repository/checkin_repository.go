@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"ticketing/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTicketNotAdmissible   = errors.New("ticket is not booked")
+	ErrCredentialSuperseded  = errors.New("credential has been superseded by a newer one")
+	ErrAlreadyCheckedIn      = errors.New("ticket has already been fully checked in")
+	ErrAdmitExceedsRemaining = errors.New("not enough admissions left on this ticket")
+)
+
+// GateCount adalah jumlah orang yang masuk lewat satu gerbang
+type GateCount struct {
+	Gate     string
+	Admitted int
+}
+
+type CheckInSummary struct {
+	Booked     int
+	CheckedIn  int
+	Rejected   int64
+	Conflicts  int64 // konflik sync offline yang belum ditinjau
+	Gates      []GateCount
+	LastScanAt *time.Time
+}
+
+type CheckInRepository interface {
+	AssignStaff(assignment *model.StaffAssignment) error
+	UnassignStaff(eventID, userID uint) error
+	FindStaff(eventID uint) ([]model.StaffAssignment, error)
+	IsAssigned(eventID, userID uint) (bool, error)
+	Admit(scan *model.CheckIn, version, admit int) (*model.Ticket, error)
+	AdmitOffline(scan *model.CheckIn, version, admit int) error
+	Record(scan *model.CheckIn) error
+	LastAdmitted(ticketID uint) (*model.CheckIn, error)
+	FindByDeviceScan(deviceID, clientScanID string) (*model.CheckIn, error)
+	FindBundleTickets(eventID uint) ([]model.Ticket, error)
+	FindScans(eventID uint, page, limit int, result string) ([]model.CheckIn, int64, error)
+	Review(id, reviewerID uint, note string) (*model.CheckIn, error)
+	Summary(eventID uint) (*CheckInSummary, error)
+}
+
+type checkInRepository struct {
+	db *gorm.DB
+}
+
+func NewCheckInRepository(db *gorm.DB) CheckInRepository {
+	return &checkInRepository{db: db}
+}
+
+// AssignStaff idempoten: menugaskan staff yang sama dua kali tidak membuat baris baru
+func (r *checkInRepository) AssignStaff(assignment *model.StaffAssignment) error {
+	return r.db.Where(model.StaffAssignment{EventID: assignment.EventID, UserID: assignment.UserID}).
+		FirstOrCreate(assignment).Error
+}
+
+func (r *checkInRepository) UnassignStaff(eventID, userID uint) error {
+	result := r.db.Unscoped().Where("event_id = ? AND user_id = ?", eventID, userID).Delete(&model.StaffAssignment{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *checkInRepository) FindStaff(eventID uint) ([]model.StaffAssignment, error) {
+	var assignments []model.StaffAssignment
+	err := r.db.Preload("User").Where("event_id = ?", eventID).Order("id").Find(&assignments).Error
+	return assignments, err
+}
+
+func (r *checkInRepository) IsAssigned(eventID, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.StaffAssignment{}).Where("event_id = ? AND user_id = ?", eventID, userID).Count(&count).Error
+	return count > 0, err
+}
+
+// Admit memasukkan admit orang (0 berarti seluruh sisa kuota) dengan tiket dikunci,
+// sehingga dua scanner yang memindai tiket yang sama bersamaan tidak bisa melebihi Qty.
+func (r *checkInRepository) Admit(scan *model.CheckIn, version, admit int) (*model.Ticket, error) {
+	var ticket model.Ticket
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&ticket, scan.TicketID).Error; err != nil {
+			return err
+		}
+
+		if ticket.Status != model.Booked {
+			return ErrTicketNotAdmissible
+		}
+		if version != ticket.CredentialVersion {
+			return ErrCredentialSuperseded
+		}
+
+		// Kredensial attendee pass hanya memasukkan satu orang, dan hanya sekali
+		if scan.PassID != nil {
+			pass, err := lockPass(tx, &ticket, *scan.PassID)
+			if err != nil {
+				return err
+			}
+			if pass.CheckedInAt != nil {
+				return ErrPassCheckedIn
+			}
+			if admit > 1 {
+				return ErrAdmitExceedsRemaining
+			}
+			admit = 1
+		}
+
+		remaining := ticket.Qty - ticket.CheckedIn
+		if remaining <= 0 {
+			return ErrAlreadyCheckedIn
+		}
+		if admit == 0 {
+			admit = remaining
+		}
+		if admit > remaining {
+			return ErrAdmitExceedsRemaining
+		}
+
+		if err := admitPasses(tx, &ticket, scan.PassID, admit, scan.Gate, scan.ScannedAt); err != nil {
+			return err
+		}
+		if err := tx.Model(&ticket).Update("checked_in", gorm.Expr("checked_in + ?", admit)).Error; err != nil {
+			return err
+		}
+		ticket.CheckedIn += admit
+
+		scan.EventID = ticket.EventID
+		scan.Admitted = admit
+		scan.Result = model.CheckInAdmitted
+		return tx.Create(scan).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// AdmitOffline mencatat scan yang sudah terjadi di scanner offline. Orang tersebut
+// sudah masuk, jadi scan tidak ditolak; jika tiket tidak lagi sah atau kuotanya
+// sudah habis (mis. tiket yang sama masuk di dua gerbang), scan dicatat sebagai
+// konflik dan checked_in tidak pernah melebihi Qty.
+func (r *checkInRepository) AdmitOffline(scan *model.CheckIn, version, admit int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var ticket model.Ticket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&ticket, scan.TicketID).Error; err != nil {
+			return err
+		}
+		scan.EventID = ticket.EventID
+		scan.Result = model.CheckInAdmitted
+
+		switch {
+		case ticket.Status != model.Booked:
+			scan.Result = model.CheckInConflict
+			scan.Reason = ErrTicketNotAdmissible.Error()
+		case version != ticket.CredentialVersion:
+			scan.Result = model.CheckInConflict
+			scan.Reason = ErrCredentialSuperseded.Error()
+		default:
+			if err := admitOffline(tx, &ticket, scan, admit); err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(scan).Error
+	})
+}
+
+func admitOffline(tx *gorm.DB, ticket *model.Ticket, scan *model.CheckIn, admit int) error {
+	if scan.PassID != nil {
+		pass, err := lockPass(tx, ticket, *scan.PassID)
+		if err != nil {
+			return err
+		}
+		if pass.CheckedInAt != nil {
+			scan.Result = model.CheckInConflict
+			scan.Reason = fmt.Sprintf("%s (at gate %s)", ErrPassCheckedIn.Error(), pass.CheckInGate)
+			return nil
+		}
+		admit = 1
+	}
+
+	remaining := max(ticket.Qty-ticket.CheckedIn, 0)
+	scan.Admitted = min(admit, remaining)
+	if admit > remaining {
+		scan.Result = model.CheckInConflict
+		scan.Reason = fmt.Sprintf("admitted %d but only %d admissions were left", admit, remaining)
+
+		var gates []string
+		if err := tx.Model(&model.CheckIn{}).
+			Where("ticket_id = ? AND admitted > 0", ticket.ID).
+			Distinct().Pluck("gate", &gates).Error; err != nil {
+			return err
+		}
+		if len(gates) > 0 {
+			scan.Reason += " (already admitted at gate " + strings.Join(gates, ", ") + ")"
+		}
+	}
+	if scan.Admitted == 0 {
+		return nil
+	}
+
+	if err := admitPasses(tx, ticket, scan.PassID, scan.Admitted, scan.Gate, scan.ScannedAt); err != nil {
+		return err
+	}
+	return tx.Model(ticket).Update("checked_in", gorm.Expr("checked_in + ?", scan.Admitted)).Error
+}
+
+// Record menyimpan scan yang ditolak untuk audit
+func (r *checkInRepository) Record(scan *model.CheckIn) error {
+	return r.db.Create(scan).Error
+}
+
+func (r *checkInRepository) LastAdmitted(ticketID uint) (*model.CheckIn, error) {
+	var scan model.CheckIn
+	err := r.db.Where("ticket_id = ? AND admitted > 0", ticketID).
+		Order("scanned_at DESC").First(&scan).Error
+	return &scan, err
+}
+
+func (r *checkInRepository) FindByDeviceScan(deviceID, clientScanID string) (*model.CheckIn, error) {
+	var scan model.CheckIn
+	err := r.db.Where("device_id = ? AND client_scan_id = ?", deviceID, clientScanID).First(&scan).Error
+	return &scan, err
+}
+
+// FindBundleTickets mengembalikan tiket booked sebuah event untuk bundle scanner offline
+func (r *checkInRepository) FindBundleTickets(eventID uint) ([]model.Ticket, error) {
+	var tickets []model.Ticket
+	err := r.db.Select("id", "qty", "checked_in", "credential_version").
+		Preload("Passes", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "ticket_id", "seq", "checked_in_at").Order("seq")
+		}).
+		Where("event_id = ? AND status = ?", eventID, model.Booked).
+		Order("id").Find(&tickets).Error
+	return tickets, err
+}
+
+func (r *checkInRepository) FindScans(eventID uint, page, limit int, result string) ([]model.CheckIn, int64, error) {
+	var scans []model.CheckIn
+	var total int64
+
+	query := r.db.Model(&model.CheckIn{}).Where("event_id = ?", eventID)
+	if result != "" {
+		query = query.Where("result = ?", result)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Preload("Scanner").
+		Order("scanned_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&scans).Error
+	return scans, total, err
+}
+
+// Review menandai konflik sync offline sebagai sudah ditinjau
+func (r *checkInRepository) Review(id, reviewerID uint, note string) (*model.CheckIn, error) {
+	result := r.db.Model(&model.CheckIn{}).
+		Where("id = ? AND result = ? AND reviewed_at IS NULL", id, model.CheckInConflict).
+		Updates(map[string]interface{}{"reviewed_at": time.Now(), "reviewed_by": reviewerID, "review_note": note})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var scan model.CheckIn
+	err := r.db.Preload("Scanner").First(&scan, id).Error
+	return &scan, err
+}
+
+// Summary menghitung angka check-in langsung dari tabel sehingga selalu terkini
+func (r *checkInRepository) Summary(eventID uint) (*CheckInSummary, error) {
+	summary := &CheckInSummary{}
+
+	var totals struct {
+		Booked    int
+		CheckedIn int
+	}
+	if err := r.db.Model(&model.Ticket{}).
+		Select("COALESCE(SUM(qty), 0) AS booked, COALESCE(SUM(checked_in), 0) AS checked_in").
+		Where("event_id = ? AND status = ?", eventID, model.Booked).
+		Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+	summary.Booked = totals.Booked
+	summary.CheckedIn = totals.CheckedIn
+
+	if err := r.db.Model(&model.CheckIn{}).
+		Where("event_id = ? AND result = ?", eventID, model.CheckInRejected).
+		Count(&summary.Rejected).Error; err != nil {
+		return nil, err
+	}
+
+	if err := r.db.Model(&model.CheckIn{}).
+		Where("event_id = ? AND result = ? AND reviewed_at IS NULL", eventID, model.CheckInConflict).
+		Count(&summary.Conflicts).Error; err != nil {
+		return nil, err
+	}
+
+	if err := r.db.Model(&model.CheckIn{}).
+		Select("gate, COALESCE(SUM(admitted), 0) AS admitted").
+		Where("event_id = ? AND admitted > 0", eventID).
+		Group("gate").Order("gate").
+		Scan(&summary.Gates).Error; err != nil {
+		return nil, err
+	}
+
+	var last model.CheckIn
+	err := r.db.Where("event_id = ?", eventID).Order("scanned_at DESC").First(&last).Error
+	if err == nil {
+		summary.LastScanAt = &last.ScannedAt
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return summary, nil
+}
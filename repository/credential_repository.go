@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"ticketing/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CredentialRepository interface {
+	ActiveKey() (*model.SigningKey, error)
+	FindKeys(statuses []model.SigningKeyStatus) ([]model.SigningKey, error)
+	Rotate(key *model.SigningKey) error
+	Issue(credential *model.TicketCredential, reason string) error
+	FindActive(ticketID uint) (*model.TicketCredential, error)
+	FindActiveForPass(passID uint) (*model.TicketCredential, error)
+	FindByCredentialID(credentialID string) (*model.TicketCredential, error)
+	Revoke(ticketID uint, reason string) error
+	RevokePass(passID uint, reason string) error
+	FindRevoked(eventID uint, now time.Time) ([]model.TicketCredential, error)
+}
+
+type credentialRepository struct {
+	db *gorm.DB
+}
+
+func NewCredentialRepository(db *gorm.DB) CredentialRepository {
+	return &credentialRepository{db: db}
+}
+
+func (r *credentialRepository) ActiveKey() (*model.SigningKey, error) {
+	var key model.SigningKey
+	err := r.db.Where("status = ?", model.SigningKeyActive).Order("id DESC").First(&key).Error
+	return &key, err
+}
+
+func (r *credentialRepository) FindKeys(statuses []model.SigningKeyStatus) ([]model.SigningKey, error) {
+	var keys []model.SigningKey
+	err := r.db.Where("status IN ?", statuses).Order("id DESC").Find(&keys).Error
+	return keys, err
+}
+
+// Rotate menjadikan key sebagai kunci aktif: kunci aktif lama menjadi previous dan
+// previous sebelumnya dipensiunkan, semuanya dalam satu transaksi.
+func (r *credentialRepository) Rotate(key *model.SigningKey) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current []model.SigningKey
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("status IN ?", []model.SigningKeyStatus{model.SigningKeyActive, model.SigningKeyPrevious}).
+			Find(&current).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, k := range current {
+			updates := map[string]interface{}{"status": model.SigningKeyPrevious}
+			if k.Status == model.SigningKeyPrevious {
+				updates = map[string]interface{}{"status": model.SigningKeyRetired, "retired_at": now}
+			}
+			if err := tx.Model(&k).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		key.Status = model.SigningKeyActive
+		key.ActivatedAt = now
+		return tx.Create(key).Error
+	})
+}
+
+// Issue menyimpan kredensial baru dan mencabut kredensial lama dengan cakupan yang
+// sama: kredensial tiket hanya menggantikan kredensial tiket, kredensial pass hanya pass itu.
+func (r *credentialRepository) Issue(credential *model.TicketCredential, reason string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&model.TicketCredential{}).Where("ticket_id = ? AND revoked_at IS NULL", credential.TicketID)
+		if credential.PassID != nil {
+			query = query.Where("pass_id = ?", *credential.PassID)
+		} else {
+			query = query.Where("pass_id IS NULL")
+		}
+		if err := query.Updates(map[string]interface{}{"revoked_at": time.Now(), "revoke_reason": reason}).Error; err != nil {
+			return err
+		}
+		return tx.Create(credential).Error
+	})
+}
+
+func (r *credentialRepository) FindActive(ticketID uint) (*model.TicketCredential, error) {
+	var credential model.TicketCredential
+	err := r.db.Where("ticket_id = ? AND pass_id IS NULL AND revoked_at IS NULL", ticketID).Order("id DESC").First(&credential).Error
+	return &credential, err
+}
+
+func (r *credentialRepository) FindActiveForPass(passID uint) (*model.TicketCredential, error) {
+	var credential model.TicketCredential
+	err := r.db.Where("pass_id = ? AND revoked_at IS NULL", passID).Order("id DESC").First(&credential).Error
+	return &credential, err
+}
+
+func (r *credentialRepository) FindByCredentialID(credentialID string) (*model.TicketCredential, error) {
+	var credential model.TicketCredential
+	err := r.db.Where("credential_id = ?", credentialID).First(&credential).Error
+	return &credential, err
+}
+
+// Revoke mencabut semua kredensial tiket, termasuk kredensial attendee pass-nya
+func (r *credentialRepository) Revoke(ticketID uint, reason string) error {
+	return revokeCredentials(r.db, ticketID, reason)
+}
+
+func (r *credentialRepository) RevokePass(passID uint, reason string) error {
+	return r.db.Model(&model.TicketCredential{}).
+		Where("pass_id = ? AND revoked_at IS NULL", passID).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "revoke_reason": reason}).Error
+}
+
+// FindRevoked mengembalikan kredensial yang dicabut tapi belum kedaluwarsa,
+// yaitu yang masih perlu ditolak oleh scanner. eventID 0 berarti semua event.
+func (r *credentialRepository) FindRevoked(eventID uint, now time.Time) ([]model.TicketCredential, error) {
+	var credentials []model.TicketCredential
+	query := r.db.Where("revoked_at IS NOT NULL AND expires_at > ?", now)
+	if eventID != 0 {
+		query = query.Where("event_id = ?", eventID)
+	}
+	err := query.Order("id").Find(&credentials).Error
+	return credentials, err
+}
+
+func revokeCredentials(db *gorm.DB, ticketID uint, reason string) error {
+	return db.Model(&model.TicketCredential{}).
+		Where("ticket_id = ? AND revoked_at IS NULL", ticketID).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "revoke_reason": reason}).Error
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ticketing/config"
+	"ticketing/model"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	migrateOnce sync.Once
+	migrateErr  error
+	testSeq     atomic.Int64
+)
+
+// openTestDB membuka database MySQL dari TEST_DATABASE_DSN (mis.
+// "root:secret@tcp(127.0.0.1:3306)/ticketing_test?parseTime=True") lalu menjalankan migrasi.
+// Dipakai test yang butuh row lock MySQL (FOR UPDATE), jadi test dilewati jika DSN kosong.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open test database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("open test database: %v", err)
+	}
+	// Di bawah max_connections bawaan MySQL; goroutine lain mengantre koneksi
+	sqlDB.SetMaxOpenConns(50)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	migrateOnce.Do(func() { migrateErr = config.AutoMigrate(db) })
+	if migrateErr != nil {
+		t.Fatalf("migrate test database: %v", migrateErr)
+	}
+	return db
+}
+
+// openPortableTestDB memakai database MySQL dari TEST_DATABASE_DSN jika diisi, selain itu
+// SQLite in-memory baru per test. Hanya untuk test yang tidak bergantung pada row lock MySQL;
+// SQLite mengabaikan FOR UPDATE sehingga test konkurensi tetap memakai openTestDB.
+func openPortableTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	if os.Getenv("TEST_DATABASE_DSN") != "" {
+		return openTestDB(t)
+	}
+
+	db, err := gorm.Open(sqliteTestDialector{sqlite.Open(":memory:").(*sqlite.Dialector)},
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open sqlite test database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("open sqlite test database: %v", err)
+	}
+	// Setiap koneksi :memory: adalah database terpisah
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := config.AutoMigrate(db); err != nil {
+		t.Fatalf("migrate sqlite test database: %v", err)
+	}
+	return db
+}
+
+// sqliteTestDialector memetakan kolom enum MySQL ke text agar skema bisa dimigrasi di SQLite
+type sqliteTestDialector struct {
+	*sqlite.Dialector
+}
+
+func (d sqliteTestDialector) DataTypeOf(field *schema.Field) string {
+	if strings.HasPrefix(string(field.DataType), "enum(") {
+		return "text"
+	}
+	return d.Dialector.DataTypeOf(field)
+}
+
+// Migrator memakai migrator SQLite dengan dialector ini agar DataTypeOf di atas dipakai
+func (d sqliteTestDialector) Migrator(db *gorm.DB) gorm.Migrator {
+	m := d.Dialector.Migrator(db).(sqlite.Migrator)
+	m.Dialector = d
+	return m
+}
+
+// uniqueName membuat nama unik agar test bisa berbagi database tanpa dibersihkan
+func uniqueName(prefix string) string {
+	return fmt.Sprintf("%s-%d-%d", prefix, time.Now().UnixNano(), testSeq.Add(1))
+}
+
+func createTestUser(t *testing.T, db *gorm.DB) *model.User {
+	t.Helper()
+
+	user := &model.User{Name: "Test User", Password: "x", Email: uniqueName("user") + "@example.com", Role: model.Users}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+// createTestEvent menyimpan event; field yang kosong diisi nilai minimal yang valid
+func createTestEvent(t *testing.T, db *gorm.DB, event model.Event) *model.Event {
+	t.Helper()
+
+	if event.Name == "" {
+		event.Name = uniqueName("event")
+	}
+	event.Description, event.Location = "test", "test"
+	if event.StartsAt.IsZero() {
+		event.StartsAt = time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second)
+	}
+	event.EndsAt = event.StartsAt.Add(3 * time.Hour)
+	if event.Capacity == 0 {
+		event.Capacity = 100
+	}
+	if err := db.Omit("Venue", "Categories", "Tags").Create(&event).Error; err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+	return &event
+}
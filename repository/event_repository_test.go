@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"ticketing/model"
+	"ticketing/utils"
+
+	"gorm.io/gorm"
+)
+
+func createTestVenue(t *testing.T, db *gorm.DB, lat, lng float64) *model.Venue {
+	t.Helper()
+
+	venue := &model.Venue{Name: uniqueName("venue"), Address: "test", City: "Test", Country: "Test",
+		Latitude: &lat, Longitude: &lng, MaxCapacity: 1000}
+	if err := db.Create(venue).Error; err != nil {
+		t.Fatalf("create venue: %v", err)
+	}
+	return venue
+}
+
+func TestFindAllNear(t *testing.T) {
+	db := openPortableTestDB(t)
+	repo := NewEventRepository(db)
+
+	// Nama event diberi prefiks unik; filter search membatasi hasil ke event test ini saja
+	prefix := uniqueName("near")
+	monas := GeoPoint{Lat: -6.1754, Lng: 106.8272}
+	venues := map[string]*model.Venue{
+		"close": createTestVenue(t, db, -6.1862, 106.8228), // ~1.3 km
+		"mid":   createTestVenue(t, db, -6.2615, 106.7838), // ~10.7 km
+		"far":   createTestVenue(t, db, -6.9175, 107.6191), // Bandung, ~120 km
+	}
+	ids := make(map[uint]string)
+	for name, venue := range venues {
+		event := createTestEvent(t, db, model.Event{Name: prefix + "-" + name, VenueID: &venue.ID})
+		ids[event.ID] = name
+	}
+	// Event tanpa venue tidak punya koordinat sehingga tidak pernah ikut pencarian near
+	createTestEvent(t, db, model.Event{Name: prefix + "-online"})
+
+	tests := []struct {
+		name        string
+		radiusKm    float64
+		sort        []EventSort
+		page, limit int
+		want        []string
+		wantTotal   int64
+	}{
+		{name: "small radius", radiusKm: 5, want: []string{"close"}, wantTotal: 1},
+		{name: "city radius", radiusKm: 15, want: []string{"close", "mid"}, wantTotal: 2},
+		{name: "regional radius", radiusKm: 200, want: []string{"close", "mid", "far"}, wantTotal: 3},
+		{name: "farthest first", radiusKm: 200, sort: []EventSort{{Field: "distance", Desc: true}},
+			want: []string{"far", "mid", "close"}, wantTotal: 3},
+		{name: "second page", radiusKm: 200, page: 2, limit: 1, want: []string{"mid"}, wantTotal: 3},
+		{name: "distance then name", radiusKm: 200, sort: []EventSort{{Field: "distance"}, {Field: "name"}},
+			want: []string{"close", "mid", "far"}, wantTotal: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, limit := tt.page, tt.limit
+			if page == 0 {
+				page, limit = 1, 10
+			}
+			filter := EventFilter{Search: prefix, Near: &monas, RadiusKm: tt.radiusKm, Sort: tt.sort}
+
+			events, total, err := repo.FindAll(page, limit, filter)
+			if err != nil {
+				t.Fatalf("FindAll: %v", err)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("total = %d, want %d", total, tt.wantTotal)
+			}
+
+			var got []string
+			for _, event := range events {
+				name := ids[event.ID]
+				got = append(got, name)
+
+				venue := venues[name]
+				if venue == nil || event.DistanceKm == nil {
+					t.Errorf("event %d (%s) has no venue distance", event.ID, event.Name)
+					continue
+				}
+				want := utils.HaversineKm(monas.Lat, monas.Lng, *venue.Latitude, *venue.Longitude)
+				if math.Abs(*event.DistanceKm-want) > 0.01 {
+					t.Errorf("%s distance = %.2f km, want %.2f km", name, *event.DistanceKm, want)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("events = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("events = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// TestFindAllNearOrdersTiesBySecondarySort memastikan kunci sort setelah distance mengurutkan
+// event di venue yang sama, termasuk ketika grup jarak yang sama terpotong batas halaman
+func TestFindAllNearOrdersTiesBySecondarySort(t *testing.T) {
+	db := openPortableTestDB(t)
+	repo := NewEventRepository(db)
+
+	prefix := uniqueName("ties")
+	monas := GeoPoint{Lat: -6.1754, Lng: 106.8272}
+	nearVenue := createTestVenue(t, db, -6.1862, 106.8228)
+	shared := createTestVenue(t, db, -6.2615, 106.7838)
+	names := make(map[uint]string)
+	for _, name := range []string{"b", "close", "a", "c"} {
+		venue := shared
+		if name == "close" {
+			venue = nearVenue
+		}
+		event := createTestEvent(t, db, model.Event{Name: prefix + "-" + name, VenueID: &venue.ID})
+		names[event.ID] = name
+	}
+
+	tests := []struct {
+		name        string
+		sort        []EventSort
+		page, limit int
+		want        []string
+	}{
+		{name: "distance only keeps id order", sort: []EventSort{{Field: "distance"}},
+			page: 1, limit: 10, want: []string{"close", "b", "a", "c"}},
+		{name: "distance then name", sort: []EventSort{{Field: "distance"}, {Field: "name"}},
+			page: 1, limit: 10, want: []string{"close", "a", "b", "c"}},
+		{name: "distance then name descending", sort: []EventSort{{Field: "distance"}, {Field: "name", Desc: true}},
+			page: 1, limit: 10, want: []string{"close", "c", "b", "a"}},
+		{name: "farthest first then name", sort: []EventSort{{Field: "distance", Desc: true}, {Field: "name"}},
+			page: 1, limit: 10, want: []string{"a", "b", "c", "close"}},
+		{name: "tie group split across pages", sort: []EventSort{{Field: "distance"}, {Field: "name"}},
+			page: 2, limit: 2, want: []string{"b", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := EventFilter{Search: prefix, Near: &monas, RadiusKm: 50, Sort: tt.sort}
+			events, total, err := repo.FindAll(tt.page, tt.limit, filter)
+			if err != nil {
+				t.Fatalf("FindAll: %v", err)
+			}
+			if total != 4 {
+				t.Errorf("total = %d, want 4", total)
+			}
+			var got []string
+			for _, event := range events {
+				got = append(got, names[event.ID])
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("events = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFindAllNearAcrossAntimeridian memastikan prefilter bounding box tidak membuang venue
+// di seberang garis bujur 180°
+func TestFindAllNearAcrossAntimeridian(t *testing.T) {
+	db := openPortableTestDB(t)
+	repo := NewEventRepository(db)
+
+	prefix := uniqueName("antimeridian")
+	venue := createTestVenue(t, db, -17, 179.95)
+	event := createTestEvent(t, db, model.Event{Name: prefix, VenueID: &venue.ID})
+
+	filter := EventFilter{Search: prefix, Near: &GeoPoint{Lat: -17, Lng: -179.95}, RadiusKm: 20}
+	events, total, err := repo.FindAll(1, 10, filter)
+	if err != nil {
+		t.Fatalf("FindAll: %v", err)
+	}
+	if total != 1 || len(events) != 1 || events[0].ID != event.ID {
+		t.Fatalf("got %d event(s) (total %d), want event %d", len(events), total, event.ID)
+	}
+	if d := *events[0].DistanceKm; d < 10 || d > 11 {
+		t.Errorf("distance = %.2f km, want ~10.6 km", d)
+	}
+
+	filter.RadiusKm = 5
+	if _, total, err := repo.FindAll(1, 10, filter); err != nil || total != 0 {
+		t.Errorf("radius 5 km: total = %d, err = %v, want 0 events", total, err)
+	}
+}
+
+// TestUpdateRejectsCapacityBelowReserved memastikan batas bawah kapasitas dihitung dari counter
+// terbaru di database, bukan dari salinan event yang dibaca sebelum pembelian terjadi
+func TestUpdateRejectsCapacityBelowReserved(t *testing.T) {
+	db := openPortableTestDB(t)
+	repo := NewEventRepository(db)
+
+	event := createTestEvent(t, db, model.Event{Capacity: 10})
+	stale := *event
+	if err := db.Model(&model.Event{}).Where("id = ?", event.ID).
+		Updates(map[string]any{"sold": 4, "held": 1, "offered": 1}).Error; err != nil {
+		t.Fatalf("set counters: %v", err)
+	}
+
+	stale.Capacity = 5
+	if err := repo.Update(&stale); !errors.Is(err, ErrCapacityBelowReserved) {
+		t.Fatalf("capacity 5: err = %v, want ErrCapacityBelowReserved", err)
+	}
+	stale.Capacity = 6
+	if err := repo.Update(&stale); err != nil {
+		t.Fatalf("capacity 6: %v", err)
+	}
+}
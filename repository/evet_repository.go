@@ -1,18 +1,88 @@
 package repository
 
 import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"ticketing/dto"
 	"ticketing/model"
+	"ticketing/utils"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrCapacityAlreadyRestored dikembalikan ketika kapasitas lama sudah pernah dipulihkan
+	ErrCapacityAlreadyRestored = errors.New("legacy capacity has already been restored")
+	// ErrNoLegacyCutoff dikembalikan ketika waktu upgrade tidak tercatat dan tidak diberikan
+	ErrNoLegacyCutoff = errors.New("upgrade time of the inventory counters is not recorded; pass a cutoff time")
+	// ErrCapacityBelowReserved dikembalikan ketika kapasitas baru lebih kecil dari qty yang sudah terpakai
+	ErrCapacityBelowReserved = errors.New("capacity cannot be lower than tickets already sold, held or offered")
 )
 
 type EventRepository interface {
 	Create(event *model.Event) error
-	FindAll(page, limit int, search string) ([]model.Event, int64, error)
+	FindAll(page, limit int, filter EventFilter) ([]model.Event, int64, error)
+	FindFacets(filter EventFilter) (map[string][]dto.FacetCount, error)
 	FindByID(id uint) (*model.Event, error)
 	Update(event *model.Event) error
 	Delete(id uint) error
 	GetAvailableTickets(eventID uint) (int, error)
+	ReconcileCounters() ([]model.Event, error)
+	RestoreLegacyCapacity(before *time.Time) (time.Time, error)
+	FindStatusDue(now time.Time, limit int) ([]model.Event, error)
+	TransitionStatus(id uint, from, to model.EventStatus, override bool) (bool, error)
+	SetStatusOverride(id uint, override bool) error
+}
+
+// EventFilter adalah filter pencarian event yang sudah divalidasi service
+type EventFilter struct {
+	Search   string
+	From     *time.Time // waktu mulai >= From
+	To       *time.Time // waktu mulai < To
+	MinPrice *float64
+	MaxPrice *float64
+	Statuses []model.EventStatus
+	VenueIDs []uint
+	Cities   []string
+	// CategoryIDs sudah mencakup subkategori; event cocok jika punya salah satunya
+	CategoryIDs []uint
+	TagIDs      []uint
+	Available   *bool // true: masih ada sisa kuota, false: habis
+	Near        *GeoPoint
+	RadiusKm    float64 // dipakai bersama Near
+	Sort        []EventSort
+}
+
+// GeoPoint adalah koordinat dalam derajat desimal
+type GeoPoint struct {
+	Lat float64
+	Lng float64
+}
+
+// EventSort adalah satu kunci pengurutan; Field harus ada di eventSortColumns atau "distance"
+// (hanya untuk pencarian near dan harus menjadi kunci pertama)
+type EventSort struct {
+	Field string
+	Desc  bool
+}
+
+// eventSortColumns adalah whitelist kunci sort ke kolom tabel events
+var eventSortColumns = map[string]string{
+	"date":       "starts_at",
+	"price":      "price",
+	"popularity": "sold",
+	"name":       "name",
+	"created":    "created_at",
+}
+
+// IsEventSortField mengembalikan apakah field boleh dipakai untuk sort
+func IsEventSortField(field string) bool {
+	_, ok := eventSortColumns[field]
+	return ok
 }
 
 type eventRepository struct {
@@ -23,39 +93,372 @@ func NewEventRepository(db *gorm.DB) EventRepository {
 	return &eventRepository{db: db}
 }
 
+// Create menyimpan event baru; event di venue dicek kapasitas dan jadwalnya di transaksi yang sama
 func (r *eventRepository) Create(event *model.Event) error {
-	return r.db.Create(event).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkVenueInTx(tx, event, nil); err != nil {
+			return err
+		}
+		// Kategori dan tag hanya dihubungkan, tidak ikut disimpan ulang
+		return tx.Omit("Venue", "Categories.*", "Tags.*").Create(event).Error
+	})
 }
 
-func (r *eventRepository) FindAll(page, limit int, search string) ([]model.Event, int64, error) {
+func (r *eventRepository) FindAll(page, limit int, filter EventFilter) ([]model.Event, int64, error) {
 	var events []model.Event
 	var total int64
 
-	query := r.db.Model(&model.Event{})
+	query, nearby, err := r.filtered(filter, "")
+	if err != nil {
+		return nil, 0, err
+	}
 
-	if search != "" {
-		query = query.Where("name LIKE ? OR description LIKE ? OR location LIKE ?",
-			"%"+search+"%", "%"+search+"%", "%"+search+"%")
+	sorts := filter.Sort
+	if len(sorts) == 0 {
+		sorts = []EventSort{{Field: "date"}}
+		if filter.Near != nil {
+			sorts = []EventSort{{Field: "distance"}}
+		}
 	}
 
-	err := query.Count(&total).Error
-	if err != nil {
+	offset := (page - 1) * limit
+	if sorts[0].Field == "distance" {
+		return r.findByDistance(nearby, sorts, offset, limit)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	offset := (page - 1) * limit
-	err = query.Offset(offset).Limit(limit).Find(&events).Error
+	for _, key := range sorts {
+		query = query.Order(clause.OrderByColumn{
+			Column: clause.Column{Table: "events", Name: eventSortColumns[key.Field]},
+			Desc:   key.Desc,
+		})
+	}
+
+	err = query.Order("events.id").Offset(offset).Limit(limit).
+		Preload("TicketTypes").Preload("Venue").Preload("Categories").Preload("Tags").Find(&events).Error
+	setDistances(events, nearby)
 	return events, total, err
 }
 
+// nearbyEvent adalah event hasil pencarian near beserta jaraknya dari titik pencarian
+type nearbyEvent struct {
+	ID         uint
+	Latitude   float64
+	Longitude  float64
+	DistanceKm float64
+}
+
+// filtered membangun query event yang sudah difilter. Untuk pencarian near, kandidat
+// diambil dengan prefilter bounding box di SQL (bisa memakai index koordinat venue dan
+// tidak butuh fungsi trigonometri/ekstensi spasial), lalu jarak tepat dihitung dengan
+// haversine di Go; query dibatasi ke event yang benar-benar di dalam radius.
+func (r *eventRepository) filtered(filter EventFilter, except string) (*gorm.DB, []nearbyEvent, error) {
+	query := r.applyFilter(r.db.Model(&model.Event{}), filter, except)
+	if filter.Near == nil {
+		return query, nil, nil
+	}
+
+	minLat, maxLat, minLng, maxLng := utils.BoundingBox(filter.Near.Lat, filter.Near.Lng, filter.RadiusKm)
+	candidates := r.applyFilter(r.db.Model(&model.Event{}), filter, except).
+		Joins("JOIN venues ON venues.id = events.venue_id AND venues.deleted_at IS NULL").
+		Where("venues.latitude BETWEEN ? AND ?", minLat, maxLat)
+	if minLng <= maxLng {
+		candidates = candidates.Where("venues.longitude BETWEEN ? AND ?", minLng, maxLng)
+	} else {
+		// Kotak melewati garis bujur 180°
+		candidates = candidates.Where("venues.longitude >= ? OR venues.longitude <= ?", minLng, maxLng)
+	}
+
+	var rows []nearbyEvent
+	if err := candidates.Select("events.id AS id, venues.latitude AS latitude, venues.longitude AS longitude").
+		Scan(&rows).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var nearby []nearbyEvent
+	for _, row := range rows {
+		row.DistanceKm = utils.HaversineKm(filter.Near.Lat, filter.Near.Lng, row.Latitude, row.Longitude)
+		if row.DistanceKm <= filter.RadiusKm {
+			nearby = append(nearby, row)
+		}
+	}
+	sort.Slice(nearby, func(i, j int) bool {
+		if nearby[i].DistanceKm != nearby[j].DistanceKm {
+			return nearby[i].DistanceKm < nearby[j].DistanceKm
+		}
+		return nearby[i].ID < nearby[j].ID
+	})
+
+	ids := make([]uint, len(nearby))
+	for i := range nearby {
+		ids[i] = nearby[i].ID
+	}
+	return query.Where("events.id IN ?", ids), nearby, nil
+}
+
+// findByDistance mengambil satu halaman event near yang diurutkan berdasarkan jarak. Event
+// dengan jarak sama (mis. di venue yang sama) diurutkan dengan kunci sort berikutnya di SQL.
+func (r *eventRepository) findByDistance(nearby []nearbyEvent, sorts []EventSort, offset, limit int) ([]model.Event, int64, error) {
+	total := int64(len(nearby))
+	if sorts[0].Desc {
+		reversed := make([]nearbyEvent, len(nearby))
+		for i := range nearby {
+			reversed[len(nearby)-1-i] = nearby[i]
+		}
+		nearby = reversed
+	}
+	if offset >= len(nearby) {
+		return []model.Event{}, total, nil
+	}
+	end := min(offset+limit, len(nearby))
+
+	// Jendela diperlebar sampai batas grup jarak yang sama agar grup tidak terpotong di tepi halaman
+	start := offset
+	for start > 0 && nearby[start-1].DistanceKm == nearby[offset].DistanceKm {
+		start--
+	}
+	windowEnd := end
+	for windowEnd < len(nearby) && nearby[windowEnd].DistanceKm == nearby[end-1].DistanceKm {
+		windowEnd++
+	}
+	window := nearby[start:windowEnd]
+
+	ids := make([]uint, len(window))
+	group := make(map[uint]int, len(window))
+	g := 0
+	for i, row := range window {
+		if i > 0 && row.DistanceKm != window[i-1].DistanceKm {
+			g++
+		}
+		ids[i] = row.ID
+		group[row.ID] = g
+	}
+
+	query := r.db.Model(&model.Event{}).Where("events.id IN ?", ids)
+	for _, key := range sorts[1:] {
+		query = query.Order(clause.OrderByColumn{
+			Column: clause.Column{Table: "events", Name: eventSortColumns[key.Field]},
+			Desc:   key.Desc,
+		})
+	}
+	var ordered []uint
+	if err := query.Order("events.id").Pluck("events.id", &ordered).Error; err != nil {
+		return nil, 0, err
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return group[ordered[i]] < group[ordered[j]]
+	})
+
+	// Event yang terhapus di antara dua query tidak ikut di ordered
+	ids = ordered[min(offset-start, len(ordered)):min(end-start, len(ordered))]
+	position := make(map[uint]int, len(ids))
+	for i, id := range ids {
+		position[id] = i
+	}
+
+	var events []model.Event
+	if err := r.db.Preload("TicketTypes").Preload("Venue").Preload("Categories").Preload("Tags").
+		Where("id IN ?", ids).Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return position[events[i].ID] < position[events[j].ID]
+	})
+	setDistances(events, nearby)
+	return events, total, nil
+}
+
+// setDistances mengisi DistanceKm event dari hasil pencarian near (dibulatkan ke 10 meter)
+func setDistances(events []model.Event, nearby []nearbyEvent) {
+	if nearby == nil {
+		return
+	}
+	distances := make(map[uint]float64, len(nearby))
+	for _, row := range nearby {
+		distances[row.ID] = row.DistanceKm
+	}
+	for i := range events {
+		if distance, ok := distances[events[i].ID]; ok {
+			rounded := math.Round(distance*100) / 100
+			events[i].DistanceKm = &rounded
+		}
+	}
+}
+
+// FindFacets menghitung jumlah event per nilai facet (city, status, category, tag). Facet
+// category menghitung tautan langsung (tanpa digulung ke kategori induk). Setiap facet memakai
+// semua filter kecuali filternya sendiri, sehingga pilihan lain di facet yang sama tetap
+// terlihat beserta jumlahnya.
+func (r *eventRepository) FindFacets(filter EventFilter) (map[string][]dto.FacetCount, error) {
+	facets := make(map[string][]dto.FacetCount)
+
+	cityQuery, _, err := r.filtered(filter, "city")
+	if err != nil {
+		return nil, err
+	}
+	city := []dto.FacetCount{}
+	err = cityQuery.
+		Joins("JOIN venues ON venues.id = events.venue_id AND venues.deleted_at IS NULL").
+		Select("venues.city AS value, COUNT(*) AS count").
+		Group("venues.city").Order("count DESC, value").
+		Scan(&city).Error
+	if err != nil {
+		return nil, err
+	}
+	facets["city"] = city
+
+	statusQuery, _, err := r.filtered(filter, "status")
+	if err != nil {
+		return nil, err
+	}
+	status := []dto.FacetCount{}
+	err = statusQuery.
+		Select("events.status AS value, COUNT(*) AS count").
+		Group("events.status").Order("count DESC, value").
+		Scan(&status).Error
+	if err != nil {
+		return nil, err
+	}
+	facets["status"] = status
+
+	categoryQuery, _, err := r.filtered(filter, "category")
+	if err != nil {
+		return nil, err
+	}
+	category := []dto.FacetCount{}
+	err = categoryQuery.
+		Joins("JOIN event_categories ON event_categories.event_id = events.id").
+		Joins("JOIN categories ON categories.id = event_categories.category_id AND categories.deleted_at IS NULL").
+		Select("categories.slug AS value, COUNT(*) AS count").
+		Group("categories.slug").Order("count DESC, value").
+		Scan(&category).Error
+	if err != nil {
+		return nil, err
+	}
+	facets["category"] = category
+
+	tagQuery, _, err := r.filtered(filter, "tag")
+	if err != nil {
+		return nil, err
+	}
+	tag := []dto.FacetCount{}
+	err = tagQuery.
+		Joins("JOIN event_tags ON event_tags.event_id = events.id").
+		Joins("JOIN tags ON tags.id = event_tags.tag_id AND tags.deleted_at IS NULL").
+		Select("tags.slug AS value, COUNT(*) AS count").
+		Group("tags.slug").Order("count DESC, value").
+		Scan(&tag).Error
+	if err != nil {
+		return nil, err
+	}
+	facets["tag"] = tag
+
+	return facets, nil
+}
+
+// applyFilter menerapkan filter pencarian event; filter bernama except dilewati (untuk facet)
+func (r *eventRepository) applyFilter(query *gorm.DB, filter EventFilter, except string) *gorm.DB {
+	if filter.Search != "" {
+		like := "%" + filter.Search + "%"
+		query = query.Where("events.name LIKE ? OR events.description LIKE ? OR events.location LIKE ? OR events.venue_id IN (?)",
+			like, like, like,
+			r.db.Model(&model.Venue{}).Select("id").Where("name LIKE ? OR city LIKE ?", like, like))
+	}
+	if filter.From != nil {
+		query = query.Where("events.starts_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("events.starts_at < ?", *filter.To)
+	}
+	if filter.MinPrice != nil {
+		query = query.Where("events.price >= ?", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		query = query.Where("events.price <= ?", *filter.MaxPrice)
+	}
+	if len(filter.Statuses) > 0 && except != "status" {
+		query = query.Where("events.status IN ?", filter.Statuses)
+	}
+	if len(filter.VenueIDs) > 0 {
+		query = query.Where("events.venue_id IN ?", filter.VenueIDs)
+	}
+	if len(filter.Cities) > 0 && except != "city" {
+		query = query.Where("events.venue_id IN (?)",
+			r.db.Model(&model.Venue{}).Select("id").Where("city IN ?", filter.Cities))
+	}
+	if len(filter.CategoryIDs) > 0 && except != "category" {
+		query = query.Where("events.id IN (?)",
+			r.db.Table("event_categories").Select("event_id").Where("category_id IN ?", filter.CategoryIDs))
+	}
+	if len(filter.TagIDs) > 0 && except != "tag" {
+		query = query.Where("events.id IN (?)",
+			r.db.Table("event_tags").Select("event_id").Where("tag_id IN ?", filter.TagIDs))
+	}
+	if filter.Available != nil {
+		if *filter.Available {
+			query = query.Where("events.capacity - events.sold - events.held - events.offered > 0")
+		} else {
+			query = query.Where("events.capacity - events.sold - events.held - events.offered <= 0")
+		}
+	}
+	return query
+}
+
 func (r *eventRepository) FindByID(id uint) (*model.Event, error) {
 	var event model.Event
-	err := r.db.Preload("Tickets").First(&event, id).Error
+	err := r.db.Preload("Tickets").Preload("TicketTypes").Preload("Venue").Preload("Categories").Preload("Tags").
+		Preload("Questions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position, id")
+		}).First(&event, id).Error
 	return &event, err
 }
 
+// eventUpdateOmit adalah kolom yang tidak boleh ditimpa saat admin mengubah event: counter
+// sold/held hanya diubah lewat transaksi tiket, status hanya lewat TransitionStatus
+var eventUpdateOmit = []string{"sold", "held", "offered", "status", "status_override", "status_changed_at",
+	"series_id", "Venue", "Categories", "Tags", "Tickets", "TicketTypes", "Questions"}
+
 func (r *eventRepository) Update(event *model.Event) error {
-	return r.db.Save(event).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkCapacityInTx(tx, event); err != nil {
+			return err
+		}
+		if err := checkVenueInTx(tx, event, nil); err != nil {
+			return err
+		}
+		return saveEventInTx(tx, event)
+	})
+}
+
+// checkCapacityInTx mengunci baris event (kunci yang sama dengan purchaseInTx) lalu memastikan
+// kapasitas baru masih memuat counter sold/held/offered terbaru, sehingga pembelian yang
+// berjalan bersamaan tidak bisa membuat event oversold
+func checkCapacityInTx(tx *gorm.DB, event *model.Event) error {
+	var current model.Event
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "sold", "held", "offered").
+		First(&current, event.ID).Error; err != nil {
+		return err
+	}
+	if event.Capacity < current.Sold+current.Held+current.Offered {
+		return ErrCapacityBelowReserved
+	}
+	return nil
+}
+
+// saveEventInTx menyimpan perubahan event lalu mengganti tautan kategori dan tag
+// dengan isi event.Categories/event.Tags
+func saveEventInTx(tx *gorm.DB, event *model.Event) error {
+	if err := tx.Omit(eventUpdateOmit...).Save(event).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(event).Association("Categories").Replace(event.Categories); err != nil {
+		return err
+	}
+	return tx.Model(event).Association("Tags").Replace(event.Tags)
 }
 
 func (r *eventRepository) Delete(id uint) error {
@@ -68,12 +471,173 @@ func (r *eventRepository) GetAvailableTickets(eventID uint) (int, error) {
 		return 0, err
 	}
 
-	var bookedTickets int64
-	if err := r.db.Model(&model.Ticket{}).
-		Where("event_id = ? AND status = ?", eventID, model.Booked).
-		Count(&bookedTickets).Error; err != nil {
-		return 0, err
+	return event.Available(), nil
+}
+
+// ReconcileCounters menghitung ulang kolom sold/held setiap event dari tabel tickets
+// (dan offered dari tawaran waitlist yang masih aktif)
+// dan mengembalikan event yang counternya berubah.
+func (r *eventRepository) ReconcileCounters() ([]model.Event, error) {
+	var ids []uint
+	if err := r.db.Model(&model.Event{}).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	var changed []model.Event
+	for _, id := range ids {
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			var event model.Event
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&event, id).Error; err != nil {
+				return err
+			}
+
+			held, err := sumQty(tx, id, model.Available)
+			if err != nil {
+				return err
+			}
+			sold, err := sumQty(tx, id, model.Booked)
+			if err != nil {
+				return err
+			}
+
+			var offered int64
+			if err := tx.Model(&model.WaitlistEntry{}).Select("COALESCE(SUM(qty), 0)").
+				Where("event_id = ? AND status = ?", id, model.WaitlistOffered).
+				Scan(&offered).Error; err != nil {
+				return err
+			}
+
+			if err := reconcileTicketTypes(tx, id); err != nil {
+				return err
+			}
+
+			if event.Held == held && event.Sold == sold && event.Offered == int(offered) {
+				return nil
+			}
+
+			event.Held, event.Sold, event.Offered = held, sold, int(offered)
+			changed = append(changed, event)
+			return tx.Model(&event).Updates(map[string]interface{}{"held": held, "sold": sold, "offered": offered}).Error
+		})
+		if err != nil {
+			return changed, err
+		}
+	}
+
+	return changed, nil
+}
+
+// RestoreLegacyCapacity mengembalikan kapasitas awal event yang dulu dikurangi setiap
+// pembelian tiket (termasuk yang kemudian dibatalkan). Hanya tiket yang dibuat sebelum
+// before dihitung; jika nil, dipakai waktu upgrade yang dicatat migrasi. Pemulihan dicatat
+// di data_migrations sehingga pemanggilan berikutnya mengembalikan ErrCapacityAlreadyRestored.
+func (r *eventRepository) RestoreLegacyCapacity(before *time.Time) (time.Time, error) {
+	var cutoff time.Time
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var done int64
+		if err := tx.Model(&model.DataMigration{}).
+			Where("name = ?", model.LegacyCapacityMigration).
+			Count(&done).Error; err != nil {
+			return err
+		}
+		if done > 0 {
+			return ErrCapacityAlreadyRestored
+		}
+
+		if before != nil {
+			cutoff = *before
+		} else {
+			var upgrade model.DataMigration
+			err := tx.Where("name = ?", model.InventoryCountersMigration).First(&upgrade).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNoLegacyCutoff
+			}
+			if err != nil {
+				return err
+			}
+			cutoff = upgrade.AppliedAt
+		}
+
+		// Unique index pada name juga menggagalkan proses kedua yang berjalan bersamaan
+		if err := tx.Create(&model.DataMigration{Name: model.LegacyCapacityMigration, AppliedAt: time.Now()}).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`UPDATE events SET capacity = capacity +
+			(SELECT COALESCE(SUM(tickets.qty), 0) FROM tickets
+				WHERE tickets.event_id = events.id AND tickets.created_at < ?)`, cutoff).Error
+	})
+	return cutoff, err
+}
+
+// FindStatusDue mencari event otomatis (tanpa override admin) yang statusnya sudah
+// tertinggal dari jadwal: upcoming yang sudah mulai, atau ongoing yang sudah selesai.
+func (r *eventRepository) FindStatusDue(now time.Time, limit int) ([]model.Event, error) {
+	var events []model.Event
+	err := r.db.Where("status_override = ?", false).
+		Where("(status = ? AND starts_at <= ?) OR (status = ? AND ends_at <= ?)",
+			model.Upcoming, now, model.Ongoing, now).
+		Order("starts_at").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+// TransitionStatus mengubah status hanya jika status di database masih from
+// (dan, untuk transisi otomatis, event belum di-override admin). Update bersyarat
+// ini membuat transisi aman dijalankan oleh beberapa instance sekaligus: hanya satu
+// yang mendapat true dan boleh menjalankan hook transisi.
+func (r *eventRepository) TransitionStatus(id uint, from, to model.EventStatus, override bool) (bool, error) {
+	query := r.db.Model(&model.Event{}).Where("id = ? AND status = ?", id, from)
+	if !override {
+		query = query.Where("status_override = ?", false)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"status":            to,
+		"status_override":   override,
+		"status_changed_at": time.Now(),
+	})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *eventRepository) SetStatusOverride(id uint, override bool) error {
+	return r.db.Model(&model.Event{}).Where("id = ?", id).Update("status_override", override).Error
+}
+
+func sumQty(db *gorm.DB, eventID uint, status model.TicketStatus) (int, error) {
+	var total int64
+	err := db.Model(&model.Ticket{}).
+		Select("COALESCE(SUM(qty), 0)").
+		Where("event_id = ? AND status = ?", eventID, status).
+		Scan(&total).Error
+	return int(total), err
+}
+
+// reconcileTicketTypes menghitung ulang counter sold/held setiap tier milik event
+func reconcileTicketTypes(tx *gorm.DB, eventID uint) error {
+	var ticketTypes []model.TicketType
+	if err := tx.Where("event_id = ?", eventID).Find(&ticketTypes).Error; err != nil {
+		return err
+	}
+
+	for _, tt := range ticketTypes {
+		var held, sold int64
+		if err := tx.Model(&model.Ticket{}).Select("COALESCE(SUM(qty), 0)").
+			Where("ticket_type_id = ? AND status = ?", tt.ID, model.Available).
+			Scan(&held).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Ticket{}).Select("COALESCE(SUM(qty), 0)").
+			Where("ticket_type_id = ? AND status = ?", tt.ID, model.Booked).
+			Scan(&sold).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&tt).Updates(map[string]interface{}{"held": held, "sold": sold}).Error; err != nil {
+			return err
+		}
 	}
 
-	return event.Capacity - int(bookedTickets), nil
+	return nil
 }
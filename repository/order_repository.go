@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"ticketing/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrOrderNotPayable dikembalikan ketika order tidak lagi dalam status pending
+var ErrOrderNotPayable = errors.New("order is not awaiting payment")
+
+type OrderRepository interface {
+	FindOrCreateCart(userID uint) (*model.Order, error)
+	FindByID(id uint) (*model.Order, error)
+	FindAll(page, limit int, userID uint) ([]model.Order, int64, error)
+	SaveItem(item *model.OrderItem) error
+	DeleteItem(orderID, itemID uint) error
+	UpdateTotal(orderID uint, total float64) error
+	Checkout(order *model.Order, tickets []*model.Ticket, expiresAt time.Time) error
+	CheckoutSeriesPass(order *model.Order, tickets []*model.Ticket, shares []float64, expiresAt time.Time) error
+	UpdatePaymentStatus(orderID uint, paymentStatus model.PaymentStatus, ticketStatus model.TicketStatus) error
+}
+
+type orderRepository struct {
+	db *gorm.DB
+}
+
+func NewOrderRepository(db *gorm.DB) OrderRepository {
+	return &orderRepository{db: db}
+}
+
+// FindOrCreateCart mengunci baris user lebih dulu sehingga permintaan paralel dari user
+// yang sama tidak membuat dua keranjang
+func (r *orderRepository) FindOrCreateCart(userID uint) (*model.Order, error) {
+	var order model.Order
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").First(&model.User{}, userID).Error; err != nil {
+			return err
+		}
+
+		return tx.Where(model.Order{UserID: userID, Status: model.OrderCart}).
+			Order("id").FirstOrCreate(&order).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.FindByID(order.ID)
+}
+
+func (r *orderRepository) FindByID(id uint) (*model.Order, error) {
+	var order model.Order
+	err := r.db.Preload("Items.Event").Preload("Items.TicketType").
+		Preload("Tickets.Event").Preload("Tickets.TicketType").Preload("Tickets.Seats").
+		Preload("Tickets.Passes", orderPasses).Preload("Tickets.Answers.Question", withDeleted).
+		First(&order, id).Error
+	return &order, err
+}
+
+func (r *orderRepository) FindAll(page, limit int, userID uint) ([]model.Order, int64, error) {
+	var orders []model.Order
+	var total int64
+
+	query := r.db.Model(&model.Order{}).Where("user_id = ? AND status <> ?", userID, model.OrderCart)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("id DESC").Offset(offset).Limit(limit).
+		Preload("Items.Event").Preload("Items.TicketType").
+		Find(&orders).Error
+	return orders, total, err
+}
+
+func (r *orderRepository) SaveItem(item *model.OrderItem) error {
+	return r.db.Omit("Event", "TicketType").Save(item).Error
+}
+
+func (r *orderRepository) DeleteItem(orderID, itemID uint) error {
+	result := r.db.Where("order_id = ?", orderID).Delete(&model.OrderItem{}, itemID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *orderRepository) UpdateTotal(orderID uint, total float64) error {
+	return r.db.Model(&model.Order{}).Where("id = ?", orderID).Update("total", total).Error
+}
+
+// Checkout meng-hold semua baris keranjang dalam satu transaksi: jika salah satu
+// event/tier habis, tidak ada tiket yang dibuat sama sekali.
+func (r *orderRepository) Checkout(order *model.Order, tickets []*model.Ticket, expiresAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var locked model.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, order.ID).Error; err != nil {
+			return err
+		}
+		if locked.Status != model.OrderCart {
+			return errors.New("cart has already been checked out")
+		}
+
+		// Kunci event selalu dalam urutan ID yang sama untuk menghindari deadlock
+		sort.SliceStable(tickets, func(i, j int) bool {
+			return tickets[i].EventID < tickets[j].EventID
+		})
+
+		total := 0.0
+		for _, ticket := range tickets {
+			ticket.OrderID = &order.ID
+			ticket.ExpiresAt = &expiresAt
+			if err := purchaseInTx(tx, ticket, nil); err != nil {
+				return err
+			}
+			total += ticket.SubTotal
+		}
+
+		order.Status = model.OrderPending
+		order.Total = total
+		order.ExpiresAt = &expiresAt
+		return tx.Model(&locked).Updates(map[string]interface{}{
+			"status":     order.Status,
+			"total":      order.Total,
+			"expires_at": order.ExpiresAt,
+		}).Error
+	})
+}
+
+// CheckoutSeriesPass membuat order pending berisi satu tiket untuk setiap kejadian seri.
+// shares (sejajar dengan tickets) adalah bagian harga series pass per tiket; selisih dengan
+// harga normal dicatat sebagai diskon. Jika satu kejadian habis, tidak ada tiket yang dibuat.
+func (r *orderRepository) CheckoutSeriesPass(order *model.Order, tickets []*model.Ticket, shares []float64, expiresAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		order.Status = model.OrderPending
+		order.ExpiresAt = &expiresAt
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+
+		// Kunci event selalu dalam urutan ID yang sama untuk menghindari deadlock
+		indexes := make([]int, len(tickets))
+		for i := range indexes {
+			indexes[i] = i
+		}
+		sort.SliceStable(indexes, func(i, j int) bool {
+			return tickets[indexes[i]].EventID < tickets[indexes[j]].EventID
+		})
+
+		total := 0.0
+		for _, i := range indexes {
+			ticket := tickets[i]
+			ticket.OrderID = &order.ID
+			ticket.ExpiresAt = &expiresAt
+			if err := purchaseInTx(tx, ticket, nil); err != nil {
+				return err
+			}
+
+			ticket.SubTotal = shares[i]
+			ticket.Discount = max(ticket.OriginalTotal-shares[i], 0)
+			ticket.OriginalTotal = ticket.SubTotal + ticket.Discount
+			if err := tx.Model(ticket).Updates(map[string]interface{}{
+				"original_total": ticket.OriginalTotal,
+				"discount":       ticket.Discount,
+				"sub_total":      ticket.SubTotal,
+			}).Error; err != nil {
+				return err
+			}
+			total += ticket.SubTotal
+		}
+
+		order.Total = total
+		return tx.Model(order).Update("total", total).Error
+	})
+}
+
+// UpdatePaymentStatus memindahkan semua tiket order sekaligus; gagal satu, batal semua
+func (r *orderRepository) UpdatePaymentStatus(orderID uint, paymentStatus model.PaymentStatus, ticketStatus model.TicketStatus) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var order model.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, orderID).Error; err != nil {
+			return err
+		}
+		if order.Status != model.OrderPending {
+			return ErrOrderNotPayable
+		}
+
+		var tickets []model.Ticket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("order_id = ?", orderID).
+			Order("id").
+			Find(&tickets).Error; err != nil {
+			return err
+		}
+
+		for i := range tickets {
+			if err := applyTransition(tx, &tickets[i], paymentStatus, ticketStatus); err != nil {
+				return err
+			}
+		}
+
+		status := model.OrderPaid
+		if ticketStatus == model.Cancelled {
+			status = model.OrderCancelled
+		}
+		return tx.Model(&order).Updates(map[string]interface{}{
+			"status":     status,
+			"expires_at": nil,
+		}).Error
+	})
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"sync"
+	"testing"
+
+	"ticketing/model"
+)
+
+// TestFindOrCreateCartIsUniquePerUser memastikan add-to-cart paralel memakai satu keranjang
+func TestFindOrCreateCartIsUniquePerUser(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewOrderRepository(db)
+	user := createTestUser(t, db)
+
+	const requests = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	start := make(chan struct{})
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if _, err := repo.FindOrCreateCart(user.ID); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	var carts int64
+	if err := db.Model(&model.Order{}).
+		Where("user_id = ? AND status = ?", user.ID, model.OrderCart).
+		Count(&carts).Error; err != nil {
+		t.Fatalf("count carts: %v", err)
+	}
+	if carts != 1 {
+		t.Errorf("carts = %d, want 1", carts)
+	}
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"errors"
+	"ticketing/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrPassCheckedIn dikembalikan saat attendee pass yang dipindai sudah pernah masuk
+var ErrPassCheckedIn = errors.New("attendee pass has already been checked in")
+
+type PassRepository interface {
+	FindByTicket(ticketID uint) ([]model.AttendeePass, error)
+	FindByID(ticketID, passID uint) (*model.AttendeePass, error)
+	EnsurePasses(ticket *model.Ticket) error
+	Update(pass *model.AttendeePass) error
+}
+
+type passRepository struct {
+	db *gorm.DB
+}
+
+func NewPassRepository(db *gorm.DB) PassRepository {
+	return &passRepository{db: db}
+}
+
+func (r *passRepository) FindByTicket(ticketID uint) ([]model.AttendeePass, error) {
+	var passes []model.AttendeePass
+	err := r.db.Where("ticket_id = ?", ticketID).Order("seq").Find(&passes).Error
+	return passes, err
+}
+
+func (r *passRepository) FindByID(ticketID, passID uint) (*model.AttendeePass, error) {
+	var pass model.AttendeePass
+	err := r.db.Where("ticket_id = ?", ticketID).First(&pass, passID).Error
+	return &pass, err
+}
+
+// EnsurePasses membuat pass yang belum ada untuk tiket yang dibeli sebelum fitur ini ada
+func (r *passRepository) EnsurePasses(ticket *model.Ticket) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var locked model.Ticket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, ticket.ID).Error; err != nil {
+			return err
+		}
+		return ensurePasses(tx, &locked)
+	})
+}
+
+func (r *passRepository) Update(pass *model.AttendeePass) error {
+	return r.db.Model(pass).Select("name", "email", "fields").Updates(pass).Error
+}
+
+// orderPasses dipakai saat preload agar pass selalu berurutan sesuai seq
+func orderPasses(db *gorm.DB) *gorm.DB {
+	return db.Order("seq")
+}
+
+// createPasses memecah tiket menjadi satu pass per orang; pass pertama atas nama pembeli
+func createPasses(tx *gorm.DB, ticket *model.Ticket) error {
+	var owner model.User
+	if err := tx.Select("id", "name", "email").First(&owner, ticket.UserID).Error; err != nil {
+		return err
+	}
+
+	passes := make([]model.AttendeePass, 0, ticket.Qty)
+	for seq := 1; seq <= ticket.Qty; seq++ {
+		pass := model.AttendeePass{TicketID: ticket.ID, EventID: ticket.EventID, Seq: seq}
+		if seq == 1 {
+			pass.Name = owner.Name
+			pass.Email = owner.Email
+		}
+		passes = append(passes, pass)
+	}
+	return tx.Create(&passes).Error
+}
+
+// ensurePasses melengkapi pass tiket lama; pass yang dibuat menyesuaikan checked_in tiket.
+// Pemanggil harus sudah mengunci baris tiket.
+func ensurePasses(tx *gorm.DB, ticket *model.Ticket) error {
+	var count int64
+	if err := tx.Model(&model.AttendeePass{}).Where("ticket_id = ?", ticket.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if err := createPasses(tx, ticket); err != nil {
+		return err
+	}
+	if ticket.CheckedIn == 0 {
+		return nil
+	}
+	return tx.Model(&model.AttendeePass{}).
+		Where("ticket_id = ? AND seq <= ?", ticket.ID, ticket.CheckedIn).
+		Update("checked_in_at", time.Now()).Error
+}
+
+// resetPasses mengosongkan data peserta saat tiket berpindah pemilik; pass pertama
+// diisi atas nama pemilik baru dan sisanya diisi ulang oleh pemilik baru.
+func resetPasses(tx *gorm.DB, ticket *model.Ticket, ownerID uint) error {
+	if err := ensurePasses(tx, ticket); err != nil {
+		return err
+	}
+
+	var owner model.User
+	if err := tx.Select("id", "name", "email").First(&owner, ownerID).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(&model.AttendeePass{}).Where("ticket_id = ?", ticket.ID).
+		Updates(map[string]interface{}{"name": "", "email": "", "fields": nil}).Error; err != nil {
+		return err
+	}
+	return tx.Model(&model.AttendeePass{}).Where("ticket_id = ? AND seq = 1", ticket.ID).
+		Updates(map[string]interface{}{"name": owner.Name, "email": owner.Email}).Error
+}
+
+// admitPasses menandai pass yang masuk. Jika passID diisi hanya pass itu yang
+// ditandai; jika tidak, count pass pertama yang belum check-in menurut urutan.
+func admitPasses(tx *gorm.DB, ticket *model.Ticket, passID *uint, count int, gate string, at time.Time) error {
+	if err := ensurePasses(tx, ticket); err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{"checked_in_at": at, "check_in_gate": gate}
+	if passID != nil {
+		return tx.Model(&model.AttendeePass{}).Where("id = ?", *passID).Updates(updates).Error
+	}
+
+	var ids []uint
+	if err := tx.Model(&model.AttendeePass{}).
+		Where("ticket_id = ? AND checked_in_at IS NULL", ticket.ID).
+		Order("seq").Limit(count).
+		Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return tx.Model(&model.AttendeePass{}).Where("id IN ?", ids).Updates(updates).Error
+}
+
+// lockPass memuat pass milik tiket di dalam transaksi check-in
+func lockPass(tx *gorm.DB, ticket *model.Ticket, passID uint) (*model.AttendeePass, error) {
+	if err := ensurePasses(tx, ticket); err != nil {
+		return nil, err
+	}
+	var pass model.AttendeePass
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("ticket_id = ?", ticket.ID).First(&pass, passID).Error
+	return &pass, err
+}
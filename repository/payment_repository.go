@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"ticketing/model"
+
+	"gorm.io/gorm"
+)
+
+type PaymentRepository interface {
+	Create(payment *model.Payment) error
+	FindByProviderRef(provider, ref string) (*model.Payment, error)
+	// Settle memindahkan payment dari pending ke status akhir; false jika sudah diproses sebelumnya
+	Settle(paymentID uint, status model.PaymentAttemptStatus, updates map[string]interface{}) (bool, error)
+	UpdateStatus(paymentID uint, status model.PaymentAttemptStatus, reason string) error
+	FindCapturedForTicket(ticket *model.Ticket) (*model.Payment, error)
+}
+
+type paymentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+func (r *paymentRepository) Create(payment *model.Payment) error {
+	return r.db.Create(payment).Error
+}
+
+func (r *paymentRepository) FindByProviderRef(provider, ref string) (*model.Payment, error) {
+	var payment model.Payment
+	err := r.db.Where("provider = ? AND provider_ref = ?", provider, ref).First(&payment).Error
+	return &payment, err
+}
+
+func (r *paymentRepository) Settle(paymentID uint, status model.PaymentAttemptStatus, updates map[string]interface{}) (bool, error) {
+	if updates == nil {
+		updates = map[string]interface{}{}
+	}
+	updates["status"] = status
+
+	result := r.db.Model(&model.Payment{}).
+		Where("id = ? AND status = ?", paymentID, model.PaymentAttemptPending).
+		Updates(updates)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *paymentRepository) UpdateStatus(paymentID uint, status model.PaymentAttemptStatus, reason string) error {
+	return r.db.Model(&model.Payment{}).
+		Where("id = ?", paymentID).
+		Updates(map[string]interface{}{"status": status, "failure_reason": reason}).Error
+}
+
+// FindCapturedForTicket mencari pembayaran yang berhasil untuk tiket, baik langsung maupun lewat order-nya
+func (r *paymentRepository) FindCapturedForTicket(ticket *model.Ticket) (*model.Payment, error) {
+	var payment model.Payment
+	query := r.db.Where("status IN ?", []model.PaymentAttemptStatus{model.PaymentAttemptSucceeded, model.PaymentAttemptRefunded})
+	if ticket.OrderID != nil {
+		query = query.Where("ticket_id = ? OR order_id = ?", ticket.ID, *ticket.OrderID)
+	} else {
+		query = query.Where("ticket_id = ?", ticket.ID)
+	}
+	err := query.Order("id DESC").First(&payment).Error
+	return &payment, err
+}
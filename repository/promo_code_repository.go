@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"errors"
+	"ticketing/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrPromoCodeExhausted dikembalikan ketika batas pemakaian kode promo sudah habis
+var ErrPromoCodeExhausted = errors.New("promo code redemption limit reached")
+
+type PromoCodeRepository interface {
+	Create(promo *model.PromoCode) error
+	FindAll() ([]model.PromoCode, error)
+	FindByID(id uint) (*model.PromoCode, error)
+	FindByCode(code string) (*model.PromoCode, error)
+	FindByCodeWithDeleted(code string) (*model.PromoCode, error)
+	Update(promo *model.PromoCode) error
+	Delete(id uint) error
+}
+
+type promoCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewPromoCodeRepository(db *gorm.DB) PromoCodeRepository {
+	return &promoCodeRepository{db: db}
+}
+
+func (r *promoCodeRepository) Create(promo *model.PromoCode) error {
+	// Event dan tier hanya dihubungkan, tidak ikut disimpan ulang
+	return r.db.Omit("Events.*", "TicketTypes.*").Create(promo).Error
+}
+
+func (r *promoCodeRepository) FindAll() ([]model.PromoCode, error) {
+	var promos []model.PromoCode
+	err := r.db.Preload("Events").Preload("TicketTypes").Order("id DESC").Find(&promos).Error
+	return promos, err
+}
+
+func (r *promoCodeRepository) FindByID(id uint) (*model.PromoCode, error) {
+	var promo model.PromoCode
+	err := r.db.Preload("Events").Preload("TicketTypes").First(&promo, id).Error
+	return &promo, err
+}
+
+func (r *promoCodeRepository) FindByCode(code string) (*model.PromoCode, error) {
+	var promo model.PromoCode
+	err := r.db.Preload("Events").Preload("TicketTypes").Where("code = ?", code).First(&promo).Error
+	return &promo, err
+}
+
+// FindByCodeWithDeleted ikut mencari kode yang sudah dihapus (soft delete), karena
+// unique index kolom code tetap berlaku untuk baris tersebut
+func (r *promoCodeRepository) FindByCodeWithDeleted(code string) (*model.PromoCode, error) {
+	var promo model.PromoCode
+	err := r.db.Unscoped().Where("code = ?", code).First(&promo).Error
+	return &promo, err
+}
+
+func (r *promoCodeRepository) Update(promo *model.PromoCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Counter redeemed hanya boleh diubah lewat transaksi tiket
+		if err := tx.Omit("redeemed", "Events", "TicketTypes").Save(promo).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(promo).Association("Events").Replace(promo.Events); err != nil {
+			return err
+		}
+		return tx.Model(promo).Association("TicketTypes").Replace(promo.TicketTypes)
+	})
+}
+
+func (r *promoCodeRepository) Delete(id uint) error {
+	return r.db.Delete(&model.PromoCode{}, id).Error
+}
+
+// applyPromoCode mengunci kode promo lalu mengecek ulang batas pemakaiannya di dalam
+// transaksi pembelian, sehingga pembelian paralel tidak bisa melewati batas.
+// Subtotal tiket diisi dengan total setelah diskon.
+func applyPromoCode(tx *gorm.DB, ticket *model.Ticket) error {
+	var promo model.PromoCode
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&promo, *ticket.PromoCodeID).Error; err != nil {
+		return err
+	}
+
+	if !promo.ValidAt(time.Now()) {
+		return errors.New("promo code is not valid at this time")
+	}
+	if promo.MaxRedemptions > 0 && promo.Redeemed >= promo.MaxRedemptions {
+		return ErrPromoCodeExhausted
+	}
+	if promo.MaxPerUser > 0 {
+		var used int64
+		if err := tx.Model(&model.PromoRedemption{}).
+			Where("promo_code_id = ? AND user_id = ?", promo.ID, ticket.UserID).
+			Count(&used).Error; err != nil {
+			return err
+		}
+		if used >= int64(promo.MaxPerUser) {
+			return ErrPromoCodeExhausted
+		}
+	}
+
+	ticket.Discount = promo.DiscountFor(ticket.OriginalTotal)
+	ticket.SubTotal = ticket.OriginalTotal - ticket.Discount
+	return nil
+}
+
+// redeemPromoCode mencatat pemakaian kode setelah tiket tersimpan
+func redeemPromoCode(tx *gorm.DB, ticket *model.Ticket) error {
+	redemption := &model.PromoRedemption{
+		PromoCodeID: *ticket.PromoCodeID,
+		UserID:      ticket.UserID,
+		TicketID:    ticket.ID,
+		Discount:    ticket.Discount,
+	}
+	if err := tx.Create(redemption).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&model.PromoCode{}).Where("id = ?", *ticket.PromoCodeID).
+		Update("redeemed", gorm.Expr("redeemed + 1")).Error
+}
+
+// releasePromoCode mengembalikan jatah kode promo dari tiket yang batal sebelum dibayar
+func releasePromoCode(tx *gorm.DB, ticket *model.Ticket) error {
+	result := tx.Unscoped().Where("ticket_id = ?", ticket.ID).Delete(&model.PromoRedemption{})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+
+	return tx.Model(&model.PromoCode{}).Where("id = ? AND redeemed > 0", *ticket.PromoCodeID).
+		Update("redeemed", gorm.Expr("redeemed - 1")).Error
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"ticketing/model"
+
+	"gorm.io/gorm"
+)
+
+type QuestionRepository interface {
+	FindByEvent(eventID uint) ([]model.RegistrationQuestion, error)
+	Replace(eventID uint, questions []model.RegistrationQuestion) error
+}
+
+type questionRepository struct {
+	db *gorm.DB
+}
+
+func NewQuestionRepository(db *gorm.DB) QuestionRepository {
+	return &questionRepository{db: db}
+}
+
+// withDeleted dipakai saat preload jawaban agar pertanyaan yang sudah dihapus tetap terbaca
+func withDeleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+func (r *questionRepository) FindByEvent(eventID uint) ([]model.RegistrationQuestion, error) {
+	var questions []model.RegistrationQuestion
+	err := r.db.Where("event_id = ?", eventID).Order("position, id").Find(&questions).Error
+	return questions, err
+}
+
+// Replace mengganti formulir event. Pertanyaan dicocokkan lewat key sehingga jawaban
+// yang sudah ada tetap terhubung; pertanyaan yang dihapus di-soft delete agar jawabannya
+// masih bisa diekspor.
+func (r *questionRepository) Replace(eventID uint, questions []model.RegistrationQuestion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing []model.RegistrationQuestion
+		if err := tx.Where("event_id = ?", eventID).Find(&existing).Error; err != nil {
+			return err
+		}
+		byKey := make(map[string]model.RegistrationQuestion, len(existing))
+		for _, q := range existing {
+			byKey[q.Key] = q
+		}
+
+		for i := range questions {
+			question := &questions[i]
+			question.EventID = eventID
+			if current, ok := byKey[question.Key]; ok {
+				question.ID = current.ID
+				question.CreatedAt = current.CreatedAt
+				delete(byKey, question.Key)
+			}
+			if err := tx.Save(question).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, removed := range byKey {
+			if err := tx.Delete(&removed).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"errors"
+	"math"
+	"ticketing/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrRefundExceedsBalance dikembalikan ketika refund melebihi sisa dana tiket yang belum dikembalikan
+var ErrRefundExceedsBalance = errors.New("refund amount exceeds the refundable balance of the ticket")
+
+type RefundRepository interface {
+	CreateWithinBalance(refund *model.Refund) error
+	Update(refund *model.Refund) error
+	FindByTicket(ticketID uint) ([]model.Refund, error)
+	SumByTicket(ticketID uint) (float64, error)
+	SumByPayment(paymentID uint) (float64, error)
+	FindRules(eventID uint) ([]model.CancellationRule, error)
+	ReplaceRules(eventID uint, rules []model.CancellationRule) error
+}
+
+type refundRepository struct {
+	db *gorm.DB
+}
+
+func NewRefundRepository(db *gorm.DB) RefundRepository {
+	return &refundRepository{db: db}
+}
+
+// CreateWithinBalance mengunci baris tiket lalu mencatat refund hanya jika jumlahnya masih
+// muat dalam subtotal tiket dikurangi refund pending/processed, sehingga refund paralel
+// tidak bisa bersama-sama melebihi dana yang dibayar
+func (r *refundRepository) CreateWithinBalance(refund *model.Refund) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var ticket model.Ticket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&ticket, refund.TicketID).Error; err != nil {
+			return err
+		}
+
+		var refunded float64
+		if err := tx.Model(&model.Refund{}).
+			Select("COALESCE(SUM(amount), 0)").
+			Where("ticket_id = ? AND status IN ?", ticket.ID, []model.RefundStatus{model.RefundPending, model.RefundProcessed}).
+			Scan(&refunded).Error; err != nil {
+			return err
+		}
+		if math.Round((ticket.SubTotal-refunded-refund.Amount)*100) < 0 {
+			return ErrRefundExceedsBalance
+		}
+
+		return tx.Create(refund).Error
+	})
+}
+
+func (r *refundRepository) Update(refund *model.Refund) error {
+	return r.db.Save(refund).Error
+}
+
+func (r *refundRepository) FindByTicket(ticketID uint) ([]model.Refund, error) {
+	var refunds []model.Refund
+	err := r.db.Where("ticket_id = ?", ticketID).Order("id").Find(&refunds).Error
+	return refunds, err
+}
+
+// SumByTicket menjumlahkan refund yang sedang diproses atau sudah berhasil untuk tiket
+func (r *refundRepository) SumByTicket(ticketID uint) (float64, error) {
+	var total float64
+	err := r.db.Model(&model.Refund{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("ticket_id = ? AND status IN ?", ticketID, []model.RefundStatus{model.RefundPending, model.RefundProcessed}).
+		Scan(&total).Error
+	return total, err
+}
+
+func (r *refundRepository) SumByPayment(paymentID uint) (float64, error) {
+	var total float64
+	err := r.db.Model(&model.Refund{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("payment_id = ? AND status = ?", paymentID, model.RefundProcessed).
+		Scan(&total).Error
+	return total, err
+}
+
+func (r *refundRepository) FindRules(eventID uint) ([]model.CancellationRule, error) {
+	var rules []model.CancellationRule
+	err := r.db.Where("event_id = ?", eventID).Order("hours_before DESC").Find(&rules).Error
+	return rules, err
+}
+
+func (r *refundRepository) ReplaceRules(eventID uint, rules []model.CancellationRule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("event_id = ?", eventID).Delete(&model.CancellationRule{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		return tx.Create(&rules).Error
+	})
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"ticketing/model"
+)
+
+// TestCreateWithinBalanceNeverOverRefunds mengirim refund paralel yang totalnya melebihi
+// subtotal tiket; hanya yang muat dalam sisa dana boleh tercatat
+func TestCreateWithinBalanceNeverOverRefunds(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewRefundRepository(db)
+
+	user := createTestUser(t, db)
+	event := createTestEvent(t, db, model.Event{Price: 100})
+	ticket := &model.Ticket{
+		EventID:       event.ID,
+		UserID:        user.ID,
+		Qty:           1,
+		SubTotal:      100,
+		Status:        model.Booked,
+		PaymentStatus: model.Success,
+		BookedAt:      time.Now(),
+	}
+	if err := db.Create(ticket).Error; err != nil {
+		t.Fatalf("create ticket: %v", err)
+	}
+
+	const attempts = 20 // 20 x 30 jauh di atas subtotal 100
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		created    int
+		rejected   int
+		unexpected []error
+	)
+	start := make(chan struct{})
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			err := repo.CreateWithinBalance(&model.Refund{
+				TicketID: ticket.ID,
+				Amount:   30,
+				Reason:   "test",
+				Status:   model.RefundPending,
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				created++
+			case errors.Is(err, ErrRefundExceedsBalance):
+				rejected++
+			default:
+				unexpected = append(unexpected, err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	for _, err := range unexpected {
+		t.Errorf("unexpected refund error: %v", err)
+	}
+	if created != 3 || rejected != attempts-3 {
+		t.Errorf("created = %d, rejected = %d, want 3 and %d", created, rejected, attempts-3)
+	}
+
+	total, err := repo.SumByTicket(ticket.ID)
+	if err != nil {
+		t.Fatalf("sum refunds: %v", err)
+	}
+	if total > ticket.SubTotal {
+		t.Errorf("refunded %.2f exceeds paid %.2f", total, ticket.SubTotal)
+	}
+}
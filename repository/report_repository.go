@@ -10,6 +10,8 @@ import (
 type ReportRepository interface {
 	GetSummaryReport(db *gorm.DB) (dto.SummaryReportResponse, error)
 	GetEventReports(db *gorm.DB) ([]dto.EventReportResponse, error)
+	GetPromoCodeReports(db *gorm.DB) ([]dto.PromoCodeReportResponse, error)
+	GetAttendees(db *gorm.DB, eventID uint) (*model.Event, []model.RegistrationQuestion, []model.Ticket, error)
 }
 
 type reportRepository struct {
@@ -46,17 +48,25 @@ func (r *reportRepository) GetSummaryReport(db *gorm.DB) (dto.SummaryReportRespo
 	}
 	summary.TotalTickets = int(totalTickets)
 
-	// Total Revenue
-	var totalRevenue float64
-	if err := tx.Table("tickets").
-		Select("COALESCE(SUM(events.price), 0)").
-		Joins("JOIN events ON tickets.event_id = events.id").
-		Where("tickets.status = ?", model.Booked).
-		Scan(&totalRevenue).Error; err != nil {
+	// Total Revenue: semua tiket yang pernah dibayar dikurangi refund yang sudah diproses
+	var grossRevenue, totalRefunded float64
+	if err := tx.Model(&model.Ticket{}).
+		Select("COALESCE(SUM(sub_total), 0)").
+		Where("payment_status = ?", model.Success).
+		Scan(&grossRevenue).Error; err != nil {
 		tx.Rollback()
 		return summary, err
 	}
-	summary.TotalRevenue = totalRevenue
+	if err := tx.Model(&model.Refund{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("status = ?", model.RefundProcessed).
+		Scan(&totalRefunded).Error; err != nil {
+		tx.Rollback()
+		return summary, err
+	}
+	summary.GrossRevenue = grossRevenue
+	summary.TotalRefunded = totalRefunded
+	summary.TotalRevenue = grossRevenue - totalRefunded
 
 	// Event Status Counts (Upcoming, Ongoing, Completed)
 	var upcoming, ongoing, completed int64
@@ -77,6 +87,14 @@ func (r *reportRepository) GetSummaryReport(db *gorm.DB) (dto.SummaryReportRespo
 	summary.Ongoing = int(ongoing)
 	summary.Completed = int(completed)
 
+	// Rekap per kategori
+	categories, err := categoryBreakdown(tx)
+	if err != nil {
+		tx.Rollback()
+		return summary, err
+	}
+	summary.Categories = categories
+
 	// Commit transaksi setelah semua query berhasil
 	if err := tx.Commit().Error; err != nil {
 		return summary, err
@@ -89,40 +107,246 @@ func (r *reportRepository) GetEventReports(db *gorm.DB) ([]dto.EventReportRespon
 	var reports []dto.EventReportResponse
 	var events []model.Event
 
-	// Preload tiket untuk mendapatkan semua data event dan tiket yang terkait
-	if err := db.Preload("Tickets").Find(&events).Error; err != nil {
+	if err := db.Preload("TicketTypes").Preload("Categories").Preload("Tags").Find(&events).Error; err != nil {
 		return nil, err
 	}
 
-	// Menghitung laporan untuk setiap event
-	for _, e := range events {
-		// Menghitung tiket yang terjual
-		ticketsSold := 0
-		for _, t := range e.Tickets {
-			if t.Status == model.Booked {
-				ticketsSold++
-			}
-		}
+	// Pendapatan bersih = subtotal tiket yang sudah dibayar dikurangi refund yang diproses
+	revenueByEvent, err := netRevenueBy(db, "tickets.event_id")
+	if err != nil {
+		return nil, err
+	}
+	revenueByTier, err := netRevenueBy(db, "tickets.ticket_type_id")
+	if err != nil {
+		return nil, err
+	}
 
-		// Menghitung pendapatan dan tingkat hunian
-		revenue := float64(ticketsSold) * e.Price
+	// Menghitung laporan untuk setiap event dari counter sold milik event
+	for _, e := range events {
 		occupancyRate := 0.0
 		if e.Capacity > 0 {
-			occupancyRate = float64(ticketsSold) / float64(e.Capacity) * 100
+			occupancyRate = float64(e.Sold) / float64(e.Capacity) * 100
 		}
 
-		// Menyusun laporan untuk event ini
 		report := dto.EventReportResponse{
 			EventName:     e.Name,
 			TotalCapacity: e.Capacity,
-			TicketsSold:   ticketsSold,
-			Revenue:       revenue,
+			TicketsSold:   e.Sold,
+			Revenue:       revenueByEvent[e.ID],
 			OccupancyRate: occupancyRate,
+			Categories:    []string{},
+			Tags:          []string{},
+		}
+		for _, category := range e.Categories {
+			report.Categories = append(report.Categories, category.Name)
+		}
+		for _, tag := range e.Tags {
+			report.Tags = append(report.Tags, tag.Name)
+		}
+
+		for _, tt := range e.TicketTypes {
+			report.TicketTypes = append(report.TicketTypes, dto.TicketTypeReportResponse{
+				Name:        tt.Name,
+				Price:       tt.Price,
+				Quota:       tt.Quota,
+				TicketsSold: tt.Sold,
+				Revenue:     revenueByTier[tt.ID],
+			})
 		}
 
-		// Menambahkan laporan ke dalam list
 		reports = append(reports, report)
 	}
 
 	return reports, nil
 }
+
+// categoryBreakdown merekap jumlah event, tiket terjual dan pendapatan bersih per kategori
+// dalam urutan pohon. Event di subkategori dihitung sekali di setiap kategori induknya.
+func categoryBreakdown(db *gorm.DB) ([]dto.CategoryReportResponse, error) {
+	var categories []model.Category
+	if err := db.Order("position, name, id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	var links []struct {
+		EventID    uint
+		CategoryID uint
+		Sold       int
+	}
+	if err := db.Table("event_categories").
+		Select("event_categories.event_id, event_categories.category_id, events.sold").
+		Joins("JOIN events ON events.id = event_categories.event_id AND events.deleted_at IS NULL").
+		Scan(&links).Error; err != nil {
+		return nil, err
+	}
+
+	revenueByEvent, err := netRevenueBy(db, "tickets.event_id")
+	if err != nil {
+		return nil, err
+	}
+
+	parentOf := make(map[uint]*uint, len(categories))
+	children := make(map[uint][]*model.Category)
+	for i := range categories {
+		category := &categories[i]
+		parentOf[category.ID] = category.ParentID
+		var parent uint
+		if category.ParentID != nil {
+			parent = *category.ParentID
+		}
+		children[parent] = append(children[parent], category)
+	}
+
+	// Naikkan setiap tautan event ke kategori induknya; satu event dihitung sekali per kategori
+	rows := make(map[uint]*dto.CategoryReportResponse, len(categories))
+	counted := make(map[[2]uint]bool)
+	for _, link := range links {
+		id := &link.CategoryID
+		for id != nil {
+			key := [2]uint{*id, link.EventID}
+			if counted[key] {
+				break
+			}
+			counted[key] = true
+			row := rows[*id]
+			if row == nil {
+				row = &dto.CategoryReportResponse{}
+				rows[*id] = row
+			}
+			row.Events++
+			row.TicketsSold += link.Sold
+			row.Revenue += revenueByEvent[link.EventID]
+			id = parentOf[*id]
+		}
+	}
+
+	breakdown := []dto.CategoryReportResponse{}
+	var walk func(parent uint)
+	walk = func(parent uint) {
+		for _, category := range children[parent] {
+			row := dto.CategoryReportResponse{}
+			if rows[category.ID] != nil {
+				row = *rows[category.ID]
+			}
+			row.ID = category.ID
+			row.Name = category.Name
+			row.Slug = category.Slug
+			row.ParentID = category.ParentID
+			breakdown = append(breakdown, row)
+			walk(category.ID)
+		}
+	}
+	walk(0)
+	return breakdown, nil
+}
+
+// netRevenueBy menjumlahkan pendapatan bersih per nilai kolom tiket (event atau tier)
+func netRevenueBy(db *gorm.DB, column string) (map[uint]float64, error) {
+	var gross, refunded []struct {
+		Key    uint
+		Amount float64
+	}
+
+	if err := db.Model(&model.Ticket{}).
+		Select(column+" AS `key`, COALESCE(SUM(tickets.sub_total), 0) AS amount").
+		Where("tickets.payment_status = ? AND "+column+" IS NOT NULL", model.Success).
+		Group(column).
+		Scan(&gross).Error; err != nil {
+		return nil, err
+	}
+
+	if err := db.Model(&model.Refund{}).
+		Select(column+" AS `key`, COALESCE(SUM(refunds.amount), 0) AS amount").
+		Joins("JOIN tickets ON tickets.id = refunds.ticket_id").
+		Where("refunds.status = ? AND "+column+" IS NOT NULL", model.RefundProcessed).
+		Group(column).
+		Scan(&refunded).Error; err != nil {
+		return nil, err
+	}
+
+	revenue := make(map[uint]float64, len(gross))
+	for _, row := range gross {
+		revenue[row.Key] += row.Amount
+	}
+	for _, row := range refunded {
+		revenue[row.Key] -= row.Amount
+	}
+	return revenue, nil
+}
+
+func (r *reportRepository) GetPromoCodeReports(db *gorm.DB) ([]dto.PromoCodeReportResponse, error) {
+	var promos []model.PromoCode
+	if err := db.Order("code").Find(&promos).Error; err != nil {
+		return nil, err
+	}
+
+	var redemptions []struct {
+		PromoCodeID   uint
+		Redemptions   int
+		UniqueUsers   int
+		TotalDiscount float64
+	}
+	if err := db.Model(&model.PromoRedemption{}).
+		Select("promo_code_id, COUNT(*) AS redemptions, COUNT(DISTINCT user_id) AS unique_users, COALESCE(SUM(discount), 0) AS total_discount").
+		Group("promo_code_id").
+		Scan(&redemptions).Error; err != nil {
+		return nil, err
+	}
+
+	revenueByPromo, err := netRevenueBy(db, "tickets.promo_code_id")
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []dto.PromoCodeReportResponse
+	for _, p := range promos {
+		report := dto.PromoCodeReportResponse{
+			Code:           p.Code,
+			DiscountType:   string(p.DiscountType),
+			Value:          p.Value,
+			Active:         p.IsActive(),
+			MaxRedemptions: p.MaxRedemptions,
+			Revenue:        revenueByPromo[p.ID],
+		}
+		for _, rd := range redemptions {
+			if rd.PromoCodeID == p.ID {
+				report.Redemptions = rd.Redemptions
+				report.UniqueUsers = rd.UniqueUsers
+				report.TotalDiscount = rd.TotalDiscount
+			}
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// GetAttendees mengambil tiket booked sebuah event beserta pass dan jawaban formulirnya.
+// Pertanyaan yang sudah dihapus tetap diikutkan jika masih ada jawabannya.
+func (r *reportRepository) GetAttendees(db *gorm.DB, eventID uint) (*model.Event, []model.RegistrationQuestion, []model.Ticket, error) {
+	var event model.Event
+	if err := db.First(&event, eventID).Error; err != nil {
+		return nil, nil, nil, err
+	}
+
+	var questions []model.RegistrationQuestion
+	if err := db.Unscoped().
+		Where("event_id = ?", eventID).
+		Where("deleted_at IS NULL OR id IN (?)", db.Model(&model.TicketAnswer{}).Select("question_id")).
+		Order("deleted_at IS NOT NULL, position, id").
+		Find(&questions).Error; err != nil {
+		return nil, nil, nil, err
+	}
+
+	var tickets []model.Ticket
+	if err := db.Preload("User").Preload("TicketType").
+		Preload("Passes", orderPasses).
+		Preload("Answers").
+		Where("event_id = ? AND status = ?", eventID, model.Booked).
+		Order("id").
+		Find(&tickets).Error; err != nil {
+		return nil, nil, nil, err
+	}
+
+	return &event, questions, tickets, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"errors"
+	"ticketing/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrSeatUnavailable dikembalikan ketika salah satu kursi yang diminta sudah di-hold/terjual
+var ErrSeatUnavailable = errors.New("one or more selected seats are no longer available")
+
+type SeatRepository interface {
+	ReplaceLayout(eventID uint, seats []model.Seat) error
+	FindByEvent(eventID uint) ([]model.Seat, error)
+	CountByEvent(eventID uint) (int64, error)
+}
+
+type seatRepository struct {
+	db *gorm.DB
+}
+
+func NewSeatRepository(db *gorm.DB) SeatRepository {
+	return &seatRepository{db: db}
+}
+
+// ReplaceLayout mengganti seluruh denah kursi event; ditolak jika sudah ada kursi yang terjual/di-hold
+func (r *seatRepository) ReplaceLayout(eventID uint, seats []model.Seat) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Kunci baris event yang sama dengan purchaseInTx, sehingga tidak ada kursi yang
+		// di-hold di antara pengecekan di bawah dan penghapusan denah
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").First(&model.Event{}, eventID).Error; err != nil {
+			return err
+		}
+
+		var taken int64
+		if err := tx.Model(&model.Seat{}).
+			Where("event_id = ? AND status <> ?", eventID, model.SeatAvailable).
+			Count(&taken).Error; err != nil {
+			return err
+		}
+		if taken > 0 {
+			return errors.New("cannot replace seat map while seats are held or booked")
+		}
+
+		if err := tx.Unscoped().Where("event_id = ?", eventID).Delete(&model.Seat{}).Error; err != nil {
+			return err
+		}
+
+		return tx.CreateInBatches(seats, 500).Error
+	})
+}
+
+func (r *seatRepository) FindByEvent(eventID uint) ([]model.Seat, error) {
+	var seats []model.Seat
+	err := r.db.Where("event_id = ?", eventID).Order("id").Find(&seats).Error
+	return seats, err
+}
+
+func (r *seatRepository) CountByEvent(eventID uint) (int64, error) {
+	var total int64
+	err := r.db.Model(&model.Seat{}).Where("event_id = ?", eventID).Count(&total).Error
+	return total, err
+}
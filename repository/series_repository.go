@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"ticketing/model"
+
+	"gorm.io/gorm"
+)
+
+type SeriesRepository interface {
+	Create(series *model.EventSeries) error
+	FindAll(page, limit int) ([]model.EventSeries, int64, error)
+	FindByID(id uint) (*model.EventSeries, error)
+	Update(series *model.EventSeries) error
+	FindUpcoming(seriesID uint, from time.Time) ([]model.Event, error)
+	UpdateOccurrences(events []model.Event) error
+}
+
+type seriesRepository struct {
+	db *gorm.DB
+}
+
+func NewSeriesRepository(db *gorm.DB) SeriesRepository {
+	return &seriesRepository{db: db}
+}
+
+// Create menyimpan seri beserta seluruh kejadiannya (series.Events) dalam satu transaksi.
+// Kejadian disimpan satu per satu agar setiap kejadian dicek terhadap jadwal venue,
+// termasuk terhadap kejadian sebelumnya dari seri yang sama.
+func (r *seriesRepository) Create(series *model.EventSeries) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Events").Create(series).Error; err != nil {
+			return err
+		}
+		for i := range series.Events {
+			event := &series.Events[i]
+			event.SeriesID = &series.ID
+			if err := checkVenueInTx(tx, event, nil); err != nil {
+				return err
+			}
+			if err := tx.Omit("Venue", "Categories.*", "Tags.*").Create(event).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *seriesRepository) FindAll(page, limit int) ([]model.EventSeries, int64, error) {
+	var series []model.EventSeries
+	var total int64
+
+	query := r.db.Model(&model.EventSeries{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("id").Offset(offset).Limit(limit).Find(&series).Error
+	return series, total, err
+}
+
+func (r *seriesRepository) FindByID(id uint) (*model.EventSeries, error) {
+	var series model.EventSeries
+	err := r.db.Preload("Events", func(db *gorm.DB) *gorm.DB {
+		return db.Order("starts_at")
+	}).First(&series, id).Error
+	return &series, err
+}
+
+func (r *seriesRepository) Update(series *model.EventSeries) error {
+	return r.db.Omit("Events").Save(series).Error
+}
+
+// FindUpcoming mengembalikan kejadian seri yang masih upcoming dan dimulai pada/sesudah from
+func (r *seriesRepository) FindUpcoming(seriesID uint, from time.Time) ([]model.Event, error) {
+	var events []model.Event
+	err := r.db.Preload("TicketTypes").Preload("Categories").Preload("Tags").Preload("Questions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position, id")
+	}).
+		Where("series_id = ? AND status = ? AND starts_at >= ?", seriesID, model.Upcoming, from).
+		Order("starts_at").
+		Find(&events).Error
+	return events, err
+}
+
+// UpdateOccurrences menyimpan perubahan beberapa kejadian sekaligus; gagal satu, batal semua.
+// Jadwal lama kejadian dalam batch diabaikan saat cek venue karena semuanya ikut bergeser;
+// tabrakan di antara kejadian batch sendiri dicek terpisah.
+func (r *seriesRepository) UpdateOccurrences(events []model.Event) error {
+	ids := make([]uint, len(events))
+	for i := range events {
+		ids[i] = events[i].ID
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range events {
+			event := &events[i]
+			if err := checkCapacityInTx(tx, event); err != nil {
+				if errors.Is(err, ErrCapacityBelowReserved) {
+					return fmt.Errorf("%w for occurrence %d", err, event.ID)
+				}
+				return err
+			}
+			if err := checkVenueInTx(tx, event, ids); err != nil {
+				return err
+			}
+			for j := range events[:i] {
+				other := &events[j]
+				if sameVenue(event, other) && event.StartsAt.Before(other.EndsAt) && other.StartsAt.Before(event.EndsAt) {
+					return fmt.Errorf("%w: occurrences %d and %d overlap", ErrVenueDoubleBooked, other.ID, event.ID)
+				}
+			}
+			if err := saveEventInTx(tx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func sameVenue(a, b *model.Event) bool {
+	return a.VenueID != nil && b.VenueID != nil && *a.VenueID == *b.VenueID
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"ticketing/model"
+
+	"gorm.io/gorm"
+)
+
+type TagRepository interface {
+	Create(tag *model.Tag) error
+	FindAll() ([]model.Tag, error)
+	FindByID(id uint) (*model.Tag, error)
+	FindBySlugs(slugs []string) ([]model.Tag, error)
+	FindOrCreate(tags []model.Tag) ([]model.Tag, error)
+	Update(tag *model.Tag) error
+	Delete(id uint) error
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) Create(tag *model.Tag) error {
+	return r.db.Create(tag).Error
+}
+
+func (r *tagRepository) FindAll() ([]model.Tag, error) {
+	var tags []model.Tag
+	err := r.db.Order("name, id").Find(&tags).Error
+	return tags, err
+}
+
+func (r *tagRepository) FindByID(id uint) (*model.Tag, error) {
+	var tag model.Tag
+	err := r.db.First(&tag, id).Error
+	return &tag, err
+}
+
+func (r *tagRepository) FindBySlugs(slugs []string) ([]model.Tag, error) {
+	var tags []model.Tag
+	err := r.db.Where("slug IN ?", slugs).Find(&tags).Error
+	return tags, err
+}
+
+// FindOrCreate mengembalikan tag dengan slug yang sama, atau membuatnya jika belum ada
+func (r *tagRepository) FindOrCreate(tags []model.Tag) ([]model.Tag, error) {
+	result := make([]model.Tag, 0, len(tags))
+	for _, tag := range tags {
+		var existing model.Tag
+		if err := r.db.Where(model.Tag{Slug: tag.Slug}).Attrs(model.Tag{Name: tag.Name}).
+			FirstOrCreate(&existing).Error; err != nil {
+			return nil, err
+		}
+		result = append(result, existing)
+	}
+	return result, nil
+}
+
+func (r *tagRepository) Update(tag *model.Tag) error {
+	return r.db.Save(tag).Error
+}
+
+// Delete menghapus tag secara permanen (agar slug bisa dipakai lagi) beserta tautannya ke event
+func (r *tagRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var tag model.Tag
+		if err := tx.First(&tag, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM event_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&tag).Error
+	})
+}
@@ -1,19 +1,35 @@
 package repository
 
 import (
+	"errors"
 	"ticketing/model"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrSoldOut dikembalikan ketika sisa kuota event tidak mencukupi qty yang diminta
+	ErrSoldOut = errors.New("tickets are sold out for this event")
+	// ErrHoldExpired dikembalikan ketika batas waktu pembayaran tiket sudah lewat
+	ErrHoldExpired = errors.New("ticket hold has expired")
+	// ErrInvalidTransition dikembalikan ketika perubahan status tiket tidak diizinkan
+	ErrInvalidTransition = errors.New("ticket status does not allow this action")
 )
 
 type TicketRepository interface {
 	Create(ticket *model.Ticket) error
+	Purchase(ticket *model.Ticket, seatIDs []uint) error
 	FindAll(page, limit int, userID uint) ([]model.Ticket, int64, error)
 	FindAllTickets(page, limit int) ([]model.Ticket, int64, error)
 	FindByID(id uint) (*model.Ticket, error)
 	Update(ticket *model.Ticket) error
 	Cancel(id uint) error
+	CancelHold(id uint) error
 	UpdatePaymentStatus(ticketID uint, status model.PaymentStatus, ticketStatus model.TicketStatus) error
+	ExpireHolds(now time.Time, limit int) ([]model.Ticket, error)
+	ReleaseEventHolds(eventID uint, limit int) ([]model.Ticket, error)
 }
 
 type ticketRepository struct {
@@ -28,6 +44,204 @@ func (r *ticketRepository) Create(ticket *model.Ticket) error {
 	return r.db.Create(ticket).Error
 }
 
+// Purchase membuat tiket dalam satu transaksi dengan mengunci baris event
+// (SELECT ... FOR UPDATE) sehingga pembelian paralel tidak bisa oversell.
+// Jika seatIDs diisi, kursi tersebut ikut di-hold di transaksi yang sama.
+func (r *ticketRepository) Purchase(ticket *model.Ticket, seatIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return purchaseInTx(tx, ticket, seatIDs)
+	})
+}
+
+// purchaseInTx berisi langkah pembelian yang harus berjalan di dalam transaksi pemanggil
+func purchaseInTx(tx *gorm.DB, ticket *model.Ticket, seatIDs []uint) error {
+	var event model.Event
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&event, ticket.EventID).Error; err != nil {
+		return err
+	}
+
+	if event.Available() < ticket.Qty {
+		return ErrSoldOut
+	}
+
+	price := event.Price
+	if ticket.TicketTypeID != nil {
+		var ticketType model.TicketType
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("event_id = ?", event.ID).
+			First(&ticketType, *ticket.TicketTypeID).Error; err != nil {
+			return err
+		}
+		if ticketType.Available() < ticket.Qty {
+			return ErrSoldOut
+		}
+		price = ticketType.Price
+	}
+
+	ticket.OriginalTotal = price * float64(ticket.Qty)
+	ticket.SubTotal = ticket.OriginalTotal
+	if ticket.PromoCodeID != nil {
+		if err := applyPromoCode(tx, ticket); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Create(ticket).Error; err != nil {
+		return err
+	}
+	if err := createPasses(tx, ticket); err != nil {
+		return err
+	}
+
+	if ticket.PromoCodeID != nil {
+		if err := redeemPromoCode(tx, ticket); err != nil {
+			return err
+		}
+	}
+
+	if len(seatIDs) > 0 {
+		if err := holdSeats(tx, ticket, seatIDs); err != nil {
+			return err
+		}
+	}
+
+	return moveInventory(tx, ticket, "", ticket.Status)
+}
+
+// holdSeats mengunci kursi untuk tiket hanya jika semuanya masih available
+func holdSeats(tx *gorm.DB, ticket *model.Ticket, seatIDs []uint) error {
+	query := tx.Model(&model.Seat{}).
+		Where("event_id = ? AND id IN ? AND status = ?", ticket.EventID, seatIDs, model.SeatAvailable)
+	if ticket.TicketTypeID != nil {
+		query = query.Where("ticket_type_id IS NULL OR ticket_type_id = ?", *ticket.TicketTypeID)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"status":    model.SeatHeld,
+		"ticket_id": ticket.ID,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != int64(len(seatIDs)) {
+		return ErrSeatUnavailable
+	}
+	return nil
+}
+
+// moveSeats menyelaraskan status kursi milik tiket dengan status tiketnya
+func moveSeats(tx *gorm.DB, ticketID uint, status model.TicketStatus) error {
+	updates := map[string]interface{}{}
+	switch status {
+	case model.Booked:
+		updates["status"] = model.SeatBooked
+	case model.Cancelled:
+		updates["status"] = model.SeatAvailable
+		updates["ticket_id"] = nil
+	default:
+		return nil
+	}
+
+	return tx.Model(&model.Seat{}).Where("ticket_id = ?", ticketID).Updates(updates).Error
+}
+
+// moveInventory menyesuaikan counter held/sold event (dan tier tiket, jika ada)
+// ketika tiket berpindah status. Status kosong berarti tiket tidak memakan kuota.
+func moveInventory(tx *gorm.DB, ticket *model.Ticket, from, to model.TicketStatus) error {
+	held, sold := 0, 0
+	switch from {
+	case model.Available:
+		held -= ticket.Qty
+	case model.Booked:
+		sold -= ticket.Qty
+	}
+	switch to {
+	case model.Available:
+		held += ticket.Qty
+	case model.Booked:
+		sold += ticket.Qty
+	}
+
+	if held == 0 && sold == 0 {
+		return nil
+	}
+
+	counters := map[string]interface{}{
+		"held": gorm.Expr("held + ?", held),
+		"sold": gorm.Expr("sold + ?", sold),
+	}
+
+	if err := tx.Model(&model.Event{}).Where("id = ?", ticket.EventID).Updates(counters).Error; err != nil {
+		return err
+	}
+
+	if ticket.TicketTypeID == nil {
+		return nil
+	}
+	return tx.Model(&model.TicketType{}).Where("id = ?", *ticket.TicketTypeID).Updates(counters).Error
+}
+
+// transition mengunci tiket lalu memindahkan status dan counter event secara atomik
+func (r *ticketRepository) transition(ticketID uint, paymentStatus model.PaymentStatus, status model.TicketStatus) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var ticket model.Ticket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&ticket, ticketID).Error; err != nil {
+			return err
+		}
+
+		return applyTransition(tx, &ticket, paymentStatus, status)
+	})
+}
+
+// applyTransition harus dipanggil di dalam transaksi dengan baris tiket sudah terkunci
+func applyTransition(tx *gorm.DB, ticket *model.Ticket, paymentStatus model.PaymentStatus, status model.TicketStatus) error {
+	switch {
+	case ticket.Status == model.Available && status == model.Booked:
+		if ticket.ExpiresAt != nil && time.Now().After(*ticket.ExpiresAt) {
+			return ErrHoldExpired
+		}
+	case ticket.Status == model.Available && status == model.Cancelled:
+	case ticket.Status == model.Booked && status == model.Cancelled:
+	default:
+		return ErrInvalidTransition
+	}
+
+	updates := map[string]interface{}{"status": status}
+	if paymentStatus != "" {
+		updates["payment_status"] = paymentStatus
+	}
+	if status != model.Available {
+		updates["expires_at"] = nil
+	}
+	if err := tx.Model(ticket).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	from := activeStatus(ticket.Status)
+	ticket.Status = status
+	if err := moveSeats(tx, ticket.ID, status); err != nil {
+		return err
+	}
+
+	// Tiket yang batal sebelum dibayar tidak menghabiskan jatah kode promo
+	if from == model.Available && status == model.Cancelled && ticket.PromoCodeID != nil {
+		if err := releasePromoCode(tx, ticket); err != nil {
+			return err
+		}
+	}
+	return moveInventory(tx, ticket, from, activeStatus(status))
+}
+
+// activeStatus mengosongkan status yang tidak memakan kuota (cancelled)
+func activeStatus(status model.TicketStatus) model.TicketStatus {
+	if status == model.Cancelled {
+		return ""
+	}
+	return status
+}
+
 func (r *ticketRepository) FindAll(page, limit int, userID uint) ([]model.Ticket, int64, error) {
 	var tickets []model.Ticket
 	var total int64
@@ -42,13 +256,20 @@ func (r *ticketRepository) FindAll(page, limit int, userID uint) ([]model.Ticket
 	offset := (page - 1) * limit
 	err = query.Offset(offset).Limit(limit).
 		Preload("Event").
+		Preload("TicketType").
+		Preload("PromoCode").
+		Preload("Seats").
+		Preload("Passes", orderPasses).
+		Preload("Answers.Question", withDeleted).
 		Find(&tickets).Error
 	return tickets, total, err
 }
 
 func (r *ticketRepository) FindByID(id uint) (*model.Ticket, error) {
 	var ticket model.Ticket
-	err := r.db.Preload("Event").Preload("User").First(&ticket, id).Error
+	err := r.db.Preload("Event").Preload("User").Preload("TicketType").Preload("PromoCode").Preload("Seats").
+		Preload("Passes", orderPasses).
+		Preload("Answers.Question", withDeleted).First(&ticket, id).Error
 	return &ticket, err
 }
 
@@ -57,18 +278,27 @@ func (r *ticketRepository) Update(ticket *model.Ticket) error {
 }
 
 func (r *ticketRepository) Cancel(id uint) error {
-	return r.db.Model(&model.Ticket{}).
-		Where("id = ?", id).
-		Update("status", model.Cancelled).Error
+	return r.transition(id, "", model.Cancelled)
+}
+
+// CancelHold membatalkan tiket yang belum dibayar; status dicek ulang dengan baris tiket
+// terkunci sehingga tiket yang baru saja lunas lewat webhook tidak ikut dibatalkan
+func (r *ticketRepository) CancelHold(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var ticket model.Ticket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&ticket, id).Error; err != nil {
+			return err
+		}
+		if ticket.Status != model.Available || ticket.PaymentStatus != model.Pending {
+			return ErrInvalidTransition
+		}
+
+		return applyTransition(tx, &ticket, model.Cancel, model.Cancelled)
+	})
 }
 
 func (r *ticketRepository) UpdatePaymentStatus(ticketID uint, paymentStatus model.PaymentStatus, status model.TicketStatus) error {
-	return r.db.Model(&model.Ticket{}).
-		Where("id = ?", ticketID).
-		Updates(map[string]interface{}{
-			"payment_status": paymentStatus,
-			"status":         status,
-		}).Error
+	return r.transition(ticketID, paymentStatus, status)
 }
 
 func (r *ticketRepository) FindAllTickets(page, limit int) ([]model.Ticket, int64, error) {
@@ -76,7 +306,8 @@ func (r *ticketRepository) FindAllTickets(page, limit int) ([]model.Ticket, int6
 	var total int64
 
 	offset := (page - 1) * limit
-	query := r.db.Preload("Event").Preload("User")
+	query := r.db.Preload("Event").Preload("User").Preload("TicketType").Preload("PromoCode").Preload("Seats").
+		Preload("Passes", orderPasses).Preload("Answers.Question", withDeleted)
 
 	if err := query.Offset(offset).Limit(limit).Find(&tickets).Error; err != nil {
 		return nil, 0, err
@@ -88,3 +319,64 @@ func (r *ticketRepository) FindAllTickets(page, limit int) ([]model.Ticket, int6
 
 	return tickets, total, nil
 }
+
+// ExpireHolds membatalkan tiket yang belum dibayar melewati ExpiresAt. Setiap tiket
+// dikunci dan dicek ulang di transaksinya sendiri sehingga aman dijalankan paralel
+// oleh beberapa instance server; tiket yang sudah diproses instance lain dilewati.
+func (r *ticketRepository) ExpireHolds(now time.Time, limit int) ([]model.Ticket, error) {
+	return r.cancelHolds(limit, "expires_at <= ?", now)
+}
+
+// ReleaseEventHolds membatalkan semua tiket event yang belum dibayar, dipakai saat
+// penjualan event ditutup
+func (r *ticketRepository) ReleaseEventHolds(eventID uint, limit int) ([]model.Ticket, error) {
+	return r.cancelHolds(limit, "event_id = ?", eventID)
+}
+
+// cancelHolds membatalkan hold yang cocok dengan kondisi tambahan cond
+func (r *ticketRepository) cancelHolds(limit int, cond string, arg interface{}) ([]model.Ticket, error) {
+	var ids []uint
+	if err := r.db.Model(&model.Ticket{}).
+		Where("status = ? AND payment_status = ?", model.Available, model.Pending).
+		Where(cond, arg).
+		Order("expires_at").
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	var expired []model.Ticket
+	for _, id := range ids {
+		var ticket model.Ticket
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Where("status = ? AND payment_status = ?", model.Available, model.Pending).
+				Where(cond, arg).
+				First(&ticket, id).Error
+			if err != nil {
+				return err
+			}
+
+			if err := applyTransition(tx, &ticket, model.Cancel, model.Cancelled); err != nil {
+				return err
+			}
+
+			// Order yang salah satu tiketnya kedaluwarsa tidak bisa dibayar lagi
+			if ticket.OrderID == nil {
+				return nil
+			}
+			return tx.Model(&model.Order{}).
+				Where("id = ? AND status = ?", *ticket.OrderID, model.OrderPending).
+				Update("status", model.OrderCancelled).Error
+		})
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return expired, err
+		}
+		expired = append(expired, ticket)
+	}
+
+	return expired, nil
+}
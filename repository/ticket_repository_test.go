@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"ticketing/model"
+)
+
+// TestPurchaseNeverOversells menembakkan jauh lebih banyak pembelian paralel daripada kapasitas
+// ke satu event; kunci baris event harus membuat tepat capacity pembelian berhasil.
+func TestPurchaseNeverOversells(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewTicketRepository(db)
+
+	const capacity, buyers = 25, 400
+	user := createTestUser(t, db)
+	event := createTestEvent(t, db, model.Event{Capacity: capacity, Price: 50000})
+
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		succeeded  int
+		soldOut    int
+		unexpected []error
+	)
+	start := make(chan struct{})
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			ticket := &model.Ticket{
+				EventID:       event.ID,
+				UserID:        user.ID,
+				Qty:           1,
+				Status:        model.Available,
+				PaymentStatus: model.Pending,
+				BookedAt:      time.Now(),
+			}
+			err := repo.Purchase(ticket, nil)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, ErrSoldOut):
+				soldOut++
+			default:
+				unexpected = append(unexpected, err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	for _, err := range unexpected {
+		t.Errorf("unexpected purchase error: %v", err)
+	}
+	if succeeded != capacity {
+		t.Errorf("succeeded purchases = %d, want %d", succeeded, capacity)
+	}
+	if soldOut != buyers-capacity {
+		t.Errorf("sold-out errors = %d, want %d", soldOut, buyers-capacity)
+	}
+
+	var stored model.Event
+	if err := db.First(&stored, event.ID).Error; err != nil {
+		t.Fatalf("reload event: %v", err)
+	}
+	if stored.Sold+stored.Held > stored.Capacity {
+		t.Errorf("sold (%d) + held (%d) exceeds capacity %d", stored.Sold, stored.Held, stored.Capacity)
+	}
+	if stored.Held != capacity {
+		t.Errorf("held = %d, want %d", stored.Held, capacity)
+	}
+
+	var qty int64
+	if err := db.Model(&model.Ticket{}).Where("event_id = ?", event.ID).
+		Select("COALESCE(SUM(qty), 0)").Scan(&qty).Error; err != nil {
+		t.Fatalf("sum ticket qty: %v", err)
+	}
+	if qty != capacity {
+		t.Errorf("stored ticket qty = %d, want %d", qty, capacity)
+	}
+}
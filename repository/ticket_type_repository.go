@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"ticketing/model"
+
+	"gorm.io/gorm"
+)
+
+type TicketTypeRepository interface {
+	Create(ticketType *model.TicketType) error
+	FindByEvent(eventID uint) ([]model.TicketType, error)
+	FindByID(eventID, id uint) (*model.TicketType, error)
+	Update(ticketType *model.TicketType) error
+	Delete(id uint) error
+	CountByEvent(eventID uint) (int64, error)
+}
+
+type ticketTypeRepository struct {
+	db *gorm.DB
+}
+
+func NewTicketTypeRepository(db *gorm.DB) TicketTypeRepository {
+	return &ticketTypeRepository{db: db}
+}
+
+func (r *ticketTypeRepository) Create(ticketType *model.TicketType) error {
+	return r.db.Create(ticketType).Error
+}
+
+func (r *ticketTypeRepository) FindByEvent(eventID uint) ([]model.TicketType, error) {
+	var ticketTypes []model.TicketType
+	err := r.db.Where("event_id = ?", eventID).Order("price, id").Find(&ticketTypes).Error
+	return ticketTypes, err
+}
+
+func (r *ticketTypeRepository) FindByID(eventID, id uint) (*model.TicketType, error) {
+	var ticketType model.TicketType
+	err := r.db.Where("event_id = ?", eventID).First(&ticketType, id).Error
+	return &ticketType, err
+}
+
+func (r *ticketTypeRepository) Update(ticketType *model.TicketType) error {
+	// Counter sold/held hanya boleh diubah lewat transaksi tiket
+	return r.db.Omit("sold", "held").Save(ticketType).Error
+}
+
+func (r *ticketTypeRepository) Delete(id uint) error {
+	return r.db.Delete(&model.TicketType{}, id).Error
+}
+
+func (r *ticketTypeRepository) CountByEvent(eventID uint) (int64, error) {
+	var total int64
+	err := r.db.Model(&model.TicketType{}).Where("event_id = ?", eventID).Count(&total).Error
+	return total, err
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"errors"
+	"ticketing/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrTransferNotPending dikembalikan ketika transfer sudah diterima atau dibatalkan
+var ErrTransferNotPending = errors.New("ticket transfer is no longer pending")
+
+type TransferRepository interface {
+	Create(transfer *model.TicketTransfer) error
+	FindByID(id uint) (*model.TicketTransfer, error)
+	FindPendingByTicket(ticketID uint) (*model.TicketTransfer, error)
+	FindIncoming(email string) ([]model.TicketTransfer, error)
+	FindAll(page, limit int, ticketID uint) ([]model.TicketTransfer, int64, error)
+	Cancel(id uint) error
+	Accept(id, toUserID uint) error
+}
+
+type transferRepository struct {
+	db *gorm.DB
+}
+
+func NewTransferRepository(db *gorm.DB) TransferRepository {
+	return &transferRepository{db: db}
+}
+
+func (r *transferRepository) Create(transfer *model.TicketTransfer) error {
+	return r.db.Create(transfer).Error
+}
+
+func (r *transferRepository) FindByID(id uint) (*model.TicketTransfer, error) {
+	var transfer model.TicketTransfer
+	err := r.db.Preload("FromUser").Preload("ToUser").First(&transfer, id).Error
+	return &transfer, err
+}
+
+func (r *transferRepository) FindPendingByTicket(ticketID uint) (*model.TicketTransfer, error) {
+	var transfer model.TicketTransfer
+	err := r.db.Where("ticket_id = ? AND status = ?", ticketID, model.TransferPending).First(&transfer).Error
+	return &transfer, err
+}
+
+func (r *transferRepository) FindIncoming(email string) ([]model.TicketTransfer, error) {
+	var transfers []model.TicketTransfer
+	err := r.db.Preload("FromUser").
+		Where("to_email = ? AND status = ?", email, model.TransferPending).
+		Order("id").
+		Find(&transfers).Error
+	return transfers, err
+}
+
+func (r *transferRepository) FindAll(page, limit int, ticketID uint) ([]model.TicketTransfer, int64, error) {
+	var transfers []model.TicketTransfer
+	var total int64
+
+	query := r.db.Model(&model.TicketTransfer{})
+	if ticketID != 0 {
+		query = query.Where("ticket_id = ?", ticketID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Preload("FromUser").Preload("ToUser").
+		Order("id DESC").
+		Offset(offset).Limit(limit).
+		Find(&transfers).Error
+	return transfers, total, err
+}
+
+func (r *transferRepository) Cancel(id uint) error {
+	result := r.db.Model(&model.TicketTransfer{}).
+		Where("id = ? AND status = ?", id, model.TransferPending).
+		Updates(map[string]interface{}{"status": model.TransferCancelled, "responded_at": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTransferNotPending
+	}
+	return nil
+}
+
+// Accept memindahkan kepemilikan tiket dalam satu transaksi. Tiket dikunci lebih
+// dulu dan dicek ulang masih booked, belum check-in, dan masih milik pengirim, lalu versi
+// kredensialnya dinaikkan agar kredensial pemilik lama tidak berlaku lagi.
+// Data peserta pada attendee pass juga dikosongkan untuk diisi pemilik baru.
+func (r *transferRepository) Accept(id, toUserID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var transfer model.TicketTransfer
+		if err := tx.First(&transfer, id).Error; err != nil {
+			return err
+		}
+
+		var ticket model.Ticket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&ticket, transfer.TicketID).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&transfer, id).Error; err != nil {
+			return err
+		}
+
+		if transfer.Status != model.TransferPending {
+			return ErrTransferNotPending
+		}
+		if ticket.Status != model.Booked || ticket.UserID != transfer.FromUserID || ticket.CheckedIn > 0 {
+			return ErrInvalidTransition
+		}
+
+		if err := tx.Model(&ticket).Updates(map[string]interface{}{
+			"user_id":            toUserID,
+			"credential_version": gorm.Expr("credential_version + 1"),
+		}).Error; err != nil {
+			return err
+		}
+		if err := resetPasses(tx, &ticket, toUserID); err != nil {
+			return err
+		}
+
+		return tx.Model(&transfer).Updates(map[string]interface{}{
+			"status":       model.TransferAccepted,
+			"to_user_id":   toUserID,
+			"responded_at": time.Now(),
+		}).Error
+	})
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"ticketing/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrVenueCapacityExceeded dikembalikan ketika kapasitas event melebihi kapasitas maksimum venue
+	ErrVenueCapacityExceeded = errors.New("event capacity exceeds venue max capacity")
+	// ErrVenueDoubleBooked dikembalikan ketika jadwal event bertabrakan dengan event lain di venue yang sama
+	ErrVenueDoubleBooked = errors.New("venue is already booked for an overlapping time range")
+	// ErrVenueInUse dikembalikan ketika venue yang masih dipakai event akan dihapus
+	ErrVenueInUse = errors.New("venue is still used by events")
+)
+
+type VenueRepository interface {
+	Create(venue *model.Venue) error
+	FindAll(page, limit int, city string) ([]model.Venue, int64, error)
+	FindByID(id uint) (*model.Venue, error)
+	Update(venue *model.Venue) error
+	Delete(id uint) error
+}
+
+type venueRepository struct {
+	db *gorm.DB
+}
+
+func NewVenueRepository(db *gorm.DB) VenueRepository {
+	return &venueRepository{db: db}
+}
+
+func (r *venueRepository) Create(venue *model.Venue) error {
+	return r.db.Create(venue).Error
+}
+
+func (r *venueRepository) FindAll(page, limit int, city string) ([]model.Venue, int64, error) {
+	var venues []model.Venue
+	var total int64
+
+	query := r.db.Model(&model.Venue{})
+	if city != "" {
+		query = query.Where("city = ?", city)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("name, id").Offset(offset).Limit(limit).Find(&venues).Error
+	return venues, total, err
+}
+
+func (r *venueRepository) FindByID(id uint) (*model.Venue, error) {
+	var venue model.Venue
+	err := r.db.First(&venue, id).Error
+	return &venue, err
+}
+
+// Update menyimpan perubahan venue. MaxCapacity tidak boleh turun di bawah kapasitas
+// event upcoming yang sudah memakai venue ini.
+func (r *venueRepository) Update(venue *model.Venue) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var locked model.Venue
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, venue.ID).Error; err != nil {
+			return err
+		}
+
+		var largest model.Event
+		err := tx.Where("venue_id = ? AND status = ? AND capacity > ?", venue.ID, model.Upcoming, venue.MaxCapacity).
+			Order("capacity DESC").First(&largest).Error
+		if err == nil {
+			return fmt.Errorf("max capacity cannot be lower than the capacity of upcoming event %q (%d)",
+				largest.Name, largest.Capacity)
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		return tx.Save(venue).Error
+	})
+}
+
+func (r *venueRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var venue model.Venue
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&venue, id).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&model.Event{}).Where("venue_id = ?", id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrVenueInUse
+		}
+
+		return tx.Delete(&venue).Error
+	})
+}
+
+// checkVenueInTx mengunci venue event lalu memastikan kapasitas event tidak melebihi
+// MaxCapacity dan tidak ada event lain di venue itu yang waktunya beririsan
+// ([StartsAt, EndsAt) saling tumpang tindih). Karena baris venue dikunci, dua transaksi
+// yang memesan venue yang sama tidak bisa lolos bersamaan. Event dengan ID di exclude
+// (mis. event itu sendiri) diabaikan. Tidak melakukan apa-apa jika event tanpa venue.
+func checkVenueInTx(tx *gorm.DB, event *model.Event, exclude []uint) error {
+	if event.VenueID == nil {
+		return nil
+	}
+
+	var venue model.Venue
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&venue, *event.VenueID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("venue not found")
+		}
+		return err
+	}
+	if event.Capacity > venue.MaxCapacity {
+		return fmt.Errorf("%w (%d > %d)", ErrVenueCapacityExceeded, event.Capacity, venue.MaxCapacity)
+	}
+
+	query := tx.Where("venue_id = ? AND starts_at < ? AND ends_at > ?", venue.ID, event.EndsAt, event.StartsAt)
+	if event.ID != 0 {
+		exclude = append([]uint{event.ID}, exclude...)
+	}
+	if len(exclude) > 0 {
+		query = query.Where("id NOT IN ?", exclude)
+	}
+
+	var conflict model.Event
+	err := query.Order("starts_at").First(&conflict).Error
+	if err == nil {
+		return fmt.Errorf("%w: event %q (id %d) from %s to %s", ErrVenueDoubleBooked, conflict.Name, conflict.ID,
+			conflict.StartsAt.UTC().Format(time.RFC3339), conflict.EndsAt.UTC().Format(time.RFC3339))
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
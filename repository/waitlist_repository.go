@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"errors"
+	"ticketing/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrOfferNotActive dikembalikan ketika tawaran waitlist sudah kedaluwarsa atau tidak ada
+var ErrOfferNotActive = errors.New("waitlist offer is no longer active")
+
+type WaitlistRepository interface {
+	Create(entry *model.WaitlistEntry) error
+	FindByID(id uint) (*model.WaitlistEntry, error)
+	FindActive(eventID, userID uint) (*model.WaitlistEntry, error)
+	FindByEvent(eventID uint, statuses []model.WaitlistStatus) ([]model.WaitlistEntry, error)
+	Position(entry *model.WaitlistEntry) (int64, error)
+	Leave(id uint) error
+	OfferReleased(eventID uint, now time.Time, window time.Duration) ([]model.WaitlistEntry, error)
+	ExpireOffers(now time.Time, limit int) ([]model.WaitlistEntry, error)
+	EventsWithWaiting() ([]uint, error)
+	CloseEvent(eventID uint) ([]model.WaitlistEntry, error)
+	AcceptOffer(id uint, ticket *model.Ticket, seatIDs []uint) error
+}
+
+type waitlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWaitlistRepository(db *gorm.DB) WaitlistRepository {
+	return &waitlistRepository{db: db}
+}
+
+func (r *waitlistRepository) Create(entry *model.WaitlistEntry) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *waitlistRepository) FindByID(id uint) (*model.WaitlistEntry, error) {
+	var entry model.WaitlistEntry
+	err := r.db.First(&entry, id).Error
+	return &entry, err
+}
+
+// FindActive mencari entry user yang masih menunggu atau sedang ditawari
+func (r *waitlistRepository) FindActive(eventID, userID uint) (*model.WaitlistEntry, error) {
+	var entry model.WaitlistEntry
+	err := r.db.Where("event_id = ? AND user_id = ? AND status IN ?", eventID, userID,
+		[]model.WaitlistStatus{model.WaitlistWaiting, model.WaitlistOffered}).
+		First(&entry).Error
+	return &entry, err
+}
+
+func (r *waitlistRepository) FindByEvent(eventID uint, statuses []model.WaitlistStatus) ([]model.WaitlistEntry, error) {
+	var entries []model.WaitlistEntry
+	query := r.db.Preload("User").Where("event_id = ?", eventID)
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	err := query.Order("id").Find(&entries).Error
+	return entries, err
+}
+
+// Position mengembalikan posisi entry (mulai dari 1) di antara entry yang masih menunggu
+func (r *waitlistRepository) Position(entry *model.WaitlistEntry) (int64, error) {
+	var ahead int64
+	err := r.db.Model(&model.WaitlistEntry{}).
+		Where("event_id = ? AND status = ? AND id < ?", entry.EventID, model.WaitlistWaiting, entry.ID).
+		Count(&ahead).Error
+	return ahead + 1, err
+}
+
+// Leave mengeluarkan entry dari antrean; tawaran yang sedang aktif dilepas kembali ke event
+func (r *waitlistRepository) Leave(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		entry, err := lockEntryWithEvent(tx, id)
+		if err != nil {
+			return err
+		}
+
+		switch entry.Status {
+		case model.WaitlistWaiting:
+		case model.WaitlistOffered:
+			if err := releaseOffer(tx, entry); err != nil {
+				return err
+			}
+		default:
+			return ErrOfferNotActive
+		}
+
+		return tx.Model(entry).Updates(map[string]interface{}{
+			"status":           model.WaitlistLeft,
+			"offer_expires_at": nil,
+		}).Error
+	})
+}
+
+// OfferReleased membagikan sisa kuota event ke antrean secara FIFO di bawah lock
+// baris event, sehingga pelepasan kuota yang terjadi bersamaan tidak bisa
+// menawarkan kursi yang sama dua kali atau melompati urutan antrean.
+// Antrean berhenti pada entry pertama yang qty-nya belum muat.
+func (r *waitlistRepository) OfferReleased(eventID uint, now time.Time, window time.Duration) ([]model.WaitlistEntry, error) {
+	var offered []model.WaitlistEntry
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var event model.Event
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&event, eventID).Error; err != nil {
+			return err
+		}
+		if event.Status != model.Upcoming {
+			return nil
+		}
+
+		var waiting []model.WaitlistEntry
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("event_id = ? AND status = ?", eventID, model.WaitlistWaiting).
+			Order("id").
+			Find(&waiting).Error; err != nil {
+			return err
+		}
+
+		free := event.Available()
+		expiresAt := now.Add(window)
+		reserved := 0
+		for _, entry := range waiting {
+			if entry.Qty > free-reserved {
+				break
+			}
+			if err := tx.Model(&entry).Updates(map[string]interface{}{
+				"status":           model.WaitlistOffered,
+				"offered_at":       now,
+				"offer_expires_at": expiresAt,
+			}).Error; err != nil {
+				return err
+			}
+			entry.Status = model.WaitlistOffered
+			entry.OfferedAt = &now
+			entry.OfferExpiresAt = &expiresAt
+			reserved += entry.Qty
+			offered = append(offered, entry)
+		}
+
+		if reserved == 0 {
+			return nil
+		}
+		return tx.Model(&event).Update("offered", gorm.Expr("offered + ?", reserved)).Error
+	})
+	return offered, err
+}
+
+// ExpireOffers mengakhiri tawaran yang tidak diterima sampai batas waktunya dan
+// mengembalikan kuotanya ke event. Setiap entry dicek ulang di transaksinya sendiri.
+func (r *waitlistRepository) ExpireOffers(now time.Time, limit int) ([]model.WaitlistEntry, error) {
+	var ids []uint
+	if err := r.db.Model(&model.WaitlistEntry{}).
+		Where("status = ? AND offer_expires_at <= ?", model.WaitlistOffered, now).
+		Order("offer_expires_at").
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	var expired []model.WaitlistEntry
+	for _, id := range ids {
+		var entry *model.WaitlistEntry
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			entry, err = lockEntryWithEvent(tx, id)
+			if err != nil {
+				return err
+			}
+			if entry.Status != model.WaitlistOffered || entry.OfferExpiresAt == nil || entry.OfferExpiresAt.After(now) {
+				return gorm.ErrRecordNotFound
+			}
+
+			if err := releaseOffer(tx, entry); err != nil {
+				return err
+			}
+			return tx.Model(entry).Update("status", model.WaitlistExpired).Error
+		})
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return expired, err
+		}
+		expired = append(expired, *entry)
+	}
+
+	return expired, nil
+}
+
+// CloseEvent mengakhiri seluruh antrean event yang penjualannya ditutup; tawaran
+// yang masih aktif dilepas dan kuotanya dikembalikan ke event.
+func (r *waitlistRepository) CloseEvent(eventID uint) ([]model.WaitlistEntry, error) {
+	var ids []uint
+	if err := r.db.Model(&model.WaitlistEntry{}).
+		Where("event_id = ? AND status IN ?", eventID, []model.WaitlistStatus{model.WaitlistWaiting, model.WaitlistOffered}).
+		Order("id").
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	var closed []model.WaitlistEntry
+	for _, id := range ids {
+		var entry *model.WaitlistEntry
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			entry, err = lockEntryWithEvent(tx, id)
+			if err != nil {
+				return err
+			}
+
+			if entry.Status != model.WaitlistWaiting && entry.Status != model.WaitlistOffered {
+				return gorm.ErrRecordNotFound
+			}
+			if entry.Status == model.WaitlistOffered {
+				if err := releaseOffer(tx, entry); err != nil {
+					return err
+				}
+			}
+			return tx.Model(entry).Updates(map[string]interface{}{
+				"status":           model.WaitlistExpired,
+				"offer_expires_at": nil,
+			}).Error
+		})
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return closed, err
+		}
+		closed = append(closed, *entry)
+	}
+
+	return closed, nil
+}
+
+func (r *waitlistRepository) EventsWithWaiting() ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&model.WaitlistEntry{}).
+		Where("status = ?", model.WaitlistWaiting).
+		Distinct().
+		Pluck("event_id", &ids).Error
+	return ids, err
+}
+
+// AcceptOffer mengubah tawaran menjadi tiket: kuota yang dicadangkan dilepas dan
+// langsung dipakai oleh pembelian di transaksi yang sama.
+func (r *waitlistRepository) AcceptOffer(id uint, ticket *model.Ticket, seatIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		entry, err := lockEntryWithEvent(tx, id)
+		if err != nil {
+			return err
+		}
+		if entry.Status != model.WaitlistOffered || entry.OfferExpiresAt == nil || time.Now().After(*entry.OfferExpiresAt) {
+			return ErrOfferNotActive
+		}
+
+		if err := releaseOffer(tx, entry); err != nil {
+			return err
+		}
+		if err := purchaseInTx(tx, ticket, seatIDs); err != nil {
+			return err
+		}
+
+		return tx.Model(entry).Updates(map[string]interface{}{
+			"status":           model.WaitlistAccepted,
+			"offer_expires_at": nil,
+			"ticket_id":        ticket.ID,
+		}).Error
+	})
+}
+
+// lockEntryWithEvent mengunci baris event lebih dulu lalu entry-nya, dengan urutan
+// yang sama seperti pembelian tiket agar tidak terjadi deadlock.
+func lockEntryWithEvent(tx *gorm.DB, id uint) (*model.WaitlistEntry, error) {
+	var entry model.WaitlistEntry
+	if err := tx.First(&entry, id).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&model.Event{}, entry.EventID).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&entry, id).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// releaseOffer mengembalikan qty yang dicadangkan untuk entry ke kuota event
+func releaseOffer(tx *gorm.DB, entry *model.WaitlistEntry) error {
+	return tx.Model(&model.Event{}).Where("id = ? AND offered >= ?", entry.EventID, entry.Qty).
+		Update("offered", gorm.Expr("offered - ?", entry.Qty)).Error
+}
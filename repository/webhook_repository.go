@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"ticketing/model"
+
+	"gorm.io/gorm"
+)
+
+type WebhookRepository interface {
+	Create(delivery *model.WebhookDelivery) error
+	FindByEventID(provider, eventID string) (*model.WebhookDelivery, error)
+	FindByID(id uint) (*model.WebhookDelivery, error)
+	FindAll(page, limit int, status string) ([]model.WebhookDelivery, int64, error)
+	Update(delivery *model.WebhookDelivery) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) Create(delivery *model.WebhookDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+func (r *webhookRepository) FindByEventID(provider, eventID string) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	err := r.db.Where("provider = ? AND event_id = ?", provider, eventID).First(&delivery).Error
+	return &delivery, err
+}
+
+func (r *webhookRepository) FindByID(id uint) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	err := r.db.First(&delivery, id).Error
+	return &delivery, err
+}
+
+func (r *webhookRepository) FindAll(page, limit int, status string) ([]model.WebhookDelivery, int64, error) {
+	var deliveries []model.WebhookDelivery
+	var total int64
+
+	query := r.db.Model(&model.WebhookDelivery{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&deliveries).Error
+	return deliveries, total, err
+}
+
+func (r *webhookRepository) Update(delivery *model.WebhookDelivery) error {
+	return r.db.Save(delivery).Error
+}
@@ -6,6 +6,7 @@ import (
 	"ticketing/config"
 	"ticketing/controller"
 	"ticketing/middleware"
+	"ticketing/payment"
 	"ticketing/repository"
 	"ticketing/service"
 
@@ -27,20 +28,107 @@ func Run() {
 	eventRepo := repository.NewEventRepository(db)
 	ticketRepo := repository.NewTicketRepository(db)
 	reportRepo := repository.NewReportRepository(db)
+	ticketTypeRepo := repository.NewTicketTypeRepository(db)
+	seatRepo := repository.NewSeatRepository(db)
+	orderRepo := repository.NewOrderRepository(db)
+	paymentRepo := repository.NewPaymentRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	refundRepo := repository.NewRefundRepository(db)
+	promoRepo := repository.NewPromoCodeRepository(db)
+	waitlistRepo := repository.NewWaitlistRepository(db)
+	transferRepo := repository.NewTransferRepository(db)
+	credentialRepo := repository.NewCredentialRepository(db)
+	checkInRepo := repository.NewCheckInRepository(db)
+	passRepo := repository.NewPassRepository(db)
+	questionRepo := repository.NewQuestionRepository(db)
+	seriesRepo := repository.NewSeriesRepository(db)
+	venueRepo := repository.NewVenueRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+
+	// Payment provider; saat ini hanya provider "fake" (in-process) yang tersedia dan
+	// hanya untuk development/test karena user bisa membayar tanpa uang sungguhan
+	if cfg.PaymentProvider != "fake" {
+		log.Fatalf("Unsupported payment provider: %s", cfg.PaymentProvider)
+	}
+	if cfg.AppEnv != "development" && cfg.AppEnv != "test" {
+		log.Fatalf("Payment provider fake requires APP_ENV=development or APP_ENV=test (got %q)", cfg.AppEnv)
+	}
+	// Tanpa secret, signature webhook bisa dipalsukan dengan HMAC berkunci kosong
+	if cfg.PaymentWebhookSecret == "" {
+		log.Fatalf("PAYMENT_WEBHOOK_SECRET must be set")
+	}
+	paymentProvider := payment.NewFakeProvider(payment.Mode(cfg.FakePaymentMode), cfg.FakePaymentDelay, cfg.PaymentWebhookSecret)
 
 	// Initialize services
 	authService := service.NewAuthService(userRepo)
-	eventService := service.NewEventService(eventRepo)
-	ticketService := service.NewTicketService(ticketRepo, eventRepo)
+	waitlistService := service.NewWaitlistService(waitlistRepo, eventRepo, cfg.WaitlistOfferWindow)
+	// Scheduler status event; hook penutupan penjualan melepas hold dan antrean waitlist
+	statusScheduler := service.NewEventStatusScheduler(eventRepo, cfg.EventStatusInterval, cfg.EventDefaultDuration)
+	statusScheduler.OnTransition(service.CloseSalesHook(ticketRepo, waitlistRepo))
+	eventService := service.NewEventService(eventRepo, venueRepo, categoryRepo, tagRepo, waitlistService, statusScheduler, cfg.DefaultTimezone)
+	credentialService := service.NewCredentialService(credentialRepo, ticketRepo, orderRepo)
+	if err := credentialService.EnsureActiveKey(); err != nil {
+		log.Fatalf("Failed to initialize ticket signing key: %v", err)
+	}
+	paymentService := service.NewPaymentService(paymentProvider, cfg.PaymentCurrency, paymentRepo, ticketRepo, orderRepo,
+		credentialService)
+	refundService := service.NewRefundService(refundRepo, ticketRepo, eventRepo, paymentRepo, paymentService)
+	ticketService := service.NewTicketService(ticketRepo, eventRepo, seatRepo, paymentService, refundService, promoRepo,
+		waitlistRepo, waitlistService, credentialService)
 	reportService := service.NewReportService(reportRepo)
 	userService := service.NewUserService(userRepo)
+	ticketTypeService := service.NewTicketTypeService(ticketTypeRepo, eventRepo)
+	seatService := service.NewSeatService(seatRepo, eventRepo)
+	promoCodeService := service.NewPromoCodeService(promoRepo, eventRepo)
+	transferService := service.NewTransferService(transferRepo, ticketRepo, userRepo, credentialService)
+	checkInService := service.NewCheckInService(checkInRepo, credentialRepo, passRepo, ticketRepo, eventRepo, userRepo, credentialService)
+	passService := service.NewPassService(passRepo, ticketRepo, credentialService)
+	questionService := service.NewQuestionService(questionRepo, eventRepo)
+	orderService := service.NewOrderService(orderRepo, eventRepo, seatRepo, paymentService, waitlistService)
+	seriesService := service.NewSeriesService(seriesRepo, eventRepo, venueRepo, categoryRepo, tagRepo, seatRepo, orderRepo, waitlistService,
+		statusScheduler, cfg.DefaultTimezone)
+	venueService := service.NewVenueService(venueRepo, cfg.DefaultTimezone)
+	categoryService := service.NewCategoryService(categoryRepo)
+	tagService := service.NewTagService(tagRepo)
+
+	webhookService := service.NewWebhookService(map[string]payment.Provider{
+		paymentProvider.Name(): paymentProvider,
+	}, webhookRepo, paymentService)
+
+	// Konfirmasi tertunda dari provider fake melewati pipeline webhook yang sama
+	paymentProvider.SetNotifier(func(event payment.WebhookEvent, payload []byte, signature string) {
+		if _, err := webhookService.ReceivePaymentWebhook(paymentProvider.Name(), payload, signature); err != nil {
+			log.Printf("Failed to apply payment event %s: %v", event.ID, err)
+		}
+	})
+
+	// Background job untuk melepas hold tiket yang tidak dibayar
+	holdSweeper := service.NewHoldSweeper(ticketRepo, waitlistService, cfg.HoldSweepInterval)
+	go holdSweeper.Run()
+	go statusScheduler.Run()
 
 	// Initialize controllers
 	authController := controller.NewAuthController(authService)
-	eventController := controller.NewEventController(eventService)
+	eventController := controller.NewEventController(eventService, seriesService)
 	ticketController := controller.NewTicketController(ticketService)
 	reportController := controller.NewReportController(reportService, db)
 	userController := controller.NewUserController(userService)
+	ticketTypeController := controller.NewTicketTypeController(ticketTypeService)
+	seatController := controller.NewSeatController(seatService)
+	orderController := controller.NewOrderController(orderService)
+	webhookController := controller.NewWebhookController(webhookService)
+	refundController := controller.NewRefundController(refundService)
+	promoCodeController := controller.NewPromoCodeController(promoCodeService)
+	waitlistController := controller.NewWaitlistController(waitlistService, ticketService)
+	transferController := controller.NewTransferController(transferService)
+	credentialController := controller.NewCredentialController(credentialService)
+	checkInController := controller.NewCheckInController(checkInService)
+	passController := controller.NewPassController(passService)
+	questionController := controller.NewQuestionController(questionService)
+	seriesController := controller.NewSeriesController(seriesService)
+	venueController := controller.NewVenueController(venueService)
+	categoryController := controller.NewCategoryController(categoryService, tagService)
 
 	// Create Gin router
 	router := gin.Default()
@@ -49,7 +137,9 @@ func Run() {
 	router.Use(middleware.ErrorHandler()) // Global error handler
 
 	// Register routes
-	SetupRoutes(router, db, authController, userController, eventController, ticketController, reportController, reportService)
+	SetupRoutes(router, db, authController, userController, eventController, ticketController, reportController, reportService,
+		ticketTypeController, seatController, orderController, webhookController, refundController, promoCodeController, waitlistController, transferController, credentialController,
+		checkInController, passController, questionController, seriesController, venueController, categoryController)
 
 	// Set server port, default to 8080
 	port := os.Getenv("PORT")
@@ -18,7 +18,25 @@ func SetupRoutes(
 	ticketController *controller.TicketController,
 	reportController *controller.ReportController,
 	reportService service.ReportService, // Gunakan service untuk laporan
+	ticketTypeController *controller.TicketTypeController,
+	seatController *controller.SeatController,
+	orderController *controller.OrderController,
+	webhookController *controller.WebhookController,
+	refundController *controller.RefundController,
+	promoCodeController *controller.PromoCodeController,
+	waitlistController *controller.WaitlistController,
+	transferController *controller.TransferController,
+	credentialController *controller.CredentialController,
+	checkInController *controller.CheckInController,
+	passController *controller.PassController,
+	questionController *controller.QuestionController,
+	seriesController *controller.SeriesController,
+	venueController *controller.VenueController,
+	categoryController *controller.CategoryController,
 ) {
+	// Public key untuk verifikasi kredensial tiket secara offline
+	r.GET("/.well-known/ticket-keys", credentialController.GetPublicKeys)
+
 	api := r.Group("/api")
 
 	// AUTH routes (tanpa middleware)
@@ -38,12 +56,67 @@ func SetupRoutes(
 	{
 		eventGroup.GET("", eventController.GetAllEvents)     // publik
 		eventGroup.GET("/:id", eventController.GetEventByID) // publik
+		eventGroup.GET("/:id/ticket-types", ticketTypeController.GetTicketTypes)
+		eventGroup.GET("/:id/seats", seatController.GetSeatMap)
+		eventGroup.GET("/:id/questions", questionController.GetForm)
 
 		eventGroup.Use(middleware.AuthMiddleware("admin")) // hanya admin boleh buat, update, hapus
 		eventGroup.POST("", eventController.CreateEvent)
 		eventGroup.PUT("/:id", eventController.UpdateEvent)
 		eventGroup.DELETE("/:id", eventController.DeleteEvent)
+		eventGroup.PATCH("/:id/status", eventController.SetStatus)
+		eventGroup.POST("/:id/ticket-types", ticketTypeController.CreateTicketType)
+		eventGroup.PUT("/:id/ticket-types/:typeId", ticketTypeController.UpdateTicketType)
+		eventGroup.DELETE("/:id/ticket-types/:typeId", ticketTypeController.DeleteTicketType)
+		eventGroup.POST("/:id/seats", seatController.ImportSeatMap)
+		eventGroup.GET("/:id/cancellation-policy", refundController.GetPolicy)
+		eventGroup.PUT("/:id/cancellation-policy", refundController.SetPolicy)
+		eventGroup.PUT("/:id/questions", questionController.SetForm)
+	}
+
+	// VENUE routes
+	venueGroup := api.Group("/venues")
+	{
+		venueGroup.GET("", venueController.GetAllVenues)     // publik, ?city= untuk filter kota
+		venueGroup.GET("/:id", venueController.GetVenueByID) // publik
+
+		venueGroup.Use(middleware.AuthMiddleware("admin"))
+		venueGroup.POST("", venueController.CreateVenue)
+		venueGroup.PUT("/:id", venueController.UpdateVenue)
+		venueGroup.DELETE("/:id", venueController.DeleteVenue)
+	}
+
+	// CATEGORY & TAG routes
+	categoryGroup := api.Group("/categories")
+	{
+		categoryGroup.GET("", categoryController.GetCategoryTree)     // publik
+		categoryGroup.GET("/:id", categoryController.GetCategoryByID) // publik
+
+		categoryGroup.Use(middleware.AuthMiddleware("admin"))
+		categoryGroup.POST("", categoryController.CreateCategory)
+		categoryGroup.PUT("/:id", categoryController.UpdateCategory)
+		categoryGroup.DELETE("/:id", categoryController.DeleteCategory)
+	}
+
+	tagGroup := api.Group("/tags")
+	{
+		tagGroup.GET("", categoryController.GetTags) // publik
+
+		tagGroup.Use(middleware.AuthMiddleware("admin"))
+		tagGroup.POST("", categoryController.CreateTag)
+		tagGroup.PUT("/:id", categoryController.UpdateTag)
+		tagGroup.DELETE("/:id", categoryController.DeleteTag)
+	}
+
+	// SERIES routes
+	seriesGroup := api.Group("/series")
+	{
+		seriesGroup.GET("", seriesController.GetAllSeries)      // publik
+		seriesGroup.GET("/:id", seriesController.GetSeriesByID) // publik
+
+		seriesGroup.POST("", middleware.AuthMiddleware("admin"), seriesController.CreateSeries)
 	}
+	api.POST("/series/:id/passes", middleware.AuthMiddleware("user"), seriesController.PurchasePass)
 
 	// TICKET routes (user)
 	ticketGroup := api.Group("/tickets")
@@ -52,9 +125,99 @@ func SetupRoutes(
 		ticketGroup.POST("", ticketController.PurchaseTicket)
 		ticketGroup.GET("", ticketController.GetUserTickets)
 		ticketGroup.GET("/:id", ticketController.GetTicketByID)
+		ticketGroup.GET("/:id/refund-preview", refundController.PreviewRefund)
 		ticketGroup.PATCH("/:id", ticketController.CancelTicket)
 		ticketGroup.PATCH("/:id/payment", ticketController.UpdatePayment)
 		ticketGroup.PATCH("/:id/cancel-payment", ticketController.CancelPayment)
+		ticketGroup.POST("/:id/transfer", transferController.InitiateTransfer)
+		ticketGroup.DELETE("/:id/transfer", transferController.CancelTransfer)
+		ticketGroup.PUT("/:id/passes/:passId", passController.UpdatePass)
+	}
+
+	// E-ticket bisa diunduh pemilik tiket maupun admin
+	api.GET("/tickets/:id/pdf", middleware.AuthMiddleware("user", "admin"), ticketController.DownloadTicketPDF)
+	api.GET("/tickets/:id/credential", middleware.AuthMiddleware("user", "admin"), credentialController.GetCredential)
+	api.GET("/tickets/:id/passes", middleware.AuthMiddleware("user", "admin"), passController.GetPasses)
+	api.GET("/tickets/:id/passes/:passId/credential", middleware.AuthMiddleware("user", "admin"), passController.GetPassCredential)
+
+	// CHECK-IN route untuk scanner di gerbang (staff yang ditugaskan atau admin)
+	checkInGroup := api.Group("/checkin")
+	checkInGroup.Use(middleware.AuthMiddleware("staff", "admin"))
+	{
+		checkInGroup.POST("", checkInController.CheckIn)
+		checkInGroup.GET("/events/:id/bundle", checkInController.GetBundle)
+		checkInGroup.POST("/events/:id/sync", checkInController.SyncOffline)
+	}
+
+	// TRANSFER routes (penerima transfer)
+	transferGroup := api.Group("/transfers")
+	transferGroup.Use(middleware.AuthMiddleware("user"))
+	{
+		transferGroup.GET("/incoming", transferController.GetIncomingTransfers)
+		transferGroup.POST("/:id/accept", transferController.AcceptTransfer)
+	}
+
+	// WAITLIST routes (user)
+	waitlistGroup := api.Group("/events/:id/waitlist")
+	waitlistGroup.Use(middleware.AuthMiddleware("user"))
+	{
+		waitlistGroup.POST("", waitlistController.Join)
+		waitlistGroup.GET("", waitlistController.GetPosition)
+		waitlistGroup.DELETE("", waitlistController.Leave)
+	}
+	api.POST("/waitlist/:entryId/accept", middleware.AuthMiddleware("user"), waitlistController.AcceptOffer)
+
+	// PROMO CODE routes (admin only)
+	promoGroup := api.Group("/promo-codes")
+	promoGroup.Use(middleware.AuthMiddleware("admin"))
+	{
+		promoGroup.GET("", promoCodeController.GetPromoCodes)
+		promoGroup.GET("/:id", promoCodeController.GetPromoCodeByID)
+		promoGroup.POST("", promoCodeController.CreatePromoCode)
+		promoGroup.PUT("/:id", promoCodeController.UpdatePromoCode)
+		promoGroup.DELETE("/:id", promoCodeController.DeletePromoCode)
+	}
+
+	// CART & ORDER routes (user)
+	cartGroup := api.Group("/cart")
+	cartGroup.Use(middleware.AuthMiddleware("user"))
+	{
+		cartGroup.GET("", orderController.GetCart)
+		cartGroup.POST("/items", orderController.AddToCart)
+		cartGroup.DELETE("/items/:itemId", orderController.RemoveFromCart)
+		cartGroup.POST("/checkout", orderController.Checkout)
+	}
+
+	orderGroup := api.Group("/orders")
+	orderGroup.Use(middleware.AuthMiddleware("user"))
+	{
+		orderGroup.GET("", orderController.GetUserOrders)
+		orderGroup.GET("/:id", orderController.GetOrderByID)
+		orderGroup.PATCH("/:id/payment", orderController.PayOrder)
+		orderGroup.PATCH("/:id/cancel", orderController.CancelOrder)
+	}
+
+	// WEBHOOK routes (tanpa JWT, diverifikasi lewat signature HMAC)
+	api.POST("/webhooks/payments/:provider", webhookController.ReceivePaymentWebhook)
+
+	// ADMIN routes
+	adminGroup := api.Group("/admin")
+	adminGroup.Use(middleware.AuthMiddleware("admin"))
+	{
+		adminGroup.GET("/webhooks", webhookController.GetDeliveries)
+		adminGroup.POST("/webhooks/:id/replay", webhookController.ReplayDelivery)
+		adminGroup.GET("/tickets/:id/refunds", refundController.GetTicketRefunds)
+		adminGroup.GET("/events/:id/waitlist", waitlistController.GetQueue)
+		adminGroup.GET("/transfers", transferController.GetTransfers)
+		adminGroup.POST("/signing-keys/rotate", credentialController.RotateKey)
+		adminGroup.GET("/credentials/revoked", credentialController.GetRevoked)
+		adminGroup.GET("/events/:id/staff", checkInController.GetStaff)
+		adminGroup.POST("/events/:id/staff", checkInController.AssignStaff)
+		adminGroup.DELETE("/events/:id/staff/:userId", checkInController.UnassignStaff)
+		adminGroup.GET("/events/:id/checkins", checkInController.GetSummary)
+		adminGroup.GET("/events/:id/checkins/scans", checkInController.GetScans)
+		adminGroup.PATCH("/checkins/:id/review", checkInController.ReviewConflict)
+		adminGroup.POST("/tickets/:id/refunds", refundController.IssueRefund)
 	}
 
 	// REPORT routes (admin only)
@@ -64,6 +227,8 @@ func SetupRoutes(
 		reportGroup.GET("/summary", reportController.GetSummaryReport)
 		reportGroup.GET("/events", reportController.GetEventReports)
 		reportGroup.GET("/ticket", ticketController.GetAllTickets)
+		reportGroup.GET("/promo-codes", reportController.GetPromoCodeReports)
+		reportGroup.GET("/events/:id/attendees", reportController.GetAttendeeReport)
 
 		// Route untuk generate summary report PDF
 		reportGroup.GET("/generate-summary-excel", func(c *gin.Context) {
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"ticketing/dto"
+	"ticketing/model"
+	"ticketing/repository"
+	"ticketing/utils"
+)
+
+type CategoryService interface {
+	CreateCategory(req dto.CategoryRequest) (*dto.CategoryResponse, error)
+	GetCategoryTree() ([]dto.CategoryResponse, error)
+	GetCategoryByID(id uint) (*dto.CategoryResponse, error)
+	UpdateCategory(id uint, req dto.CategoryRequest) (*dto.CategoryResponse, error)
+	DeleteCategory(id uint) error
+}
+
+type categoryService struct {
+	categoryRepo repository.CategoryRepository
+}
+
+func NewCategoryService(categoryRepo repository.CategoryRepository) CategoryService {
+	return &categoryService{categoryRepo: categoryRepo}
+}
+
+func (s *categoryService) CreateCategory(req dto.CategoryRequest) (*dto.CategoryResponse, error) {
+	category := &model.Category{}
+	if err := s.applyCategoryRequest(category, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.categoryRepo.Create(category); err != nil {
+		return nil, err
+	}
+	return s.GetCategoryByID(category.ID)
+}
+
+// GetCategoryTree mengembalikan kategori akar beserta subkategorinya secara bertingkat
+func (s *categoryService) GetCategoryTree() ([]dto.CategoryResponse, error) {
+	categories, err := s.categoryRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	return buildCategoryTree(categories, nil), nil
+}
+
+func (s *categoryService) GetCategoryByID(id uint) (*dto.CategoryResponse, error) {
+	categories, err := s.categoryRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range categories {
+		if categories[i].ID == id {
+			response := mapCategoryToResponse(&categories[i])
+			response.Children = buildCategoryTree(categories, &id)
+			return response, nil
+		}
+	}
+	return nil, errors.New("category not found")
+}
+
+func (s *categoryService) UpdateCategory(id uint, req dto.CategoryRequest) (*dto.CategoryResponse, error) {
+	category, err := s.categoryRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("category not found")
+	}
+
+	if err := s.applyCategoryRequest(category, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.categoryRepo.Update(category); err != nil {
+		return nil, err
+	}
+	return s.GetCategoryByID(category.ID)
+}
+
+func (s *categoryService) DeleteCategory(id uint) error {
+	if _, err := s.categoryRepo.FindByID(id); err != nil {
+		return errors.New("category not found")
+	}
+	return s.categoryRepo.Delete(id)
+}
+
+// applyCategoryRequest mengisi kategori dari request; slug harus unik dan induk baru
+// tidak boleh kategori itu sendiri atau salah satu turunannya
+func (s *categoryService) applyCategoryRequest(category *model.Category, req dto.CategoryRequest) error {
+	slug := utils.Slugify(req.Slug)
+	if req.Slug == "" {
+		slug = utils.Slugify(req.Name)
+	}
+	if slug == "" {
+		return errors.New("slug must contain letters or digits")
+	}
+	if existing, err := s.categoryRepo.FindBySlug(slug); err == nil && existing.ID != category.ID {
+		return fmt.Errorf("category slug %q already exists", slug)
+	}
+
+	if req.ParentID != nil {
+		categories, err := s.categoryRepo.FindAll()
+		if err != nil {
+			return err
+		}
+		parents := make(map[uint]*uint, len(categories))
+		for _, c := range categories {
+			parents[c.ID] = c.ParentID
+		}
+		if _, ok := parents[*req.ParentID]; !ok {
+			return errors.New("parent category not found")
+		}
+		// Telusuri leluhur induk baru; jika bertemu kategori ini berarti terjadi siklus
+		for ancestor := req.ParentID; ancestor != nil; ancestor = parents[*ancestor] {
+			if category.ID != 0 && *ancestor == category.ID {
+				return errors.New("category cannot be moved under itself or its subcategories")
+			}
+		}
+	}
+
+	category.Name = req.Name
+	category.Slug = slug
+	category.ParentID = req.ParentID
+	category.Position = req.Position
+	return nil
+}
+
+// buildCategoryTree menyusun anak-anak parentID (nil untuk akar) secara rekursif
+func buildCategoryTree(categories []model.Category, parentID *uint) []dto.CategoryResponse {
+	tree := []dto.CategoryResponse{}
+	for i := range categories {
+		category := &categories[i]
+		if !sameParent(category.ParentID, parentID) {
+			continue
+		}
+		response := mapCategoryToResponse(category)
+		response.Children = buildCategoryTree(categories, &category.ID)
+		tree = append(tree, *response)
+	}
+	return tree
+}
+
+// categoryWithDescendants mengembalikan ID kategori yang diberikan beserta semua turunannya
+func categoryWithDescendants(categories []model.Category, ids []uint) []uint {
+	children := make(map[uint][]uint)
+	for _, category := range categories {
+		if category.ParentID != nil {
+			children[*category.ParentID] = append(children[*category.ParentID], category.ID)
+		}
+	}
+
+	seen := make(map[uint]bool)
+	var result []uint
+	queue := append([]uint{}, ids...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+		queue = append(queue, children[id]...)
+	}
+	return result
+}
+
+// resolveCategories memuat kategori untuk category_ids event; ID yang tidak ada ditolak
+func resolveCategories(categoryRepo repository.CategoryRepository, ids []uint) ([]model.Category, error) {
+	if len(ids) == 0 {
+		return []model.Category{}, nil
+	}
+
+	unique := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		unique[id] = true
+	}
+	categories, err := categoryRepo.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(categories) != len(unique) {
+		return nil, errors.New("category not found")
+	}
+	return categories, nil
+}
+
+func sameParent(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func mapCategoryToResponse(category *model.Category) *dto.CategoryResponse {
+	return &dto.CategoryResponse{
+		ID:       category.ID,
+		Name:     category.Name,
+		Slug:     category.Slug,
+		ParentID: category.ParentID,
+		Position: category.Position,
+	}
+}
+
+func mapCategoriesToResponse(categories []model.Category) []dto.CategoryResponse {
+	responses := []dto.CategoryResponse{}
+	for i := range categories {
+		responses = append(responses, *mapCategoryToResponse(&categories[i]))
+	}
+	return responses
+}
@@ -0,0 +1,499 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"ticketing/credential"
+	"ticketing/dto"
+	"ticketing/model"
+	"ticketing/repository"
+	"ticketing/utils"
+
+	"gorm.io/gorm"
+)
+
+var ErrNotAssignedStaff = errors.New("you are not assigned to check in tickets for this event")
+
+type CheckInService interface {
+	CheckIn(scannerID uint, isAdmin bool, req dto.CheckInRequest) (*dto.CheckInResponse, error)
+	GetBundle(scannerID uint, isAdmin bool, eventID uint) (*dto.ScannerBundleResponse, error)
+	SyncOffline(scannerID uint, isAdmin bool, eventID uint, req dto.OfflineSyncRequest) (*dto.OfflineSyncResponse, error)
+	GetSummary(eventID uint) (*dto.CheckInSummaryResponse, error)
+	GetScans(eventID uint, page, limit int, result string) ([]dto.CheckInScanResponse, *dto.Pagination, error)
+	ReviewConflict(reviewerID, checkInID uint, req dto.CheckInReviewRequest) (*dto.CheckInScanResponse, error)
+	AssignStaff(eventID uint, req dto.StaffAssignmentRequest) (*dto.StaffAssignmentResponse, error)
+	GetStaff(eventID uint) ([]dto.StaffAssignmentResponse, error)
+	UnassignStaff(eventID, userID uint) error
+}
+
+type checkInService struct {
+	checkInRepo       repository.CheckInRepository
+	credentialRepo    repository.CredentialRepository
+	passRepo          repository.PassRepository
+	ticketRepo        repository.TicketRepository
+	eventRepo         repository.EventRepository
+	userRepo          repository.UserRepository
+	credentialService CredentialService
+}
+
+func NewCheckInService(checkInRepo repository.CheckInRepository, credentialRepo repository.CredentialRepository,
+	passRepo repository.PassRepository, ticketRepo repository.TicketRepository, eventRepo repository.EventRepository, userRepo repository.UserRepository,
+	credentialService CredentialService) CheckInService {
+	return &checkInService{
+		checkInRepo:       checkInRepo,
+		credentialRepo:    credentialRepo,
+		passRepo:          passRepo,
+		ticketRepo:        ticketRepo,
+		eventRepo:         eventRepo,
+		userRepo:          userRepo,
+		credentialService: credentialService,
+	}
+}
+
+// CheckIn memverifikasi kredensial yang dipindai lalu memasukkan pemegangnya.
+// Penolakan (kredensial tidak sah, dicabut, atau tiket sudah dipakai) tidak
+// dianggap error: hasilnya dikembalikan dengan result "rejected" beserta alasannya.
+func (s *checkInService) CheckIn(scannerID uint, isAdmin bool, req dto.CheckInRequest) (*dto.CheckInResponse, error) {
+	now := time.Now()
+	response := &dto.CheckInResponse{
+		Result:    string(model.CheckInRejected),
+		Gate:      req.Gate,
+		ScannedAt: now,
+	}
+
+	claims, err := s.credentialService.Verify(strings.TrimSpace(req.Credential))
+	if err != nil {
+		if isCredentialError(err) {
+			response.Reason = err.Error()
+			return response, nil
+		}
+		return nil, err
+	}
+
+	if err := s.authorize(scannerID, isAdmin, claims.EventID); err != nil {
+		return nil, err
+	}
+
+	response.TicketID = claims.TicketID
+	response.EventID = claims.EventID
+	scan := &model.CheckIn{
+		TicketID:     claims.TicketID,
+		EventID:      claims.EventID,
+		PassID:       claimedPass(claims),
+		CredentialID: claims.ID,
+		Gate:         req.Gate,
+		ScannerID:    scannerID,
+		ScannedAt:    now,
+	}
+	response.PassID = scan.PassID
+	if scan.PassID != nil {
+		if pass, err := s.passRepo.FindByID(scan.TicketID, *scan.PassID); err == nil {
+			response.Attendee = pass.Name
+		}
+	}
+
+	issued, err := s.credentialRepo.FindByCredentialID(claims.ID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return s.reject(scan, response, "unknown ticket credential")
+	case err != nil:
+		return nil, err
+	case issued.RevokedAt != nil:
+		return s.reject(scan, response, "ticket credential was revoked ("+issued.RevokeReason+")")
+	}
+
+	ticket, err := s.checkInRepo.Admit(scan, claims.Version, req.Admit)
+	switch {
+	case err == nil:
+		response.ID = scan.ID
+		response.Result = string(model.CheckInAdmitted)
+		response.Admitted = scan.Admitted
+		response.Qty = ticket.Qty
+		response.CheckedIn = ticket.CheckedIn
+		response.Remaining = ticket.Qty - ticket.CheckedIn
+		return response, nil
+	case errors.Is(err, repository.ErrAlreadyCheckedIn):
+		reason := err.Error()
+		if last, lastErr := s.checkInRepo.LastAdmitted(scan.TicketID); lastErr == nil {
+			reason = fmt.Sprintf("%s (last admitted at %s, gate %s)", reason,
+				last.ScannedAt.Format(time.RFC3339), last.Gate)
+		}
+		return s.reject(scan, response, reason)
+	case errors.Is(err, repository.ErrPassCheckedIn):
+		reason := err.Error()
+		if pass, passErr := s.passRepo.FindByID(scan.TicketID, *scan.PassID); passErr == nil && pass.CheckedInAt != nil {
+			reason = fmt.Sprintf("%s (at %s, gate %s)", reason,
+				pass.CheckedInAt.Format(time.RFC3339), pass.CheckInGate)
+		}
+		return s.reject(scan, response, reason)
+	case errors.Is(err, repository.ErrAdmitExceedsRemaining),
+		errors.Is(err, repository.ErrCredentialSuperseded),
+		errors.Is(err, repository.ErrTicketNotAdmissible):
+		return s.reject(scan, response, err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return s.reject(scan, response, "ticket not found")
+	default:
+		return nil, err
+	}
+}
+
+// reject mencatat scan yang ditolak dan melengkapi response dengan sisa kuota tiket
+func (s *checkInService) reject(scan *model.CheckIn, response *dto.CheckInResponse, reason string) (*dto.CheckInResponse, error) {
+	scan.Result = model.CheckInRejected
+	scan.Reason = reason
+	if err := s.checkInRepo.Record(scan); err != nil {
+		log.Printf("Failed to record rejected scan for ticket %d: %v", scan.TicketID, err)
+	}
+
+	response.ID = scan.ID
+	response.Reason = reason
+	if ticket, err := s.ticketRepo.FindByID(scan.TicketID); err == nil {
+		response.Qty = ticket.Qty
+		response.CheckedIn = ticket.CheckedIn
+		response.Remaining = ticket.Qty - ticket.CheckedIn
+	}
+	return response, nil
+}
+
+// authorize memastikan staff ditugaskan di event; admin boleh di semua event
+func (s *checkInService) authorize(scannerID uint, isAdmin bool, eventID uint) error {
+	if isAdmin {
+		return nil
+	}
+	assigned, err := s.checkInRepo.IsAssigned(eventID, scannerID)
+	if err != nil {
+		return err
+	}
+	if !assigned {
+		return ErrNotAssignedStaff
+	}
+	return nil
+}
+
+// claimedPass mengembalikan ID attendee pass pada kredensial, nil untuk kredensial tiket
+func claimedPass(claims *credential.Claims) *uint {
+	if claims.PassID == 0 {
+		return nil
+	}
+	passID := claims.PassID
+	return &passID
+}
+
+func isCredentialError(err error) bool {
+	return errors.Is(err, credential.ErrMalformed) || errors.Is(err, credential.ErrUnknownKey) ||
+		errors.Is(err, credential.ErrBadSignature) || errors.Is(err, credential.ErrExpired)
+}
+
+// GetBundle menyiapkan data untuk scanner yang akan bekerja tanpa koneksi
+func (s *checkInService) GetBundle(scannerID uint, isAdmin bool, eventID uint) (*dto.ScannerBundleResponse, error) {
+	if err := s.authorize(scannerID, isAdmin, eventID); err != nil {
+		return nil, err
+	}
+
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	keys, err := s.credentialService.GetPublicKeys()
+	if err != nil {
+		return nil, err
+	}
+	revoked, err := s.credentialService.GetRevoked(eventID)
+	if err != nil {
+		return nil, err
+	}
+	tickets, err := s.checkInRepo.FindBundleTickets(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	bundleTickets := []dto.BundleTicketResponse{}
+	for _, ticket := range tickets {
+		passes := []dto.BundlePassResponse{}
+		for _, pass := range ticket.Passes {
+			passes = append(passes, dto.BundlePassResponse{PassID: pass.ID, CheckedIn: pass.CheckedInAt != nil})
+		}
+		bundleTickets = append(bundleTickets, dto.BundleTicketResponse{
+			TicketID:  ticket.ID,
+			Qty:       ticket.Qty,
+			CheckedIn: ticket.CheckedIn,
+			Version:   ticket.CredentialVersion,
+			Passes:    passes,
+		})
+	}
+
+	return &dto.ScannerBundleResponse{
+		EventID:     event.ID,
+		EventName:   event.Name,
+		GeneratedAt: time.Now(),
+		Keys:        keys.Keys,
+		Tickets:     bundleTickets,
+		Revoked:     revoked,
+	}, nil
+}
+
+// SyncOffline memasukkan log scan dari scanner offline. Scan diproses berurutan
+// menurut waktu scan; scan yang pernah diupload (device_id + scan_id sama)
+// dilewati. Scan yang bentrok dengan data server dicatat sebagai konflik.
+func (s *checkInService) SyncOffline(scannerID uint, isAdmin bool, eventID uint, req dto.OfflineSyncRequest) (*dto.OfflineSyncResponse, error) {
+	if err := s.authorize(scannerID, isAdmin, eventID); err != nil {
+		return nil, err
+	}
+
+	scans := make([]dto.OfflineScanRequest, len(req.Scans))
+	copy(scans, req.Scans)
+	sort.SliceStable(scans, func(i, j int) bool {
+		return scans[i].ScannedAt.Before(scans[j].ScannedAt)
+	})
+
+	response := &dto.OfflineSyncResponse{
+		DeviceID: req.DeviceID,
+		Received: len(scans),
+		Results:  []dto.OfflineScanResult{},
+	}
+	for _, scan := range scans {
+		result, err := s.syncScan(scannerID, eventID, req.DeviceID, scan)
+		if err != nil {
+			return nil, err
+		}
+
+		switch result.Status {
+		case string(model.CheckInAdmitted):
+			response.Admitted++
+		case string(model.CheckInRejected):
+			response.Rejected++
+		case string(model.CheckInConflict):
+			response.Conflicts++
+		case "duplicate":
+			response.Duplicates++
+		default:
+			response.Invalid++
+		}
+		response.Results = append(response.Results, *result)
+	}
+
+	return response, nil
+}
+
+func (s *checkInService) syncScan(scannerID, eventID uint, deviceID string, req dto.OfflineScanRequest) (*dto.OfflineScanResult, error) {
+	result := &dto.OfflineScanResult{ScanID: req.ScanID, Status: "invalid"}
+
+	if existing, err := s.checkInRepo.FindByDeviceScan(deviceID, req.ScanID); err == nil {
+		result.Status = "duplicate"
+		result.CheckInID = existing.ID
+		result.Admitted = existing.Admitted
+		return result, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if req.ScannedAt.After(time.Now().Add(5 * time.Minute)) {
+		result.Reason = "scan time is in the future"
+		return result, nil
+	}
+
+	claims, err := s.credentialService.VerifyOffline(strings.TrimSpace(req.Credential), req.ScannedAt)
+	if err != nil {
+		if isCredentialError(err) {
+			result.Reason = err.Error()
+			return result, nil
+		}
+		return nil, err
+	}
+	if claims.EventID != eventID {
+		result.Reason = "ticket credential is for another event"
+		return result, nil
+	}
+
+	clientScanID := req.ScanID
+	scan := &model.CheckIn{
+		TicketID:     claims.TicketID,
+		EventID:      claims.EventID,
+		PassID:       claimedPass(claims),
+		CredentialID: claims.ID,
+		Gate:         req.Gate,
+		ScannerID:    scannerID,
+		ScannedAt:    req.ScannedAt,
+		DeviceID:     &deviceID,
+		ClientScanID: &clientScanID,
+	}
+
+	// Scanner sudah menolak scan ini saat offline: cukup dicatat
+	if req.Result == string(model.CheckInRejected) {
+		scan.Result = model.CheckInRejected
+		scan.Reason = req.Reason
+		if err := s.checkInRepo.Record(scan); err != nil {
+			return nil, err
+		}
+		result.Status = string(scan.Result)
+		result.CheckInID = scan.ID
+		result.Reason = scan.Reason
+		return result, nil
+	}
+
+	admit := req.Admit
+	if admit == 0 {
+		admit = 1
+	}
+
+	issued, err := s.credentialRepo.FindByCredentialID(claims.ID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		result.Reason = "unknown ticket credential"
+		return result, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if issued.RevokedAt != nil && issued.RevokedAt.Before(req.ScannedAt) {
+		// Kredensial sudah dicabut sebelum scan, tapi orangnya sudah masuk: catat sebagai konflik
+		scan.Result = model.CheckInConflict
+		scan.Reason = "ticket credential was revoked before the scan (" + issued.RevokeReason + ")"
+		if err := s.checkInRepo.Record(scan); err != nil {
+			return nil, err
+		}
+	} else if err := s.checkInRepo.AdmitOffline(scan, claims.Version, admit); err != nil {
+		return nil, err
+	}
+
+	result.Status = string(scan.Result)
+	result.CheckInID = scan.ID
+	result.Admitted = scan.Admitted
+	result.Reason = scan.Reason
+	return result, nil
+}
+
+func (s *checkInService) GetSummary(eventID uint) (*dto.CheckInSummaryResponse, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	summary, err := s.checkInRepo.Summary(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	gates := []dto.GateCountResponse{}
+	for _, g := range summary.Gates {
+		gates = append(gates, dto.GateCountResponse{Gate: g.Gate, Admitted: g.Admitted})
+	}
+
+	return &dto.CheckInSummaryResponse{
+		EventID:    event.ID,
+		EventName:  event.Name,
+		Capacity:   event.Capacity,
+		Booked:     summary.Booked,
+		CheckedIn:  summary.CheckedIn,
+		Remaining:  summary.Booked - summary.CheckedIn,
+		Rejected:   summary.Rejected,
+		Conflicts:  summary.Conflicts,
+		Gates:      gates,
+		LastScanAt: summary.LastScanAt,
+	}, nil
+}
+
+func (s *checkInService) GetScans(eventID uint, page, limit int, result string) ([]dto.CheckInScanResponse, *dto.Pagination, error) {
+	scans, total, err := s.checkInRepo.FindScans(eventID, page, limit, result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	responses := []dto.CheckInScanResponse{}
+	for i := range scans {
+		responses = append(responses, *mapCheckInToScanResponse(&scans[i]))
+	}
+
+	pagination := utils.GeneratePagination(page, limit, total)
+	return responses, &pagination, nil
+}
+
+func (s *checkInService) ReviewConflict(reviewerID, checkInID uint, req dto.CheckInReviewRequest) (*dto.CheckInScanResponse, error) {
+	scan, err := s.checkInRepo.Review(checkInID, reviewerID, strings.TrimSpace(req.Note))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("no open check-in conflict with this ID")
+		}
+		return nil, err
+	}
+	return mapCheckInToScanResponse(scan), nil
+}
+
+func mapCheckInToScanResponse(scan *model.CheckIn) *dto.CheckInScanResponse {
+	return &dto.CheckInScanResponse{
+		ID:           scan.ID,
+		TicketID:     scan.TicketID,
+		PassID:       scan.PassID,
+		CredentialID: scan.CredentialID,
+		Gate:         scan.Gate,
+		ScannerID:    scan.ScannerID,
+		ScannerEmail: scan.Scanner.Email,
+		Result:       string(scan.Result),
+		Reason:       scan.Reason,
+		Admitted:     scan.Admitted,
+		ScannedAt:    scan.ScannedAt,
+		DeviceID:     scan.DeviceID,
+		ReviewedAt:   scan.ReviewedAt,
+		ReviewNote:   scan.ReviewNote,
+	}
+}
+
+func (s *checkInService) AssignStaff(eventID uint, req dto.StaffAssignmentRequest) (*dto.StaffAssignmentResponse, error) {
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	user, err := s.userRepo.FindByEmail(strings.ToLower(strings.TrimSpace(req.Email)))
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.Role != model.Staff {
+		return nil, errors.New("only users with the staff role can be assigned")
+	}
+
+	assignment := &model.StaffAssignment{EventID: eventID, UserID: user.ID}
+	if err := s.checkInRepo.AssignStaff(assignment); err != nil {
+		return nil, err
+	}
+	assignment.User = *user
+
+	return mapStaffAssignmentToResponse(assignment), nil
+}
+
+func (s *checkInService) GetStaff(eventID uint) ([]dto.StaffAssignmentResponse, error) {
+	assignments, err := s.checkInRepo.FindStaff(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := []dto.StaffAssignmentResponse{}
+	for i := range assignments {
+		responses = append(responses, *mapStaffAssignmentToResponse(&assignments[i]))
+	}
+	return responses, nil
+}
+
+func (s *checkInService) UnassignStaff(eventID, userID uint) error {
+	if err := s.checkInRepo.UnassignStaff(eventID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("staff is not assigned to this event")
+		}
+		return err
+	}
+	return nil
+}
+
+func mapStaffAssignmentToResponse(assignment *model.StaffAssignment) *dto.StaffAssignmentResponse {
+	return &dto.StaffAssignmentResponse{
+		UserID:     assignment.UserID,
+		Name:       assignment.User.Name,
+		Email:      assignment.User.Email,
+		AssignedAt: assignment.CreatedAt,
+	}
+}
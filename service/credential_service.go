@@ -0,0 +1,310 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"log"
+	"time"
+
+	"ticketing/credential"
+	"ticketing/dto"
+	"ticketing/model"
+	"ticketing/repository"
+)
+
+// credentialGrace adalah lama kredensial tetap berlaku setelah event dimulai
+const credentialGrace = 24 * time.Hour
+
+type CredentialService interface {
+	EnsureActiveKey() error
+	RotateKey() (*dto.SigningKeyResponse, error)
+	GetPublicKeys() (*dto.SigningKeysResponse, error)
+	IssueForPayment(p *model.Payment)
+	Issue(ticketID uint) (*model.TicketCredential, error)
+	Revoke(ticketID uint, reason string)
+	RevokePass(passID uint, reason string)
+	Verify(token string) (*credential.Claims, error)
+	VerifyOffline(token string, scannedAt time.Time) (*credential.Claims, error)
+	GetCredential(userID uint, isAdmin bool, ticketID uint) (*dto.CredentialResponse, error)
+	GetPassCredential(ticket *model.Ticket, pass *model.AttendeePass) (*dto.CredentialResponse, error)
+	GetRevoked(eventID uint) ([]dto.RevokedCredentialResponse, error)
+}
+
+type credentialService struct {
+	credentialRepo repository.CredentialRepository
+	ticketRepo     repository.TicketRepository
+	orderRepo      repository.OrderRepository
+}
+
+func NewCredentialService(credentialRepo repository.CredentialRepository, ticketRepo repository.TicketRepository,
+	orderRepo repository.OrderRepository) CredentialService {
+	return &credentialService{
+		credentialRepo: credentialRepo,
+		ticketRepo:     ticketRepo,
+		orderRepo:      orderRepo,
+	}
+}
+
+// EnsureActiveKey membuat kunci pertama saat aplikasi dijalankan jika belum ada
+func (s *credentialService) EnsureActiveKey() error {
+	if _, err := s.credentialRepo.ActiveKey(); err == nil {
+		return nil
+	}
+	_, err := s.RotateKey()
+	return err
+}
+
+func (s *credentialService) RotateKey() (*dto.SigningKeyResponse, error) {
+	keyID, pub, priv, err := credential.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &model.SigningKey{
+		KeyID:      keyID,
+		PublicKey:  base64.StdEncoding.EncodeToString(pub),
+		PrivateKey: base64.StdEncoding.EncodeToString(priv),
+	}
+	if err := s.credentialRepo.Rotate(key); err != nil {
+		return nil, err
+	}
+
+	return mapSigningKeyToResponse(key), nil
+}
+
+// GetPublicKeys mengembalikan kunci aktif dan sebelumnya untuk diverifikasi scanner
+func (s *credentialService) GetPublicKeys() (*dto.SigningKeysResponse, error) {
+	keys, err := s.credentialRepo.FindKeys([]model.SigningKeyStatus{model.SigningKeyActive, model.SigningKeyPrevious})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.SigningKeysResponse{Keys: []dto.SigningKeyResponse{}}
+	for i := range keys {
+		response.Keys = append(response.Keys, *mapSigningKeyToResponse(&keys[i]))
+	}
+	return response, nil
+}
+
+// IssueForPayment menerbitkan kredensial untuk tiket yang baru dibooking oleh pembayaran.
+// Kegagalan hanya dicatat; kredensial akan diterbitkan saat pertama kali diminta.
+func (s *credentialService) IssueForPayment(p *model.Payment) {
+	var ticketIDs []uint
+	switch {
+	case p.OrderID != nil:
+		order, err := s.orderRepo.FindByID(*p.OrderID)
+		if err != nil {
+			log.Printf("Failed to load order %d for credentials: %v", *p.OrderID, err)
+			return
+		}
+		for _, ticket := range order.Tickets {
+			ticketIDs = append(ticketIDs, ticket.ID)
+		}
+	case p.TicketID != nil:
+		ticketIDs = append(ticketIDs, *p.TicketID)
+	}
+
+	for _, ticketID := range ticketIDs {
+		if _, err := s.Issue(ticketID); err != nil {
+			log.Printf("Failed to issue credential for ticket %d: %v", ticketID, err)
+		}
+	}
+}
+
+// Issue menandatangani kredensial baru untuk tiket booked dan mencabut yang lama
+func (s *credentialService) Issue(ticketID uint) (*model.TicketCredential, error) {
+	ticket, err := s.ticketRepo.FindByID(ticketID)
+	if err != nil {
+		return nil, errors.New("ticket not found")
+	}
+	return s.issue(ticket, nil)
+}
+
+// issue menandatangani kredensial untuk seluruh tiket, atau untuk satu pass jika pass diisi
+func (s *credentialService) issue(ticket *model.Ticket, pass *model.AttendeePass) (*model.TicketCredential, error) {
+	if ticket.Status != model.Booked {
+		return nil, errors.New("credentials are only issued for booked tickets")
+	}
+
+	key, err := s.credentialRepo.ActiveKey()
+	if err != nil {
+		return nil, errors.New("no active signing key")
+	}
+	privateKey, err := base64.StdEncoding.DecodeString(key.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := credential.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expiresAt := ticket.Event.StartsAt.Add(credentialGrace)
+	claims := credential.Claims{
+		ID:        id,
+		KeyID:     key.KeyID,
+		TicketID:  ticket.ID,
+		EventID:   ticket.EventID,
+		Qty:       ticket.Qty,
+		Version:   ticket.CredentialVersion,
+		NotBefore: now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	}
+	var passID *uint
+	if pass != nil {
+		claims.PassID = pass.ID
+		claims.Qty = 1
+		passID = &pass.ID
+	}
+
+	token, err := credential.Sign(ed25519.PrivateKey(privateKey), claims)
+	if err != nil {
+		return nil, err
+	}
+
+	issued := &model.TicketCredential{
+		CredentialID: id,
+		TicketID:     ticket.ID,
+		PassID:       passID,
+		EventID:      ticket.EventID,
+		KeyID:        key.KeyID,
+		Version:      ticket.CredentialVersion,
+		Token:        token,
+		ExpiresAt:    expiresAt,
+	}
+	if err := s.credentialRepo.Issue(issued, "reissued"); err != nil {
+		return nil, err
+	}
+	return issued, nil
+}
+
+// Revoke mencabut kredensial tiket (mis. setelah dibatalkan); kegagalan hanya dicatat
+func (s *credentialService) Revoke(ticketID uint, reason string) {
+	if err := s.credentialRepo.Revoke(ticketID, reason); err != nil {
+		log.Printf("Failed to revoke credentials for ticket %d: %v", ticketID, err)
+	}
+}
+
+// Verify memeriksa kredensial terhadap kunci yang dipublikasikan, sama seperti scanner offline
+func (s *credentialService) Verify(token string) (*credential.Claims, error) {
+	return s.verify(token, time.Now(), []model.SigningKeyStatus{model.SigningKeyActive, model.SigningKeyPrevious})
+}
+
+// VerifyOffline memeriksa kredensial pada waktu scan dilakukan. Kunci yang sudah
+// dipensiunkan tetap diterima karena scanner bisa memakai bundle yang lebih lama.
+func (s *credentialService) VerifyOffline(token string, scannedAt time.Time) (*credential.Claims, error) {
+	return s.verify(token, scannedAt, []model.SigningKeyStatus{
+		model.SigningKeyActive, model.SigningKeyPrevious, model.SigningKeyRetired,
+	})
+}
+
+func (s *credentialService) verify(token string, at time.Time, statuses []model.SigningKeyStatus) (*credential.Claims, error) {
+	keys, err := s.credentialRepo.FindKeys(statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKeys := make(map[string]ed25519.PublicKey, len(keys))
+	for _, key := range keys {
+		if pub, err := base64.StdEncoding.DecodeString(key.PublicKey); err == nil {
+			publicKeys[key.KeyID] = pub
+		}
+	}
+
+	return credential.Verify(token, func(keyID string) (ed25519.PublicKey, bool) {
+		pub, ok := publicKeys[keyID]
+		return pub, ok
+	}, at)
+}
+
+func (s *credentialService) RevokePass(passID uint, reason string) {
+	if err := s.credentialRepo.RevokePass(passID, reason); err != nil {
+		log.Printf("Failed to revoke credentials for attendee pass %d: %v", passID, err)
+	}
+}
+
+func (s *credentialService) GetCredential(userID uint, isAdmin bool, ticketID uint) (*dto.CredentialResponse, error) {
+	ticket, err := s.ticketRepo.FindByID(ticketID)
+	if err != nil || (!isAdmin && ticket.UserID != userID) {
+		return nil, errors.New("ticket not found")
+	}
+	if ticket.Status != model.Booked {
+		return nil, errors.New("credentials are only issued for booked tickets")
+	}
+
+	issued, err := s.credentialRepo.FindActive(ticket.ID)
+	if err != nil || issued.Version != ticket.CredentialVersion {
+		// Tiket lama atau penerbitan sebelumnya gagal: terbitkan sekarang
+		if issued, err = s.issue(ticket, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return mapCredentialToResponse(issued), nil
+}
+
+// GetPassCredential mengembalikan kredensial satu attendee pass; kepemilikan sudah dicek pemanggil
+func (s *credentialService) GetPassCredential(ticket *model.Ticket, pass *model.AttendeePass) (*dto.CredentialResponse, error) {
+	issued, err := s.credentialRepo.FindActiveForPass(pass.ID)
+	if err != nil || issued.Version != ticket.CredentialVersion {
+		if issued, err = s.issue(ticket, pass); err != nil {
+			return nil, err
+		}
+	}
+
+	return mapCredentialToResponse(issued), nil
+}
+
+func mapCredentialToResponse(issued *model.TicketCredential) *dto.CredentialResponse {
+	return &dto.CredentialResponse{
+		CredentialID: issued.CredentialID,
+		TicketID:     issued.TicketID,
+		PassID:       issued.PassID,
+		KeyID:        issued.KeyID,
+		Version:      issued.Version,
+		Token:        issued.Token,
+		ExpiresAt:    issued.ExpiresAt,
+	}
+}
+
+func (s *credentialService) GetRevoked(eventID uint) ([]dto.RevokedCredentialResponse, error) {
+	revoked, err := s.credentialRepo.FindRevoked(eventID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	responses := []dto.RevokedCredentialResponse{}
+	for _, c := range revoked {
+		responses = append(responses, dto.RevokedCredentialResponse{
+			CredentialID: c.CredentialID,
+			TicketID:     c.TicketID,
+			PassID:       c.PassID,
+			EventID:      c.EventID,
+			Version:      c.Version,
+			RevokedAt:    *c.RevokedAt,
+			Reason:       c.RevokeReason,
+			ExpiresAt:    c.ExpiresAt,
+		})
+	}
+	return responses, nil
+}
+
+func mapSigningKeyToResponse(key *model.SigningKey) *dto.SigningKeyResponse {
+	x := ""
+	if pub, err := base64.StdEncoding.DecodeString(key.PublicKey); err == nil {
+		x = base64.RawURLEncoding.EncodeToString(pub)
+	}
+
+	return &dto.SigningKeyResponse{
+		KeyID:       key.KeyID,
+		KeyType:     "OKP",
+		Curve:       "Ed25519",
+		X:           x,
+		Use:         "sig",
+		Status:      string(key.Status),
+		ActivatedAt: key.ActivatedAt,
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"ticketing/model"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+// renderETicket membuat PDF e-ticket di memori dengan QR code berisi token tiket
+func renderETicket(ticket *model.Ticket, token string) ([]byte, error) {
+	qr, err := qrcode.Encode(token, qrcode.Medium, 512)
+	if err != nil {
+		return nil, err
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("E-Ticket #"+strconv.Itoa(int(ticket.ID)), true)
+	pdf.AddPage()
+
+	pdf.SetFont("Arial", "B", 20)
+	pdf.CellFormat(0, 12, ticket.Event.Name, "", 1, "L", false, 0, "")
+	pdf.SetFont("Arial", "", 11)
+	pdf.CellFormat(0, 6, "E-Ticket #"+strconv.Itoa(int(ticket.ID)), "", 1, "L", false, 0, "")
+	pdf.Ln(6)
+
+	rows := [][2]string{
+		{"Date", ticket.Event.StartsAt.In(ticket.Event.TimeLocation()).Format("Mon, 02 Jan 2006 15:04 MST")},
+		{"Location", ticket.Event.Location},
+		{"Attendee", ticket.User.Name + " <" + ticket.User.Email + ">"},
+		{"Quantity", strconv.Itoa(ticket.Qty)},
+	}
+	if ticket.TicketType != nil {
+		rows = append(rows, [2]string{"Ticket Type", ticket.TicketType.Name})
+	}
+	if len(ticket.Seats) > 0 {
+		var labels []string
+		for i := range ticket.Seats {
+			labels = append(labels, ticket.Seats[i].Label())
+		}
+		rows = append(rows, [2]string{"Seats", strings.Join(labels, ", ")})
+	}
+
+	for _, row := range rows {
+		pdf.SetFont("Arial", "B", 12)
+		pdf.CellFormat(35, 8, row[0], "", 0, "L", false, 0, "")
+		pdf.SetFont("Arial", "", 12)
+		pdf.MultiCell(0, 8, row[1], "", "L", false)
+	}
+
+	pdf.Ln(6)
+	pdf.RegisterImageOptionsReader("qr", gofpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qr))
+	pdf.ImageOptions("qr", 65, pdf.GetY(), 80, 80, false, gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+	pdf.SetY(pdf.GetY() + 84)
+	pdf.SetFont("Arial", "I", 9)
+	pdf.CellFormat(0, 5, "Present this QR code at the entrance. Do not share it.", "", 1, "C", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
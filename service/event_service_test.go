@@ -0,0 +1,39 @@
+package service
+
+import "testing"
+
+func TestParseGeoPoint(t *testing.T) {
+	tests := []struct {
+		value    string
+		wantErr  bool
+		lat, lng float64
+	}{
+		{value: "-6.1754,106.8272", lat: -6.1754, lng: 106.8272},
+		{value: " 51.5 , -0.12 ", lat: 51.5, lng: -0.12},
+		{value: "90,180", lat: 90, lng: 180},
+		{value: "-6.1754", wantErr: true},
+		{value: "91,0", wantErr: true},
+		{value: "0,-181", wantErr: true},
+		{value: "NaN,106.8", wantErr: true},
+		{value: "-6.1,NaN", wantErr: true},
+		{value: "Inf,0", wantErr: true},
+		{value: "abc,def", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		point, err := parseGeoPoint(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseGeoPoint(%q) = %+v, want an error", tt.value, *point)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseGeoPoint(%q): %v", tt.value, err)
+			continue
+		}
+		if point.Lat != tt.lat || point.Lng != tt.lng {
+			t.Errorf("parseGeoPoint(%q) = %+v, want %v,%v", tt.value, *point, tt.lat, tt.lng)
+		}
+	}
+}
@@ -2,38 +2,85 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 
 	"ticketing/dto"
 	"ticketing/model"
 	"ticketing/repository"
+	"ticketing/utils"
 )
 
+// ErrInvalidEventQuery dikembalikan ketika query string pencarian event tidak valid
+var ErrInvalidEventQuery = errors.New("invalid event query")
+
+// defaultNearRadiusKm adalah radius pencarian near jika radius_km tidak dikirim
+const defaultNearRadiusKm = 10
+
+// defaultHoldMinutes adalah lama hold tiket yang belum dibayar jika admin tidak mengaturnya
+const defaultHoldMinutes = 15
+
 type EventService interface {
 	CreateEvent(req dto.EventRequest) (*dto.EventResponse, error)
-	GetAllEvents(page, limit int, search string) ([]dto.EventResponse, *dto.Pagination, error)
+	GetAllEvents(page, limit int, query dto.EventSearchQuery) ([]dto.EventResponse, *dto.Pagination, map[string][]dto.FacetCount, error)
 	GetEventByID(id uint) (*dto.EventResponse, error)
 	UpdateEvent(id uint, req dto.EventRequest) (*dto.EventResponse, error)
 	DeleteEvent(id uint) error
+	SetStatus(id uint, req dto.EventStatusRequest) (*dto.EventResponse, error)
 }
 
 type eventService struct {
-	eventRepo repository.EventRepository
+	eventRepo       repository.EventRepository
+	venueRepo       repository.VenueRepository
+	categoryRepo    repository.CategoryRepository
+	tagRepo         repository.TagRepository
+	waitlistService WaitlistService
+	statusScheduler *EventStatusScheduler
+	defaultTimezone string
 }
 
-func NewEventService(eventRepo repository.EventRepository) EventService {
-	return &eventService{eventRepo: eventRepo}
+func NewEventService(eventRepo repository.EventRepository, venueRepo repository.VenueRepository,
+	categoryRepo repository.CategoryRepository, tagRepo repository.TagRepository, waitlistService WaitlistService,
+	statusScheduler *EventStatusScheduler, defaultTimezone string) EventService {
+	return &eventService{
+		eventRepo:       eventRepo,
+		venueRepo:       venueRepo,
+		categoryRepo:    categoryRepo,
+		tagRepo:         tagRepo,
+		waitlistService: waitlistService,
+		statusScheduler: statusScheduler,
+		defaultTimezone: defaultTimezone,
+	}
 }
 
 func (s *eventService) CreateEvent(req dto.EventRequest) (*dto.EventResponse, error) {
 	event := &model.Event{
-		Name:        req.Name,
-		Description: req.Description,
-		Location:    req.Location,
-		DateTime:    req.DateTime,
-		Capacity:    req.Capacity,
-		Price:       req.Price,
-		Status:      model.Upcoming,
+		Name:             req.Name,
+		Description:      req.Description,
+		Location:         req.Location,
+		Timezone:         s.defaultTimezone,
+		Capacity:         req.Capacity,
+		Price:            req.Price,
+		HoldMinutes:      req.HoldMinutes,
+		TransfersAllowed: req.TransfersAllowed,
+	}
+	if event.HoldMinutes == 0 {
+		event.HoldMinutes = defaultHoldMinutes
 	}
+	if err := s.applyVenue(event, req); err != nil {
+		return nil, err
+	}
+	if err := s.applyTaxonomy(event, req); err != nil {
+		return nil, err
+	}
+	if err := applySchedule(event, req, s.statusScheduler.DefaultDuration()); err != nil {
+		return nil, err
+	}
+	// Event yang dibuat dengan jadwal di masa lalu langsung mendapat status sesuai jadwal
+	event.Status = scheduledStatus(event, time.Now())
 
 	if err := s.eventRepo.Create(event); err != nil {
 		return nil, err
@@ -46,24 +93,34 @@ func (s *eventService) CreateEvent(req dto.EventRequest) (*dto.EventResponse, er
 	}
 
 	// Now pass the available tickets to mapEventToResponse
-	return s.mapEventToResponse(event, available), nil
+	return mapEventToResponse(event, available), nil
 }
 
-func (s *eventService) GetAllEvents(page, limit int, search string) ([]dto.EventResponse, *dto.Pagination, error) {
-	events, total, err := s.eventRepo.FindAll(page, limit, search)
+func (s *eventService) GetAllEvents(page, limit int, query dto.EventSearchQuery) ([]dto.EventResponse, *dto.Pagination, map[string][]dto.FacetCount, error) {
+	filter, err := s.parseSearchQuery(query)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
+	}
+
+	events, total, err := s.eventRepo.FindAll(page, limit, filter)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	facets, err := s.eventRepo.FindFacets(filter)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
 	var responses []dto.EventResponse
 	for _, event := range events {
 		available, err := s.eventRepo.GetAvailableTickets(event.ID)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 
 		// Pass available tickets to mapEventToResponse
-		response := s.mapEventToResponse(&event, available)
+		response := mapEventToResponse(&event, available)
 		responses = append(responses, *response)
 	}
 
@@ -74,7 +131,7 @@ func (s *eventService) GetAllEvents(page, limit int, search string) ([]dto.Event
 		TotalPages: (total + int64(limit) - 1) / int64(limit),
 	}
 
-	return responses, pagination, nil
+	return responses, pagination, facets, nil
 }
 
 func (s *eventService) GetEventByID(id uint) (*dto.EventResponse, error) {
@@ -89,7 +146,7 @@ func (s *eventService) GetEventByID(id uint) (*dto.EventResponse, error) {
 	}
 
 	// Pass available tickets to mapEventToResponse
-	return s.mapEventToResponse(event, available), nil
+	return mapEventToResponse(event, available), nil
 }
 
 func (s *eventService) UpdateEvent(id uint, req dto.EventRequest) (*dto.EventResponse, error) {
@@ -103,24 +160,56 @@ func (s *eventService) UpdateEvent(id uint, req dto.EventRequest) (*dto.EventRes
 		return nil, errors.New("cannot update event that is not upcoming")
 	}
 
+	// Batas bawah kapasitas (sold+held+offered) dicek ulang di repository dengan baris event terkunci
+	capacityIncreased := req.Capacity > event.Capacity
+
+	event.Location = req.Location
+	if err := s.applyVenue(event, req); err != nil {
+		return nil, err
+	}
+	if err := s.applyTaxonomy(event, req); err != nil {
+		return nil, err
+	}
+
+	// Tanpa end_date_time, durasi event yang lama dipertahankan
+	duration := s.statusScheduler.DefaultDuration()
+	if event.EndsAt.After(event.StartsAt) {
+		duration = event.EndsAt.Sub(event.StartsAt)
+	}
+	if err := applySchedule(event, req, duration); err != nil {
+		return nil, err
+	}
+
 	event.Name = req.Name
 	event.Description = req.Description
-	event.Location = req.Location
-	event.DateTime = req.DateTime
 	event.Capacity = req.Capacity
 	event.Price = req.Price
+	if req.HoldMinutes > 0 {
+		event.HoldMinutes = req.HoldMinutes
+	}
+	if req.TransfersAllowed != nil {
+		event.TransfersAllowed = req.TransfersAllowed
+	}
 
 	if err := s.eventRepo.Update(event); err != nil {
 		return nil, err
 	}
 
+	// Kapasitas tambahan ditawarkan lebih dulu ke antrean waitlist
+	if capacityIncreased {
+		s.waitlistService.Release(event.ID)
+		if event, err = s.eventRepo.FindByID(event.ID); err != nil {
+			return nil, err
+		}
+	}
+
 	available, err := s.eventRepo.GetAvailableTickets(event.ID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Pass available tickets to mapEventToResponse
-	return s.mapEventToResponse(event, available), nil
+	return mapEventToResponse(event, available), nil
 }
 
 func (s *eventService) DeleteEvent(id uint) error {
@@ -137,16 +226,335 @@ func (s *eventService) DeleteEvent(id uint) error {
 	return s.eventRepo.Delete(id)
 }
 
-func (s *eventService) mapEventToResponse(event *model.Event, available int) *dto.EventResponse {
-	return &dto.EventResponse{
-		ID:          event.ID,
-		Name:        event.Name,
-		Description: event.Description,
-		Location:    event.Location,
-		DateTime:    event.DateTime,
-		Capacity:    event.Capacity,
-		Available:   available,
-		Price:       event.Price,
-		Status:      string(event.Status),
+// SetStatus memaksa status event (override admin) atau mengembalikannya ke jadwal
+// otomatis. Hook transisi (mis. penutupan penjualan) tetap dijalankan.
+func (s *eventService) SetStatus(id uint, req dto.EventStatusRequest) (*dto.EventResponse, error) {
+	if req.Auto == (req.Status != "") {
+		return nil, errors.New("provide either status or auto")
+	}
+
+	event, err := s.eventRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	if req.Auto {
+		err = s.statusScheduler.Resume(event)
+	} else {
+		err = s.statusScheduler.Override(event, model.EventStatus(req.Status))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetEventByID(id)
+}
+
+// parseSearchQuery memvalidasi query string GET /events menjadi filter repository.
+// Tanggal tanpa jam dibaca di DEFAULT_TIMEZONE; "to" berupa tanggal mencakup seluruh hari itu.
+func (s *eventService) parseSearchQuery(query dto.EventSearchQuery) (repository.EventFilter, error) {
+	filter := repository.EventFilter{
+		Search:    strings.TrimSpace(query.Search),
+		MinPrice:  query.MinPrice,
+		MaxPrice:  query.MaxPrice,
+		Available: query.Available,
+	}
+	invalid := func(format string, args ...interface{}) (repository.EventFilter, error) {
+		return repository.EventFilter{}, fmt.Errorf("%w: %s", ErrInvalidEventQuery, fmt.Sprintf(format, args...))
+	}
+
+	loc, err := time.LoadLocation(s.defaultTimezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	if query.From != "" {
+		from, _, err := parseSearchTime(query.From, loc)
+		if err != nil {
+			return invalid("from: %v", err)
+		}
+		filter.From = &from
+	}
+	if query.To != "" {
+		to, dateOnly, err := parseSearchTime(query.To, loc)
+		if err != nil {
+			return invalid("to: %v", err)
+		}
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return invalid("from must be before to")
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return invalid("min_price cannot be greater than max_price")
+	}
+
+	for _, status := range splitList(query.Status) {
+		switch model.EventStatus(status) {
+		case model.Upcoming, model.Ongoing, model.Completed:
+			filter.Statuses = append(filter.Statuses, model.EventStatus(status))
+		default:
+			return invalid("unknown status %q", status)
+		}
+	}
+	for _, value := range splitList(query.VenueID) {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || id == 0 {
+			return invalid("invalid venue_id %q", value)
+		}
+		filter.VenueIDs = append(filter.VenueIDs, uint(id))
+	}
+	filter.Cities = splitList(query.City)
+
+	if slugs := splitList(query.Category); len(slugs) > 0 {
+		categories, err := s.categoryRepo.FindAll()
+		if err != nil {
+			return repository.EventFilter{}, err
+		}
+		var ids []uint
+		for _, slug := range slugs {
+			id, ok := findCategorySlug(categories, slug)
+			if !ok {
+				return invalid("unknown category %q", slug)
+			}
+			ids = append(ids, id)
+		}
+		filter.CategoryIDs = categoryWithDescendants(categories, ids)
+	}
+	if slugs := splitList(query.Tag); len(slugs) > 0 {
+		tags, err := s.tagRepo.FindBySlugs(slugs)
+		if err != nil {
+			return repository.EventFilter{}, err
+		}
+		for _, slug := range slugs {
+			id, ok := findTagSlug(tags, slug)
+			if !ok {
+				return invalid("unknown tag %q", slug)
+			}
+			filter.TagIDs = append(filter.TagIDs, id)
+		}
+	}
+
+	if query.Near != "" {
+		point, err := parseGeoPoint(query.Near)
+		if err != nil {
+			return invalid("near: %v", err)
+		}
+		filter.Near = point
+		filter.RadiusKm = defaultNearRadiusKm
+		if query.RadiusKm != nil {
+			filter.RadiusKm = *query.RadiusKm
+		}
+	} else if query.RadiusKm != nil {
+		return invalid("radius_km requires near")
+	}
+
+	for i, key := range splitList(query.Sort) {
+		sort := repository.EventSort{Field: strings.TrimPrefix(key, "-"), Desc: strings.HasPrefix(key, "-")}
+		if sort.Field == "distance" {
+			if filter.Near == nil {
+				return invalid("sort by distance requires near")
+			}
+			if i > 0 {
+				return invalid("distance must be the first sort field")
+			}
+		} else if !repository.IsEventSortField(sort.Field) {
+			return invalid("unknown sort field %q", sort.Field)
+		}
+		filter.Sort = append(filter.Sort, sort)
+	}
+
+	return filter, nil
+}
+
+func findCategorySlug(categories []model.Category, slug string) (uint, bool) {
+	for _, category := range categories {
+		if category.Slug == slug {
+			return category.ID, true
+		}
+	}
+	return 0, false
+}
+
+func findTagSlug(tags []model.Tag, slug string) (uint, bool) {
+	for _, tag := range tags {
+		if tag.Slug == slug {
+			return tag.ID, true
+		}
+	}
+	return 0, false
+}
+
+// parseGeoPoint membaca koordinat "lat,lng" dalam derajat desimal
+func parseGeoPoint(value string) (*repository.GeoPoint, error) {
+	latValue, lngValue, ok := strings.Cut(value, ",")
+	if !ok {
+		return nil, errors.New("use lat,lng")
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latValue), 64)
+	if err != nil || math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return nil, errors.New("latitude must be between -90 and 90")
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(lngValue), 64)
+	if err != nil || math.IsNaN(lng) || lng < -180 || lng > 180 {
+		return nil, errors.New("longitude must be between -180 and 180")
+	}
+	return &repository.GeoPoint{Lat: lat, Lng: lng}, nil
+}
+
+// parseSearchTime membaca tanggal (YYYY-MM-DD, awal hari di loc) atau waktu lengkap
+func parseSearchTime(value string, loc *time.Location) (time.Time, bool, error) {
+	if day, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return day.UTC(), true, nil
+	}
+	t, err := utils.ParseTimeIn(value, loc)
+	return t, false, err
+}
+
+// splitList memecah daftar dipisah koma dan membuang nilai kosong
+func splitList(value string) []string {
+	var values []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+// applyTaxonomy mengganti kategori dan tag event jika dikirim di request;
+// field yang tidak dikirim (nil) membiarkan tautan lama
+func (s *eventService) applyTaxonomy(event *model.Event, req dto.EventRequest) error {
+	categories, tags, err := resolveTaxonomy(s.categoryRepo, s.tagRepo, req)
+	if err != nil {
+		return err
+	}
+	if categories != nil {
+		event.Categories = categories
+	}
+	if tags != nil {
+		event.Tags = tags
+	}
+	return nil
+}
+
+// resolveTaxonomy memuat kategori dan tag dari request event; hasilnya nil untuk field yang tidak dikirim
+func resolveTaxonomy(categoryRepo repository.CategoryRepository, tagRepo repository.TagRepository,
+	req dto.EventRequest) ([]model.Category, []model.Tag, error) {
+	var categories []model.Category
+	var tags []model.Tag
+	var err error
+	if req.CategoryIDs != nil {
+		if categories, err = resolveCategories(categoryRepo, req.CategoryIDs); err != nil {
+			return nil, nil, err
+		}
+	}
+	if req.Tags != nil {
+		if tags, err = resolveTags(tagRepo, req.Tags); err != nil {
+			return nil, nil, err
+		}
+	}
+	return categories, tags, nil
+}
+
+// applyVenue menghubungkan event ke venue dari request (atau melepasnya jika venue_id kosong).
+// Location kosong diisi dari venue; event yang pindah venue tanpa timezone di request
+// mengikuti zona waktu venue. Kapasitas dan jadwal dicek terhadap venue di repository.
+func (s *eventService) applyVenue(event *model.Event, req dto.EventRequest) error {
+	venue, err := findVenue(s.venueRepo, req.VenueID)
+	if err != nil {
+		return err
+	}
+	if venue == nil {
+		event.VenueID = nil
+		event.Venue = nil
+		return nil
+	}
+
+	if req.Timezone == "" && (event.VenueID == nil || *event.VenueID != venue.ID) {
+		event.Timezone = venue.Timezone
+	}
+	if event.Location == "" {
+		event.Location = venueLocation(venue)
+	}
+	event.VenueID = &venue.ID
+	event.Venue = venue
+	return nil
+}
+
+// applySchedule memvalidasi timezone dan jadwal dari request lalu menyalinnya ke event
+// dalam UTC; jika end_date_time kosong, EndsAt diisi StartsAt + duration
+func applySchedule(event *model.Event, req dto.EventRequest, duration time.Duration) error {
+	if req.Timezone != "" {
+		event.Timezone = req.Timezone
+	}
+	loc, err := time.LoadLocation(event.Timezone)
+	if err != nil {
+		return errors.New("invalid timezone " + event.Timezone)
+	}
+
+	start, err := utils.ParseTimeIn(req.DateTime, loc)
+	if err != nil {
+		return errors.New("date_time: " + err.Error())
+	}
+
+	end := start.Add(duration)
+	if req.EndDateTime != "" {
+		if end, err = utils.ParseTimeIn(req.EndDateTime, loc); err != nil {
+			return errors.New("end_date_time: " + err.Error())
+		}
+		if !end.After(start) {
+			return errors.New("end_date_time must be after date_time")
+		}
+	}
+
+	event.StartsAt = start
+	event.EndsAt = end
+	return nil
+}
+
+func mapEventToResponse(event *model.Event, available int) *dto.EventResponse {
+	ticketTypes := mapTicketTypesToResponse(event.TicketTypes)
+	for i := range ticketTypes {
+		// Sisa tier tetap dibatasi oleh sisa kapasitas event
+		if ticketTypes[i].Available > available {
+			ticketTypes[i].Available = available
+		}
+	}
+	loc := event.TimeLocation()
+
+	response := &dto.EventResponse{
+		ID:               event.ID,
+		Name:             event.Name,
+		Description:      event.Description,
+		Location:         event.Location,
+		DateTime:         event.StartsAt.UTC(),
+		EndDateTime:      event.EndsAt.UTC(),
+		DateTimeLocal:    utils.FormatLocal(event.StartsAt, loc),
+		EndDateTimeLocal: utils.FormatLocal(event.EndsAt, loc),
+		Timezone:         event.Timezone,
+		SeriesID:         event.SeriesID,
+		VenueID:          event.VenueID,
+		DistanceKm:       event.DistanceKm,
+		Capacity:         event.Capacity,
+		Sold:             event.Sold,
+		Held:             event.Held,
+		Offered:          event.Offered,
+		Available:        available,
+		Price:            event.Price,
+		Status:           string(event.Status),
+		StatusOverride:   event.StatusOverride,
+		StatusChangedAt:  event.StatusChangedAt,
+		HoldMinutes:      event.HoldMinutes,
+		TransfersAllowed: event.AllowsTransfers(),
+		Categories:       mapCategoriesToResponse(event.Categories),
+		Tags:             mapTagsToResponse(event.Tags),
+		TicketTypes:      ticketTypes,
+	}
+	if event.Venue != nil {
+		response.Venue = mapVenueToResponse(event.Venue)
 	}
+	return response
 }
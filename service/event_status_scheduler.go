@@ -0,0 +1,187 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"ticketing/model"
+	"ticketing/repository"
+)
+
+// eventStatusBatch membatasi jumlah event yang diproses dalam satu putaran
+const eventStatusBatch = 100
+
+// EventStatusHook dipanggil setelah status event berpindah. Hook hanya dijalankan oleh
+// instance yang memenangkan update bersyarat, jadi setiap transisi memicu hook sekali.
+type EventStatusHook func(event *model.Event, from, to model.EventStatus)
+
+// EventStatusScheduler memindahkan status event upcoming → ongoing → completed
+// berdasarkan StartsAt dan EndsAt. Event yang statusnya di-override admin dilewati.
+type EventStatusScheduler struct {
+	eventRepo       repository.EventRepository
+	interval        time.Duration
+	defaultDuration time.Duration
+	hooks           []EventStatusHook
+}
+
+func NewEventStatusScheduler(eventRepo repository.EventRepository, interval, defaultDuration time.Duration) *EventStatusScheduler {
+	return &EventStatusScheduler{
+		eventRepo:       eventRepo,
+		interval:        interval,
+		defaultDuration: defaultDuration,
+	}
+}
+
+// OnTransition mendaftarkan hook transisi; panggil sebelum Run
+func (s *EventStatusScheduler) OnTransition(hook EventStatusHook) {
+	s.hooks = append(s.hooks, hook)
+}
+
+// DefaultDuration adalah lama event jika admin tidak mengisi end_date_time
+func (s *EventStatusScheduler) DefaultDuration() time.Duration {
+	return s.defaultDuration
+}
+
+// Run menyesuaikan status event setiap interval; jalankan sebagai goroutine.
+func (s *EventStatusScheduler) Run() {
+	s.Sweep()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.Sweep()
+	}
+}
+
+// Sweep memproses semua event yang statusnya tertinggal dari jadwal saat ini
+func (s *EventStatusScheduler) Sweep() {
+	now := time.Now()
+	for {
+		events, err := s.eventRepo.FindStatusDue(now, eventStatusBatch)
+		if err != nil {
+			log.Printf("Failed to find events due for a status change: %v", err)
+			return
+		}
+
+		moved := 0
+		for i := range events {
+			to := scheduledStatus(&events[i], now)
+			if to == events[i].Status {
+				continue
+			}
+			ok, err := s.transition(&events[i], to, false)
+			if err != nil {
+				log.Printf("Failed to move event %d to %s: %v", events[i].ID, to, err)
+				continue
+			}
+			if ok {
+				moved++
+			}
+		}
+
+		// Berhenti jika batch tidak penuh, atau tidak ada yang bisa dipindahkan agar tidak berputar terus
+		if len(events) < eventStatusBatch || moved == 0 {
+			return
+		}
+	}
+}
+
+// Override memaksa status event dan menandainya agar tidak diubah scheduler
+func (s *EventStatusScheduler) Override(event *model.Event, to model.EventStatus) error {
+	if event.Status == to {
+		event.StatusOverride = true
+		return s.eventRepo.SetStatusOverride(event.ID, true)
+	}
+
+	ok, err := s.transition(event, to, true)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("event status changed concurrently, please retry")
+	}
+	return nil
+}
+
+// Resume mengembalikan event ke jadwal otomatis dan langsung menyesuaikan statusnya
+func (s *EventStatusScheduler) Resume(event *model.Event) error {
+	if err := s.eventRepo.SetStatusOverride(event.ID, false); err != nil {
+		return err
+	}
+	event.StatusOverride = false
+
+	to := scheduledStatus(event, time.Now())
+	if to == event.Status {
+		return nil
+	}
+	_, err := s.transition(event, to, false)
+	return err
+}
+
+// transition memindahkan event dari status saat ini ke to lewat update bersyarat,
+// lalu menjalankan hook jika instance ini yang berhasil memindahkannya
+func (s *EventStatusScheduler) transition(event *model.Event, to model.EventStatus, override bool) (bool, error) {
+	from := event.Status
+	ok, err := s.eventRepo.TransitionStatus(event.ID, from, to, override)
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	now := time.Now()
+	event.Status = to
+	event.StatusOverride = override
+	event.StatusChangedAt = &now
+	log.Printf("Event %d status changed from %s to %s", event.ID, from, to)
+
+	for _, hook := range s.hooks {
+		hook(event, from, to)
+	}
+	return true, nil
+}
+
+// scheduledStatus menghitung status event menurut jadwalnya pada waktu now
+func scheduledStatus(event *model.Event, now time.Time) model.EventStatus {
+	if now.Before(event.StartsAt) {
+		return model.Upcoming
+	}
+	if !event.EndsAt.IsZero() && !now.Before(event.EndsAt) {
+		return model.Completed
+	}
+	return model.Ongoing
+}
+
+// CloseSalesHook menutup penjualan saat event meninggalkan status upcoming: hold yang
+// belum dibayar dibatalkan dan antrean waitlist diakhiri. Pembelian baru sudah ditolak
+// oleh pengecekan status di ticket, order dan waitlist service.
+func CloseSalesHook(ticketRepo repository.TicketRepository, waitlistRepo repository.WaitlistRepository) EventStatusHook {
+	return func(event *model.Event, from, to model.EventStatus) {
+		if from != model.Upcoming {
+			return
+		}
+
+		for {
+			released, err := ticketRepo.ReleaseEventHolds(event.ID, holdSweepBatch)
+			if err != nil {
+				log.Printf("Failed to release holds for event %d: %v", event.ID, err)
+				break
+			}
+			for _, ticket := range released {
+				log.Printf("Released hold for ticket %d (event %d, qty %d): sales closed", ticket.ID, ticket.EventID, ticket.Qty)
+			}
+			if len(released) < holdSweepBatch {
+				break
+			}
+		}
+
+		closed, err := waitlistRepo.CloseEvent(event.ID)
+		if err != nil {
+			log.Printf("Failed to close waitlist for event %d: %v", event.ID, err)
+			return
+		}
+		if len(closed) > 0 {
+			log.Printf("Closed %d waitlist entr(ies) for event %d", len(closed), event.ID)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"ticketing/repository"
+)
+
+// holdSweepBatch membatasi jumlah tiket yang diproses dalam satu putaran
+const holdSweepBatch = 100
+
+// HoldSweeper membatalkan tiket yang tidak dibayar sampai ExpiresAt dan
+// mengembalikan kuotanya ke event, lalu meneruskan kuota itu ke antrean waitlist.
+type HoldSweeper struct {
+	ticketRepo      repository.TicketRepository
+	waitlistService WaitlistService
+	interval        time.Duration
+}
+
+func NewHoldSweeper(ticketRepo repository.TicketRepository, waitlistService WaitlistService, interval time.Duration) *HoldSweeper {
+	return &HoldSweeper{
+		ticketRepo:      ticketRepo,
+		waitlistService: waitlistService,
+		interval:        interval,
+	}
+}
+
+// Run memblokir dan menyapu hold yang kedaluwarsa setiap interval; jalankan sebagai goroutine.
+func (s *HoldSweeper) Run() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.Sweep()
+	}
+}
+
+// Sweep memproses semua hold dan tawaran waitlist yang sudah kedaluwarsa saat ini
+func (s *HoldSweeper) Sweep() {
+	s.expireHolds()
+	s.waitlistService.ExpireOffers()
+	s.waitlistService.ProcessQueues()
+}
+
+func (s *HoldSweeper) expireHolds() {
+	for {
+		expired, err := s.ticketRepo.ExpireHolds(time.Now(), holdSweepBatch)
+		if err != nil {
+			log.Printf("Failed to expire ticket holds: %v", err)
+			return
+		}
+
+		for _, ticket := range expired {
+			log.Printf("Expired hold for ticket %d (event %d, qty %d)", ticket.ID, ticket.EventID, ticket.Qty)
+		}
+
+		if len(expired) < holdSweepBatch {
+			return
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"ticketing/dto"
+	"ticketing/model"
+	"ticketing/repository"
+)
+
+type OrderService interface {
+	GetCart(userID uint) (*dto.OrderResponse, error)
+	AddToCart(userID uint, req dto.CartItemRequest) (*dto.OrderResponse, error)
+	RemoveFromCart(userID, itemID uint) (*dto.OrderResponse, error)
+	Checkout(userID uint) (*dto.OrderResponse, error)
+	GetUserOrders(userID uint, page, limit int) ([]dto.OrderResponse, *dto.Pagination, error)
+	GetOrderByID(userID, orderID uint) (*dto.OrderResponse, error)
+	PayOrder(userID, orderID uint) (*dto.OrderResponse, error)
+	CancelOrder(userID, orderID uint) (*dto.OrderResponse, error)
+}
+
+type orderService struct {
+	orderRepo       repository.OrderRepository
+	eventRepo       repository.EventRepository
+	seatRepo        repository.SeatRepository
+	paymentService  PaymentService
+	waitlistService WaitlistService
+}
+
+func NewOrderService(orderRepo repository.OrderRepository, eventRepo repository.EventRepository,
+	seatRepo repository.SeatRepository, paymentService PaymentService, waitlistService WaitlistService) OrderService {
+	return &orderService{
+		orderRepo:       orderRepo,
+		eventRepo:       eventRepo,
+		seatRepo:        seatRepo,
+		paymentService:  paymentService,
+		waitlistService: waitlistService,
+	}
+}
+
+func (s *orderService) GetCart(userID uint) (*dto.OrderResponse, error) {
+	cart, err := s.orderRepo.FindOrCreateCart(userID)
+	if err != nil {
+		return nil, err
+	}
+	return mapOrderToResponse(cart), nil
+}
+
+func (s *orderService) AddToCart(userID uint, req dto.CartItemRequest) (*dto.OrderResponse, error) {
+	cart, err := s.orderRepo.FindOrCreateCart(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := s.eventRepo.FindByID(req.EventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	// Gabungkan dengan baris yang sudah ada untuk event/tier yang sama
+	item := &model.OrderItem{OrderID: cart.ID, EventID: event.ID}
+	for i := range cart.Items {
+		existing := &cart.Items[i]
+		if existing.EventID == event.ID && sameID(existing.TicketTypeID, req.TicketTypeID) {
+			item = existing
+			break
+		}
+	}
+	item.Qty += req.Qty
+
+	ticketType, err := s.validateLine(event, req.TicketTypeID, item.Qty, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Jawaban baru menggantikan jawaban baris yang sama; jika tidak dikirim, jawaban lama dipakai
+	answers := req.Answers
+	if answers == nil {
+		answers = stringAnswers(item.Answers)
+	}
+	if item.Answers, err = validateAnswers(event.Questions, answers); err != nil {
+		return nil, err
+	}
+
+	item.UnitPrice = event.Price
+	if ticketType != nil {
+		item.TicketTypeID = &ticketType.ID
+		item.UnitPrice = ticketType.Price
+	}
+	item.LineTotal = item.UnitPrice * float64(item.Qty)
+
+	if err := s.orderRepo.SaveItem(item); err != nil {
+		return nil, err
+	}
+
+	return s.refreshCart(cart.ID)
+}
+
+func (s *orderService) RemoveFromCart(userID, itemID uint) (*dto.OrderResponse, error) {
+	cart, err := s.orderRepo.FindOrCreateCart(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.orderRepo.DeleteItem(cart.ID, itemID); err != nil {
+		return nil, errors.New("cart item not found")
+	}
+
+	return s.refreshCart(cart.ID)
+}
+
+func (s *orderService) Checkout(userID uint) (*dto.OrderResponse, error) {
+	cart, err := s.orderRepo.FindOrCreateCart(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cart.Items) == 0 {
+		return nil, errors.New("cart is empty")
+	}
+
+	now := time.Now()
+	var expiresAt time.Time
+	var tickets []*model.Ticket
+	for i := range cart.Items {
+		item := &cart.Items[i]
+
+		event, err := s.eventRepo.FindByID(item.EventID)
+		if err != nil {
+			return nil, errors.New("event not found")
+		}
+
+		var ticketTypeID uint
+		if item.TicketTypeID != nil {
+			ticketTypeID = *item.TicketTypeID
+		}
+		if _, err := s.validateLine(event, ticketTypeID, item.Qty, now); err != nil {
+			return nil, err
+		}
+
+		// Formulir bisa berubah setelah item masuk keranjang: validasi ulang saat checkout
+		answers, err := validateAnswers(event.Questions, stringAnswers(item.Answers))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", event.Name, err)
+		}
+
+		// Seluruh order memakai batas hold tersingkat di antara event-nya
+		lineExpiry := now.Add(time.Duration(event.HoldMinutes) * time.Minute)
+		if expiresAt.IsZero() || lineExpiry.Before(expiresAt) {
+			expiresAt = lineExpiry
+		}
+
+		tickets = append(tickets, &model.Ticket{
+			EventID:      item.EventID,
+			TicketTypeID: item.TicketTypeID,
+			UserID:       userID,
+			Status:       model.Available,
+			Qty:          item.Qty,
+			BookedAt:     now,
+			Answers:      buildTicketAnswers(event.Questions, answers),
+		})
+	}
+
+	if err := s.orderRepo.Checkout(cart, tickets, expiresAt); err != nil {
+		return nil, err
+	}
+
+	order, err := s.orderRepo.FindByID(cart.ID)
+	if err != nil {
+		return nil, err
+	}
+	return mapOrderToResponse(order), nil
+}
+
+func (s *orderService) GetUserOrders(userID uint, page, limit int) ([]dto.OrderResponse, *dto.Pagination, error) {
+	orders, total, err := s.orderRepo.FindAll(page, limit, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var responses []dto.OrderResponse
+	for i := range orders {
+		responses = append(responses, *mapOrderToResponse(&orders[i]))
+	}
+
+	pagination := &dto.Pagination{
+		Page:       page,
+		Limit:      limit,
+		TotalItems: total,
+		TotalPages: (total + int64(limit) - 1) / int64(limit),
+	}
+
+	return responses, pagination, nil
+}
+
+func (s *orderService) GetOrderByID(userID, orderID uint) (*dto.OrderResponse, error) {
+	order, err := s.findUserOrder(userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return mapOrderToResponse(order), nil
+}
+
+func (s *orderService) PayOrder(userID, orderID uint) (*dto.OrderResponse, error) {
+	order, err := s.findUserOrder(userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != model.OrderPending {
+		return nil, repository.ErrOrderNotPayable
+	}
+	if order.ExpiresAt != nil && time.Now().After(*order.ExpiresAt) {
+		return nil, repository.ErrHoldExpired
+	}
+
+	payment, err := s.paymentService.PayOrder(order)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.GetOrderByID(userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	response.Payment = mapPaymentToResponse(payment)
+	return response, nil
+}
+
+func (s *orderService) CancelOrder(userID, orderID uint) (*dto.OrderResponse, error) {
+	order, err := s.findUserOrder(userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.orderRepo.UpdatePaymentStatus(order.ID, model.Cancel, model.Cancelled); err != nil {
+		return nil, err
+	}
+
+	released := make(map[uint]bool)
+	for _, item := range order.Items {
+		if !released[item.EventID] {
+			released[item.EventID] = true
+			s.waitlistService.Release(item.EventID)
+		}
+	}
+
+	return s.GetOrderByID(userID, orderID)
+}
+
+func (s *orderService) findUserOrder(userID, orderID uint) (*model.Order, error) {
+	order, err := s.orderRepo.FindByID(orderID)
+	if err != nil || order.UserID != userID || order.Status == model.OrderCart {
+		return nil, errors.New("order not found")
+	}
+	return order, nil
+}
+
+// validateLine memastikan satu baris keranjang bisa dibeli: event masih upcoming,
+// tidak memakai reserved seating, dan sesuai aturan tier jika ada.
+func (s *orderService) validateLine(event *model.Event, ticketTypeID uint, qty int, now time.Time) (*model.TicketType, error) {
+	if event.Status != model.Upcoming {
+		return nil, errors.New("event is not available for ticket purchase")
+	}
+
+	seatCount, err := s.seatRepo.CountByEvent(event.ID)
+	if err != nil {
+		return nil, err
+	}
+	if seatCount > 0 {
+		return nil, errors.New("reserved seating events must be purchased with seat selection")
+	}
+
+	if len(event.TicketTypes) == 0 && ticketTypeID == 0 {
+		return nil, nil
+	}
+
+	ticketType := findTicketType(event, ticketTypeID)
+	if ticketType == nil {
+		return nil, errors.New("a valid ticket_type_id is required for this event")
+	}
+	if err := validateTicketTypeOrder(ticketType, qty, now); err != nil {
+		return nil, err
+	}
+	return ticketType, nil
+}
+
+func (s *orderService) refreshCart(cartID uint) (*dto.OrderResponse, error) {
+	cart, err := s.orderRepo.FindByID(cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0.0
+	for _, item := range cart.Items {
+		total += item.LineTotal
+	}
+	if err := s.orderRepo.UpdateTotal(cart.ID, total); err != nil {
+		return nil, err
+	}
+	cart.Total = total
+
+	return mapOrderToResponse(cart), nil
+}
+
+func stringAnswers(answers map[string]string) map[string]interface{} {
+	converted := make(map[string]interface{}, len(answers))
+	for key, value := range answers {
+		converted[key] = value
+	}
+	return converted
+}
+
+func sameID(id *uint, other uint) bool {
+	if id == nil {
+		return other == 0
+	}
+	return *id == other
+}
+
+func mapOrderToResponse(order *model.Order) *dto.OrderResponse {
+	response := &dto.OrderResponse{
+		ID:        order.ID,
+		Status:    string(order.Status),
+		Total:     order.Total,
+		ExpiresAt: order.ExpiresAt,
+		SeriesID:  order.SeriesID,
+		Items:     []dto.OrderItemResponse{},
+	}
+
+	for _, item := range order.Items {
+		ticketTypeName := ""
+		if item.TicketType != nil {
+			ticketTypeName = item.TicketType.Name
+		}
+		response.Items = append(response.Items, dto.OrderItemResponse{
+			ID:           item.ID,
+			EventID:      item.EventID,
+			EventName:    item.Event.Name,
+			TicketTypeID: item.TicketTypeID,
+			TicketType:   ticketTypeName,
+			Qty:          item.Qty,
+			UnitPrice:    item.UnitPrice,
+			LineTotal:    item.LineTotal,
+			Answers:      item.Answers,
+		})
+		response.TotalQty += item.Qty
+	}
+
+	for i := range order.Tickets {
+		ticket := &order.Tickets[i]
+		response.Tickets = append(response.Tickets, *mapTicketToResponse(ticket, &ticket.Event))
+	}
+
+	return response
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"ticketing/dto"
+	"ticketing/model"
+	"ticketing/repository"
+)
+
+const (
+	maxPassFields     = 20
+	maxPassFieldKey   = 50
+	maxPassFieldValue = 500
+)
+
+type PassService interface {
+	GetPasses(userID uint, isAdmin bool, ticketID uint) ([]dto.AttendeePassResponse, error)
+	UpdatePass(userID, ticketID, passID uint, req dto.AttendeePassRequest) (*dto.AttendeePassResponse, error)
+	GetPassCredential(userID uint, isAdmin bool, ticketID, passID uint) (*dto.CredentialResponse, error)
+}
+
+type passService struct {
+	passRepo          repository.PassRepository
+	ticketRepo        repository.TicketRepository
+	credentialService CredentialService
+}
+
+func NewPassService(passRepo repository.PassRepository, ticketRepo repository.TicketRepository,
+	credentialService CredentialService) PassService {
+	return &passService{
+		passRepo:          passRepo,
+		ticketRepo:        ticketRepo,
+		credentialService: credentialService,
+	}
+}
+
+func (s *passService) GetPasses(userID uint, isAdmin bool, ticketID uint) ([]dto.AttendeePassResponse, error) {
+	ticket, err := s.findTicket(userID, isAdmin, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	passes, err := s.passRepo.FindByTicket(ticket.ID)
+	if err != nil {
+		return nil, err
+	}
+	return mapPassesToResponse(passes), nil
+}
+
+// UpdatePass mengisi data peserta setelah pembelian. Jika pass diberikan ke orang
+// lain (email berubah), kredensial pass yang lama dicabut.
+func (s *passService) UpdatePass(userID, ticketID, passID uint, req dto.AttendeePassRequest) (*dto.AttendeePassResponse, error) {
+	ticket, err := s.findTicket(userID, false, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.Status == model.Cancelled {
+		return nil, errors.New("cannot update passes of a cancelled ticket")
+	}
+	if err := validatePassFields(req.Fields); err != nil {
+		return nil, err
+	}
+
+	pass, err := s.passRepo.FindByID(ticket.ID, passID)
+	if err != nil {
+		return nil, errors.New("attendee pass not found")
+	}
+	if pass.CheckedInAt != nil {
+		return nil, errors.New("cannot update a pass that has already been checked in")
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	reassigned := pass.Email != "" && !strings.EqualFold(pass.Email, email)
+
+	pass.Name = strings.TrimSpace(req.Name)
+	pass.Email = email
+	pass.Fields = req.Fields
+	if err := s.passRepo.Update(pass); err != nil {
+		return nil, err
+	}
+
+	if reassigned {
+		s.credentialService.RevokePass(pass.ID, "reassigned")
+	}
+
+	return mapPassToResponse(pass), nil
+}
+
+func (s *passService) GetPassCredential(userID uint, isAdmin bool, ticketID, passID uint) (*dto.CredentialResponse, error) {
+	ticket, err := s.findTicket(userID, isAdmin, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.Status != model.Booked {
+		return nil, errors.New("credentials are only issued for booked tickets")
+	}
+
+	pass, err := s.passRepo.FindByID(ticket.ID, passID)
+	if err != nil {
+		return nil, errors.New("attendee pass not found")
+	}
+	return s.credentialService.GetPassCredential(ticket, pass)
+}
+
+// findTicket memuat tiket milik user (atau tiket apa pun untuk admin) dan
+// memastikan pass-nya sudah ada untuk tiket yang dibeli sebelum fitur pass.
+func (s *passService) findTicket(userID uint, isAdmin bool, ticketID uint) (*model.Ticket, error) {
+	ticket, err := s.ticketRepo.FindByID(ticketID)
+	if err != nil || (!isAdmin && ticket.UserID != userID) {
+		return nil, errors.New("ticket not found")
+	}
+	if len(ticket.Passes) == 0 {
+		if err := s.passRepo.EnsurePasses(ticket); err != nil {
+			return nil, err
+		}
+	}
+	return ticket, nil
+}
+
+func validatePassFields(fields map[string]string) error {
+	if len(fields) > maxPassFields {
+		return errors.New("too many custom fields")
+	}
+	for key, value := range fields {
+		if strings.TrimSpace(key) == "" || len(key) > maxPassFieldKey {
+			return errors.New("custom field names must be 1-50 characters")
+		}
+		if len(value) > maxPassFieldValue {
+			return errors.New("custom field values must be at most 500 characters")
+		}
+	}
+	return nil
+}
+
+func mapPassToResponse(pass *model.AttendeePass) *dto.AttendeePassResponse {
+	return &dto.AttendeePassResponse{
+		ID:          pass.ID,
+		Seq:         pass.Seq,
+		Name:        pass.Name,
+		Email:       pass.Email,
+		Fields:      pass.Fields,
+		CheckedIn:   pass.CheckedInAt != nil,
+		CheckedInAt: pass.CheckedInAt,
+		Gate:        pass.CheckInGate,
+	}
+}
+
+func mapPassesToResponse(passes []model.AttendeePass) []dto.AttendeePassResponse {
+	responses := []dto.AttendeePassResponse{}
+	for i := range passes {
+		responses = append(responses, *mapPassToResponse(&passes[i]))
+	}
+	return responses
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"ticketing/dto"
+	"ticketing/model"
+	"ticketing/payment"
+	"ticketing/repository"
+)
+
+// ErrPaymentMismatch dikembalikan ketika event sukses dari provider tidak sesuai nominal atau mata uang Payment
+var ErrPaymentMismatch = errors.New("payment event does not match the recorded amount or currency")
+
+type PaymentService interface {
+	PayTicket(ticket *model.Ticket) (*model.Payment, error)
+	PayOrder(order *model.Order) (*model.Payment, error)
+	HandleProviderEvent(provider string, event payment.WebhookEvent) error
+	Refund(p *model.Payment, amount float64) (string, error)
+}
+
+type paymentService struct {
+	provider    payment.Provider
+	currency    string
+	paymentRepo repository.PaymentRepository
+	ticketRepo  repository.TicketRepository
+	orderRepo   repository.OrderRepository
+
+	credentialService CredentialService
+}
+
+func NewPaymentService(provider payment.Provider, currency string, paymentRepo repository.PaymentRepository,
+	ticketRepo repository.TicketRepository, orderRepo repository.OrderRepository, credentialService CredentialService) PaymentService {
+	return &paymentService{
+		provider:          provider,
+		currency:          currency,
+		paymentRepo:       paymentRepo,
+		ticketRepo:        ticketRepo,
+		orderRepo:         orderRepo,
+		credentialService: credentialService,
+	}
+}
+
+func (s *paymentService) PayTicket(ticket *model.Ticket) (*model.Payment, error) {
+	return s.charge(&model.Payment{
+		UserID:   ticket.UserID,
+		TicketID: &ticket.ID,
+		Amount:   ticket.SubTotal,
+	}, fmt.Sprintf("ticket:%d", ticket.ID))
+}
+
+func (s *paymentService) PayOrder(order *model.Order) (*model.Payment, error) {
+	return s.charge(&model.Payment{
+		UserID:  order.UserID,
+		OrderID: &order.ID,
+		Amount:  order.Total,
+	}, fmt.Sprintf("order:%d", order.ID))
+}
+
+// charge membuat intent di provider, mencatat Payment, lalu mencoba capture.
+// Tiket baru berstatus Booked jika provider melaporkan pembayaran berhasil.
+func (s *paymentService) charge(p *model.Payment, reference string) (*model.Payment, error) {
+	intent, err := s.provider.CreateIntent(payment.Intent{
+		Reference: reference,
+		Amount:    p.Amount,
+		Currency:  s.currency,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.Provider = s.provider.Name()
+	p.ProviderRef = intent.ProviderRef
+	p.Currency = s.currency
+	p.Status = model.PaymentAttemptPending
+	if err := s.paymentRepo.Create(p); err != nil {
+		return nil, err
+	}
+
+	status, err := s.provider.Capture(p.ProviderRef)
+	if err != nil {
+		return p, err
+	}
+
+	if err := s.settle(p, status); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// Refund mengembalikan sebagian atau seluruh dana pembayaran lewat provider
+func (s *paymentService) Refund(p *model.Payment, amount float64) (string, error) {
+	if p.Provider != s.provider.Name() {
+		return "", fmt.Errorf("payment provider %s is not available for refunds", p.Provider)
+	}
+	return s.provider.Refund(p.ProviderRef, amount)
+}
+
+func (s *paymentService) HandleProviderEvent(provider string, event payment.WebhookEvent) error {
+	p, err := s.paymentRepo.FindByProviderRef(provider, event.ProviderRef)
+	if err != nil {
+		return errors.New("payment not found for provider reference")
+	}
+
+	// Pembayaran hanya dianggap lunas jika nominal dan mata uangnya sama dengan yang ditagihkan
+	if event.Status == payment.StatusSucceeded {
+		if math.Round(event.Amount*100) != math.Round(p.Amount*100) || !strings.EqualFold(event.Currency, p.Currency) {
+			return fmt.Errorf("%w: got %.2f %s, expected %.2f %s",
+				ErrPaymentMismatch, event.Amount, event.Currency, p.Amount, p.Currency)
+		}
+	}
+	return s.settle(p, event.Status)
+}
+
+// settle menerapkan status dari provider ke Payment dan tiket/order terkait.
+// Hanya transisi pertama dari pending yang diproses sehingga notifikasi ganda aman.
+func (s *paymentService) settle(p *model.Payment, status payment.Status) error {
+	switch status {
+	case payment.StatusSucceeded:
+		now := time.Now()
+		claimed, err := s.paymentRepo.Settle(p.ID, model.PaymentAttemptSucceeded, map[string]interface{}{"captured_at": now})
+		if err != nil || !claimed {
+			return err
+		}
+		p.Status = model.PaymentAttemptSucceeded
+		p.CapturedAt = &now
+
+		if err := s.book(p); err != nil {
+			// Uang sudah tertarik tapi tiket tidak bisa dibooking (mis. hold kedaluwarsa): kembalikan
+			if _, refundErr := s.provider.Refund(p.ProviderRef, p.Amount); refundErr != nil {
+				log.Printf("Failed to refund payment %d after booking error: %v", p.ID, refundErr)
+				return err
+			}
+			p.Status = model.PaymentAttemptRefunded
+			if updateErr := s.paymentRepo.UpdateStatus(p.ID, p.Status, "booking failed: "+err.Error()); updateErr != nil {
+				log.Printf("Failed to mark payment %d refunded: %v", p.ID, updateErr)
+			}
+			return err
+		}
+
+		// Tiket sudah booked: terbitkan kredensial untuk scanner gerbang
+		s.credentialService.IssueForPayment(p)
+	case payment.StatusFailed:
+		claimed, err := s.paymentRepo.Settle(p.ID, model.PaymentAttemptFailed, map[string]interface{}{"failure_reason": "declined by provider"})
+		if err != nil || !claimed {
+			return err
+		}
+		p.Status = model.PaymentAttemptFailed
+		p.FailureReason = "declined by provider"
+
+		// Pembayaran ditolak: batalkan tiket/order agar kuotanya kembali
+		err = s.updateTarget(p, model.Cancel, model.Cancelled)
+		if errors.Is(err, repository.ErrInvalidTransition) || errors.Is(err, repository.ErrOrderNotPayable) {
+			return nil // sudah dibatalkan lewat jalur lain (mis. hold kedaluwarsa)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *paymentService) book(p *model.Payment) error {
+	return s.updateTarget(p, model.Success, model.Booked)
+}
+
+// updateTarget memakai jalur UpdatePaymentStatus yang sama dengan alur tiket/order biasa
+func (s *paymentService) updateTarget(p *model.Payment, paymentStatus model.PaymentStatus, ticketStatus model.TicketStatus) error {
+	if p.OrderID != nil {
+		return s.orderRepo.UpdatePaymentStatus(*p.OrderID, paymentStatus, ticketStatus)
+	}
+	if p.TicketID != nil {
+		return s.ticketRepo.UpdatePaymentStatus(*p.TicketID, paymentStatus, ticketStatus)
+	}
+	return errors.New("payment is not linked to a ticket or order")
+}
+
+func mapPaymentToResponse(p *model.Payment) *dto.PaymentResponse {
+	if p == nil {
+		return nil
+	}
+	return &dto.PaymentResponse{
+		ID:            p.ID,
+		Provider:      p.Provider,
+		ProviderRef:   p.ProviderRef,
+		Amount:        p.Amount,
+		Currency:      p.Currency,
+		Status:        string(p.Status),
+		FailureReason: p.FailureReason,
+		CapturedAt:    p.CapturedAt,
+	}
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ticketing/dto"
+	"ticketing/model"
+	"ticketing/repository"
+)
+
+type PromoCodeService interface {
+	CreatePromoCode(req dto.PromoCodeRequest) (*dto.PromoCodeResponse, error)
+	GetPromoCodes() ([]dto.PromoCodeResponse, error)
+	GetPromoCodeByID(id uint) (*dto.PromoCodeResponse, error)
+	UpdatePromoCode(id uint, req dto.PromoCodeRequest) (*dto.PromoCodeResponse, error)
+	DeletePromoCode(id uint) error
+}
+
+type promoCodeService struct {
+	promoRepo repository.PromoCodeRepository
+	eventRepo repository.EventRepository
+}
+
+func NewPromoCodeService(promoRepo repository.PromoCodeRepository, eventRepo repository.EventRepository) PromoCodeService {
+	return &promoCodeService{
+		promoRepo: promoRepo,
+		eventRepo: eventRepo,
+	}
+}
+
+func (s *promoCodeService) CreatePromoCode(req dto.PromoCodeRequest) (*dto.PromoCodeResponse, error) {
+	promo := &model.PromoCode{}
+	if err := s.applyPromoCodeRequest(promo, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCodeAvailable(promo); err != nil {
+		return nil, err
+	}
+
+	if err := s.promoRepo.Create(promo); err != nil {
+		return nil, err
+	}
+
+	return mapPromoCodeToResponse(promo), nil
+}
+
+func (s *promoCodeService) GetPromoCodes() ([]dto.PromoCodeResponse, error) {
+	promos, err := s.promoRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []dto.PromoCodeResponse
+	for i := range promos {
+		responses = append(responses, *mapPromoCodeToResponse(&promos[i]))
+	}
+	return responses, nil
+}
+
+func (s *promoCodeService) GetPromoCodeByID(id uint) (*dto.PromoCodeResponse, error) {
+	promo, err := s.promoRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("promo code not found")
+	}
+	return mapPromoCodeToResponse(promo), nil
+}
+
+func (s *promoCodeService) UpdatePromoCode(id uint, req dto.PromoCodeRequest) (*dto.PromoCodeResponse, error) {
+	promo, err := s.promoRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("promo code not found")
+	}
+
+	if err := s.applyPromoCodeRequest(promo, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCodeAvailable(promo); err != nil {
+		return nil, err
+	}
+
+	if err := s.promoRepo.Update(promo); err != nil {
+		return nil, err
+	}
+
+	return mapPromoCodeToResponse(promo), nil
+}
+
+func (s *promoCodeService) DeletePromoCode(id uint) error {
+	if _, err := s.promoRepo.FindByID(id); err != nil {
+		return errors.New("promo code not found")
+	}
+	return s.promoRepo.Delete(id)
+}
+
+// applyPromoCodeRequest memvalidasi request dan mengisi restriksi event/tier
+func (s *promoCodeService) applyPromoCodeRequest(promo *model.PromoCode, req dto.PromoCodeRequest) error {
+	if req.DiscountType == string(model.PercentDiscount) && req.Value > 100 {
+		return errors.New("percentage discount cannot exceed 100")
+	}
+	if req.ValidFrom != nil && req.ValidUntil != nil && !req.ValidUntil.After(*req.ValidFrom) {
+		return errors.New("valid_until must be after valid_from")
+	}
+
+	var events []model.Event
+	for _, eventID := range uniqueIDs(req.EventIDs) {
+		event, err := s.eventRepo.FindByID(eventID)
+		if err != nil {
+			return errors.New("event not found")
+		}
+		events = append(events, *event)
+	}
+	promo.Events, promo.TicketTypes = nil, nil
+	for _, ticketTypeID := range uniqueIDs(req.TicketTypeIDs) {
+		ticketType := findTicketTypeInEvents(events, ticketTypeID)
+		if ticketType == nil {
+			return errors.New("ticket_type_ids must belong to the selected event_ids")
+		}
+		promo.TicketTypes = append(promo.TicketTypes, model.TicketType{Model: ticketType.Model})
+	}
+	for _, event := range events {
+		promo.Events = append(promo.Events, model.Event{Model: event.Model})
+	}
+
+	promo.Code = strings.ToUpper(strings.TrimSpace(req.Code))
+	promo.DiscountType = model.DiscountType(req.DiscountType)
+	promo.Value = req.Value
+	promo.ValidFrom = req.ValidFrom
+	promo.ValidUntil = req.ValidUntil
+	promo.MaxRedemptions = req.MaxRedemptions
+	promo.MaxPerUser = req.MaxPerUser
+	promo.MinQty = req.MinQty
+	if promo.MinQty == 0 {
+		promo.MinQty = 1
+	}
+	if req.Active != nil {
+		promo.Active = req.Active
+	}
+	return nil
+}
+
+// checkCodeAvailable menolak kode yang sudah dipakai promo lain, termasuk promo yang sudah
+// dihapus karena unique index kolom code tetap berlaku untuk baris soft delete
+func (s *promoCodeService) checkCodeAvailable(promo *model.PromoCode) error {
+	existing, err := s.promoRepo.FindByCodeWithDeleted(promo.Code)
+	if err != nil || existing.ID == promo.ID {
+		return nil
+	}
+	if existing.DeletedAt.Valid {
+		return fmt.Errorf("promo code %q was used by a deleted promo code, choose a different code", promo.Code)
+	}
+	return fmt.Errorf("promo code %q already exists", promo.Code)
+}
+
+func findTicketTypeInEvents(events []model.Event, id uint) *model.TicketType {
+	for i := range events {
+		if ticketType := findTicketType(&events[i], id); ticketType != nil {
+			return ticketType
+		}
+	}
+	return nil
+}
+
+// validatePromoCode mengecek restriksi kode promo terhadap pembelian.
+// Batas pemakaian dicek ulang secara atomik di repository.
+func validatePromoCode(promo *model.PromoCode, eventID uint, ticketTypeID *uint, qty int, now time.Time) error {
+	if !promo.ValidAt(now) {
+		return errors.New("promo code is not valid at this time")
+	}
+	if qty < promo.MinQty {
+		return errors.New("quantity is below the minimum required for this promo code")
+	}
+
+	if len(promo.Events) > 0 {
+		allowed := false
+		for _, event := range promo.Events {
+			allowed = allowed || event.ID == eventID
+		}
+		if !allowed {
+			return errors.New("promo code does not apply to this event")
+		}
+	}
+
+	if len(promo.TicketTypes) > 0 {
+		allowed := false
+		for _, ticketType := range promo.TicketTypes {
+			allowed = allowed || (ticketTypeID != nil && ticketType.ID == *ticketTypeID)
+		}
+		if !allowed {
+			return errors.New("promo code does not apply to this ticket type")
+		}
+	}
+
+	return nil
+}
+
+func mapPromoCodeToResponse(promo *model.PromoCode) *dto.PromoCodeResponse {
+	response := &dto.PromoCodeResponse{
+		ID:             promo.ID,
+		Code:           promo.Code,
+		DiscountType:   string(promo.DiscountType),
+		Value:          promo.Value,
+		ValidFrom:      promo.ValidFrom,
+		ValidUntil:     promo.ValidUntil,
+		MaxRedemptions: promo.MaxRedemptions,
+		MaxPerUser:     promo.MaxPerUser,
+		Redeemed:       promo.Redeemed,
+		MinQty:         promo.MinQty,
+		Active:         promo.IsActive(),
+		EventIDs:       []uint{},
+		TicketTypeIDs:  []uint{},
+	}
+	for _, event := range promo.Events {
+		response.EventIDs = append(response.EventIDs, event.ID)
+	}
+	for _, ticketType := range promo.TicketTypes {
+		response.TicketTypeIDs = append(response.TicketTypeIDs, ticketType.ID)
+	}
+	return response
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"ticketing/model"
+	"ticketing/repository"
+
+	"gorm.io/gorm"
+)
+
+// fakePromoRepo menyimpan satu promo; FindByCodeWithDeleted ikut mengembalikan promo yang sudah dihapus
+type fakePromoRepo struct {
+	repository.PromoCodeRepository
+	promo *model.PromoCode
+}
+
+func (r *fakePromoRepo) FindByCodeWithDeleted(code string) (*model.PromoCode, error) {
+	if r.promo == nil || r.promo.Code != code {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.promo, nil
+}
+
+func TestCheckCodeAvailable(t *testing.T) {
+	deletedAt := gorm.DeletedAt{Time: time.Now(), Valid: true}
+	tests := []struct {
+		name     string
+		existing *model.PromoCode
+		promo    model.PromoCode
+		wantErr  string
+	}{
+		{name: "unused code", promo: model.PromoCode{Code: "NEW"}},
+		{name: "active code", existing: &model.PromoCode{Model: gorm.Model{ID: 1}, Code: "SALE"},
+			promo: model.PromoCode{Code: "SALE"}, wantErr: `promo code "SALE" already exists`},
+		{name: "deleted code", existing: &model.PromoCode{Model: gorm.Model{ID: 1, DeletedAt: deletedAt}, Code: "SALE"},
+			promo: model.PromoCode{Code: "SALE"}, wantErr: `promo code "SALE" was used by a deleted promo code, choose a different code`},
+		{name: "same promo", existing: &model.PromoCode{Model: gorm.Model{ID: 1}, Code: "SALE"},
+			promo: model.PromoCode{Model: gorm.Model{ID: 1}, Code: "SALE"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &promoCodeService{promoRepo: &fakePromoRepo{promo: tt.existing}}
+
+			err := svc.checkCodeAvailable(&tt.promo)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("err = %v, want nil", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"ticketing/dto"
+	"ticketing/model"
+	"ticketing/repository"
+)
+
+var questionKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+type QuestionService interface {
+	GetForm(eventID uint) (*dto.RegistrationFormResponse, error)
+	SetForm(eventID uint, req dto.RegistrationFormRequest) (*dto.RegistrationFormResponse, error)
+}
+
+type questionService struct {
+	questionRepo repository.QuestionRepository
+	eventRepo    repository.EventRepository
+}
+
+func NewQuestionService(questionRepo repository.QuestionRepository, eventRepo repository.EventRepository) QuestionService {
+	return &questionService{
+		questionRepo: questionRepo,
+		eventRepo:    eventRepo,
+	}
+}
+
+func (s *questionService) GetForm(eventID uint) (*dto.RegistrationFormResponse, error) {
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	questions, err := s.questionRepo.FindByEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+	return mapFormToResponse(eventID, questions), nil
+}
+
+func (s *questionService) SetForm(eventID uint, req dto.RegistrationFormRequest) (*dto.RegistrationFormResponse, error) {
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	seen := make(map[string]bool, len(req.Questions))
+	questions := make([]model.RegistrationQuestion, 0, len(req.Questions))
+	for i, q := range req.Questions {
+		key := strings.TrimSpace(q.Key)
+		if !questionKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("question key %q must be lowercase letters, digits or underscores", key)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate question key %q", key)
+		}
+		seen[key] = true
+
+		question := model.RegistrationQuestion{
+			Key:       key,
+			Label:     strings.TrimSpace(q.Label),
+			Type:      model.QuestionType(q.Type),
+			Required:  q.Required,
+			MinLength: q.MinLength,
+			MaxLength: q.MaxLength,
+			Min:       q.Min,
+			Max:       q.Max,
+			Pattern:   q.Pattern,
+			Position:  i + 1,
+		}
+		if err := validateQuestion(&question, q.Options); err != nil {
+			return nil, fmt.Errorf("question %q: %w", key, err)
+		}
+		questions = append(questions, question)
+	}
+
+	if err := s.questionRepo.Replace(eventID, questions); err != nil {
+		return nil, err
+	}
+	return s.GetForm(eventID)
+}
+
+// validateQuestion memastikan aturan validasi cocok dengan tipe pertanyaan
+func validateQuestion(q *model.RegistrationQuestion, options []string) error {
+	switch q.Type {
+	case model.QuestionText:
+		if q.MinLength != nil && q.MaxLength != nil && *q.MinLength > *q.MaxLength {
+			return errors.New("min_length cannot exceed max_length")
+		}
+		if q.Pattern != "" {
+			if _, err := regexp.Compile(q.Pattern); err != nil {
+				return errors.New("pattern is not a valid regular expression")
+			}
+		}
+	case model.QuestionNumber:
+		if q.Min != nil && q.Max != nil && *q.Min > *q.Max {
+			return errors.New("min cannot exceed max")
+		}
+	case model.QuestionSelect:
+		seen := make(map[string]bool, len(options))
+		for _, option := range options {
+			option = strings.TrimSpace(option)
+			if option == "" || seen[option] {
+				return errors.New("options must be unique and non-empty")
+			}
+			seen[option] = true
+			q.Options = append(q.Options, option)
+		}
+		if len(q.Options) == 0 {
+			return errors.New("select questions need at least one option")
+		}
+	}
+
+	// Aturan yang tidak berlaku untuk tipe ini diabaikan
+	if q.Type != model.QuestionText {
+		q.MinLength, q.MaxLength, q.Pattern = nil, nil, ""
+	}
+	if q.Type != model.QuestionNumber {
+		q.Min, q.Max = nil, nil
+	}
+	return nil
+}
+
+// validateAnswers memeriksa jawaban terhadap formulir event dan mengembalikan
+// jawaban yang sudah dinormalisasi per key. Jawaban boleh dikirim sebagai tipe
+// JSON aslinya atau sebagai string (mis. "true", "42").
+func validateAnswers(questions []model.RegistrationQuestion, answers map[string]interface{}) (map[string]string, error) {
+	known := make(map[string]bool, len(questions))
+	for _, q := range questions {
+		known[q.Key] = true
+	}
+	for key := range answers {
+		if !known[key] {
+			return nil, fmt.Errorf("unknown registration question %q", key)
+		}
+	}
+
+	normalized := make(map[string]string)
+	for _, q := range questions {
+		raw, ok := answers[q.Key]
+		if !ok || raw == nil || raw == "" {
+			if q.Required {
+				return nil, fmt.Errorf("%s is required", q.Label)
+			}
+			continue
+		}
+
+		value, err := normalizeAnswer(&q, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", q.Label, err)
+		}
+		if q.Type == model.QuestionCheckbox && value == "false" && q.Required {
+			return nil, fmt.Errorf("%s must be checked", q.Label)
+		}
+		normalized[q.Key] = value
+	}
+	return normalized, nil
+}
+
+func normalizeAnswer(q *model.RegistrationQuestion, raw interface{}) (string, error) {
+	switch q.Type {
+	case model.QuestionText:
+		text, ok := raw.(string)
+		if !ok {
+			return "", errors.New("must be text")
+		}
+		text = strings.TrimSpace(text)
+		length := len([]rune(text))
+		if q.MinLength != nil && length < *q.MinLength {
+			return "", fmt.Errorf("must be at least %d characters", *q.MinLength)
+		}
+		if q.MaxLength != nil && length > *q.MaxLength {
+			return "", fmt.Errorf("must be at most %d characters", *q.MaxLength)
+		}
+		if q.Pattern != "" {
+			if re, err := regexp.Compile(q.Pattern); err == nil && !re.MatchString(text) {
+				return "", errors.New("has an invalid format")
+			}
+		}
+		return text, nil
+
+	case model.QuestionNumber:
+		var number float64
+		switch v := raw.(type) {
+		case float64:
+			number = v
+		case string:
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return "", errors.New("must be a number")
+			}
+			number = parsed
+		default:
+			return "", errors.New("must be a number")
+		}
+		if q.Min != nil && number < *q.Min {
+			return "", fmt.Errorf("must be at least %v", *q.Min)
+		}
+		if q.Max != nil && number > *q.Max {
+			return "", fmt.Errorf("must be at most %v", *q.Max)
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+
+	case model.QuestionSelect:
+		option, ok := raw.(string)
+		if !ok {
+			return "", errors.New("must be one of the options")
+		}
+		for _, o := range q.Options {
+			if o == option {
+				return option, nil
+			}
+		}
+		return "", errors.New("must be one of the options")
+
+	case model.QuestionCheckbox:
+		switch v := raw.(type) {
+		case bool:
+			return strconv.FormatBool(v), nil
+		case string:
+			checked, err := strconv.ParseBool(v)
+			if err != nil {
+				return "", errors.New("must be true or false")
+			}
+			return strconv.FormatBool(checked), nil
+		}
+		return "", errors.New("must be true or false")
+	}
+	return "", errors.New("unsupported question type")
+}
+
+// buildTicketAnswers mengubah jawaban ternormalisasi menjadi baris TicketAnswer
+func buildTicketAnswers(questions []model.RegistrationQuestion, answers map[string]string) []model.TicketAnswer {
+	var rows []model.TicketAnswer
+	for _, q := range questions {
+		if value, ok := answers[q.Key]; ok {
+			rows = append(rows, model.TicketAnswer{QuestionID: q.ID, Value: value})
+		}
+	}
+	return rows
+}
+
+func mapQuestionToResponse(q *model.RegistrationQuestion) dto.RegistrationQuestionResponse {
+	return dto.RegistrationQuestionResponse{
+		ID:        q.ID,
+		Key:       q.Key,
+		Label:     q.Label,
+		Type:      string(q.Type),
+		Required:  q.Required,
+		Options:   q.Options,
+		MinLength: q.MinLength,
+		MaxLength: q.MaxLength,
+		Min:       q.Min,
+		Max:       q.Max,
+		Pattern:   q.Pattern,
+		Position:  q.Position,
+	}
+}
+
+func mapFormToResponse(eventID uint, questions []model.RegistrationQuestion) *dto.RegistrationFormResponse {
+	response := &dto.RegistrationFormResponse{EventID: eventID, Questions: []dto.RegistrationQuestionResponse{}}
+	for i := range questions {
+		response.Questions = append(response.Questions, mapQuestionToResponse(&questions[i]))
+	}
+	return response
+}
+
+func mapAnswersToResponse(answers []model.TicketAnswer) map[string]string {
+	if len(answers) == 0 {
+		return nil
+	}
+	response := make(map[string]string, len(answers))
+	for _, answer := range answers {
+		response[answer.Question.Key] = answer.Value
+	}
+	return response
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"ticketing/dto"
+	"ticketing/model"
+	"ticketing/repository"
+)
+
+type RefundService interface {
+	GetPolicy(eventID uint) (*dto.CancellationPolicyResponse, error)
+	SetPolicy(eventID uint, req dto.CancellationPolicyRequest) (*dto.CancellationPolicyResponse, error)
+	PreviewRefund(userID, ticketID uint) (*dto.RefundPreviewResponse, error)
+	RefundCancelledTicket(ticket *model.Ticket, quote *dto.RefundPreviewResponse, reason string) (*dto.RefundResponse, error)
+	IssueRefund(ticketID uint, req dto.AdminRefundRequest) (*dto.RefundResponse, error)
+	GetTicketRefunds(ticketID uint) ([]dto.RefundResponse, error)
+}
+
+type refundService struct {
+	refundRepo     repository.RefundRepository
+	ticketRepo     repository.TicketRepository
+	eventRepo      repository.EventRepository
+	paymentRepo    repository.PaymentRepository
+	paymentService PaymentService
+}
+
+func NewRefundService(refundRepo repository.RefundRepository, ticketRepo repository.TicketRepository, eventRepo repository.EventRepository,
+	paymentRepo repository.PaymentRepository, paymentService PaymentService) RefundService {
+	return &refundService{
+		refundRepo:     refundRepo,
+		ticketRepo:     ticketRepo,
+		eventRepo:      eventRepo,
+		paymentRepo:    paymentRepo,
+		paymentService: paymentService,
+	}
+}
+
+func (s *refundService) GetPolicy(eventID uint) (*dto.CancellationPolicyResponse, error) {
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	rules, err := s.refundRepo.FindRules(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapPolicyToResponse(eventID, rules), nil
+}
+
+func (s *refundService) SetPolicy(eventID uint, req dto.CancellationPolicyRequest) (*dto.CancellationPolicyResponse, error) {
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	seen := make(map[int]bool)
+	var rules []model.CancellationRule
+	for _, rule := range req.Rules {
+		if seen[rule.HoursBefore] {
+			return nil, errors.New("each rule must have a distinct hours_before")
+		}
+		seen[rule.HoursBefore] = true
+		rules = append(rules, model.CancellationRule{
+			EventID:       eventID,
+			HoursBefore:   rule.HoursBefore,
+			RefundPercent: rule.RefundPercent,
+		})
+	}
+
+	if err := s.refundRepo.ReplaceRules(eventID, rules); err != nil {
+		return nil, err
+	}
+
+	return s.GetPolicy(eventID)
+}
+
+func (s *refundService) PreviewRefund(userID, ticketID uint) (*dto.RefundPreviewResponse, error) {
+	ticket, err := s.ticketRepo.FindByID(ticketID)
+	if err != nil || ticket.UserID != userID {
+		return nil, errors.New("ticket not found")
+	}
+	if ticket.Status != model.Booked {
+		return nil, errors.New("only booked tickets can be cancelled")
+	}
+
+	return s.quote(ticket, &ticket.Event, time.Now())
+}
+
+// quote menghitung nilai refund sesuai kebijakan pembatalan event pada waktu now.
+// Event tanpa kebijakan mengembalikan dana penuh.
+func (s *refundService) quote(ticket *model.Ticket, event *model.Event, now time.Time) (*dto.RefundPreviewResponse, error) {
+	rules, err := s.refundRepo.FindRules(event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	alreadyRefunded, err := s.refundRepo.SumByTicket(ticket.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	paid := 0.0
+	if ticket.PaymentStatus == model.Success {
+		paid = ticket.SubTotal
+	}
+
+	hoursBefore := event.StartsAt.Sub(now).Hours()
+	percent := 100.0
+	if len(rules) > 0 {
+		percent = 0
+		for _, rule := range rules { // urut hours_before DESC
+			if hoursBefore >= float64(rule.HoursBefore) {
+				percent = rule.RefundPercent
+				break
+			}
+		}
+	}
+
+	amount := math.Min(paid*percent/100, paid-alreadyRefunded)
+	if amount < 0 {
+		amount = 0
+	}
+
+	return &dto.RefundPreviewResponse{
+		TicketID:         ticket.ID,
+		PaidAmount:       paid,
+		AlreadyRefunded:  alreadyRefunded,
+		HoursBeforeEvent: math.Round(hoursBefore*100) / 100,
+		RefundPercent:    percent,
+		RefundAmount:     math.Round(amount*100) / 100,
+	}, nil
+}
+
+func (s *refundService) RefundCancelledTicket(ticket *model.Ticket, quote *dto.RefundPreviewResponse, reason string) (*dto.RefundResponse, error) {
+	if quote.RefundAmount <= 0 {
+		return nil, nil
+	}
+	if reason == "" {
+		reason = "ticket cancelled by user"
+	}
+	return s.refund(ticket, quote.RefundAmount, quote.RefundPercent, reason)
+}
+
+// IssueRefund dipakai admin untuk refund parsial/manual tanpa membatalkan tiket
+func (s *refundService) IssueRefund(ticketID uint, req dto.AdminRefundRequest) (*dto.RefundResponse, error) {
+	ticket, err := s.ticketRepo.FindByID(ticketID)
+	if err != nil {
+		return nil, errors.New("ticket not found")
+	}
+	if ticket.PaymentStatus != model.Success {
+		return nil, errors.New("ticket has not been paid")
+	}
+
+	// Sisa dana dicek ulang di dalam transaksi yang mengunci tiket saat refund dicatat
+	percent := 0.0
+	if ticket.SubTotal > 0 {
+		percent = math.Round(req.Amount/ticket.SubTotal*10000) / 100
+	}
+	return s.refund(ticket, req.Amount, percent, req.Reason)
+}
+
+func (s *refundService) GetTicketRefunds(ticketID uint) ([]dto.RefundResponse, error) {
+	refunds, err := s.refundRepo.FindByTicket(ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []dto.RefundResponse
+	for i := range refunds {
+		responses = append(responses, *mapRefundToResponse(&refunds[i]))
+	}
+	return responses, nil
+}
+
+// refund mencatat Refund lalu meneruskannya ke provider pembayaran yang dipakai tiket
+func (s *refundService) refund(ticket *model.Ticket, amount, percent float64, reason string) (*dto.RefundResponse, error) {
+	refund := &model.Refund{
+		TicketID: ticket.ID,
+		Amount:   amount,
+		Percent:  percent,
+		Reason:   reason,
+		Status:   model.RefundPending,
+	}
+
+	payment, err := s.paymentRepo.FindCapturedForTicket(ticket)
+	if err != nil {
+		// Tiket dibayar sebelum ada payment provider: tunggu diproses manual
+		refund.Error = "no captured payment found, refund requires manual processing"
+		if err := s.refundRepo.CreateWithinBalance(refund); err != nil {
+			return nil, err
+		}
+		return mapRefundToResponse(refund), nil
+	}
+
+	refund.PaymentID = &payment.ID
+	if err := s.refundRepo.CreateWithinBalance(refund); err != nil {
+		return nil, err
+	}
+
+	providerRef, err := s.paymentService.Refund(payment, amount)
+	if err != nil {
+		refund.Status = model.RefundFailed
+		refund.Error = err.Error()
+	} else {
+		refund.Status = model.RefundProcessed
+		refund.ProviderRef = providerRef
+	}
+	if err := s.refundRepo.Update(refund); err != nil {
+		return nil, err
+	}
+
+	if refund.Status == model.RefundProcessed {
+		refunded, err := s.refundRepo.SumByPayment(payment.ID)
+		if err != nil {
+			return nil, err
+		}
+		if refunded >= payment.Amount {
+			if err := s.paymentRepo.UpdateStatus(payment.ID, model.PaymentAttemptRefunded, ""); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return mapRefundToResponse(refund), nil
+}
+
+func mapRefundToResponse(refund *model.Refund) *dto.RefundResponse {
+	return &dto.RefundResponse{
+		ID:        refund.ID,
+		TicketID:  refund.TicketID,
+		Amount:    refund.Amount,
+		Percent:   refund.Percent,
+		Reason:    refund.Reason,
+		Status:    string(refund.Status),
+		Error:     refund.Error,
+		CreatedAt: refund.CreatedAt,
+	}
+}
+
+func mapPolicyToResponse(eventID uint, rules []model.CancellationRule) *dto.CancellationPolicyResponse {
+	response := &dto.CancellationPolicyResponse{EventID: eventID, Rules: []dto.CancellationRuleResponse{}}
+	for _, rule := range rules {
+		response.Rules = append(response.Rules, dto.CancellationRuleResponse{
+			HoursBefore:   rule.HoursBefore,
+			RefundPercent: rule.RefundPercent,
+		})
+	}
+	return response
+}
@@ -2,11 +2,16 @@ package service
 
 import (
 	"bytes"
+	"encoding/csv"
+	"errors"
 	"log"
 	"strconv"
+	"strings"
 	"ticketing/dto"
+	"ticketing/model"
 	"ticketing/repository"
 	"ticketing/utils"
+	"time"
 
 	"github.com/jung-kurt/gofpdf"
 	"github.com/xuri/excelize/v2"
@@ -16,6 +21,10 @@ import (
 type ReportService interface {
 	GetSummaryReport(db *gorm.DB) (dto.SummaryReportResponse, error)
 	GetEventReports(db *gorm.DB) ([]dto.EventReportResponse, error)
+	GetPromoCodeReports(db *gorm.DB) ([]dto.PromoCodeReportResponse, error)
+	GetAttendeeReport(db *gorm.DB, eventID uint) (*dto.AttendeeReportResponse, error)
+	GenerateAttendeeReportExcel(db *gorm.DB, eventID uint) ([]byte, error)
+	GenerateAttendeeReportCSV(db *gorm.DB, eventID uint) ([]byte, error)
 	GenerateSummaryReportExcel(db *gorm.DB) error
 	GenerateEventReportExcel(db *gorm.DB) error
 	GenerateSummaryReportPDF(db *gorm.DB) ([]byte, error)
@@ -40,6 +49,195 @@ func (s *reportService) GetEventReports(db *gorm.DB) ([]dto.EventReportResponse,
 	return s.reportRepo.GetEventReports(db)
 }
 
+func (s *reportService) GetPromoCodeReports(db *gorm.DB) ([]dto.PromoCodeReportResponse, error) {
+	return s.reportRepo.GetPromoCodeReports(db)
+}
+
+// GetAttendeeReport menghasilkan satu baris per attendee pass tiket booked, lengkap
+// dengan jawaban formulir registrasi pembeli
+func (s *reportService) GetAttendeeReport(db *gorm.DB, eventID uint) (*dto.AttendeeReportResponse, error) {
+	event, questions, tickets, err := s.reportRepo.GetAttendees(db, eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	report := &dto.AttendeeReportResponse{
+		EventID:   event.ID,
+		EventName: event.Name,
+		Timezone:  event.TimeLocation().String(),
+		Questions: []dto.RegistrationQuestionResponse{},
+		Rows:      []dto.AttendeeReportRow{},
+	}
+	keyByQuestion := make(map[uint]string, len(questions))
+	for i := range questions {
+		keyByQuestion[questions[i].ID] = questions[i].Key
+		report.Questions = append(report.Questions, mapQuestionToResponse(&questions[i]))
+	}
+
+	for _, ticket := range tickets {
+		answers := make(map[string]string, len(ticket.Answers))
+		for _, answer := range ticket.Answers {
+			answers[keyByQuestion[answer.QuestionID]] = answer.Value
+		}
+		ticketType := ""
+		if ticket.TicketType != nil {
+			ticketType = ticket.TicketType.Name
+		}
+
+		// Tiket lama yang belum punya pass tetap muncul satu baris per orang
+		passes := ticket.Passes
+		if len(passes) == 0 {
+			for seq := 1; seq <= ticket.Qty; seq++ {
+				pass := model.AttendeePass{Seq: seq}
+				if seq == 1 {
+					pass.Name, pass.Email = ticket.User.Name, ticket.User.Email
+				}
+				passes = append(passes, pass)
+			}
+		}
+
+		for _, pass := range passes {
+			report.Rows = append(report.Rows, dto.AttendeeReportRow{
+				TicketID:      ticket.ID,
+				PassSeq:       pass.Seq,
+				AttendeeName:  pass.Name,
+				AttendeeEmail: pass.Email,
+				BuyerName:     ticket.User.Name,
+				BuyerEmail:    ticket.User.Email,
+				TicketType:    ticketType,
+				BookingDate:   ticket.BookedAt.UTC(),
+				CheckedIn:     pass.CheckedInAt != nil || (len(ticket.Passes) == 0 && pass.Seq <= ticket.CheckedIn),
+				Answers:       answers,
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// attendeeFixedColumns adalah jumlah kolom sebelum kolom jawaban pertanyaan registrasi
+const attendeeFixedColumns = 9
+
+// csvCell menetralkan nilai yang akan dibaca sebagai formula saat CSV dibuka di Excel/Sheets
+// (diawali =, @, tab atau CR, atau + dan - yang bukan angka/nomor telepon) dengan prefiks kutip tunggal
+func csvCell(value string) string {
+	if value == "" {
+		return value
+	}
+	switch value[0] {
+	case '=', '@', '\t', '\r':
+		return "'" + value
+	case '+', '-':
+		if strings.Trim(value[1:], "0123456789 .,()-") != "" {
+			return "'" + value
+		}
+	}
+	return value
+}
+
+// escapeAttendeeCSV meng-escape kolom teks bebas yang diisi pembeli (nama, email, tipe tiket
+// dan jawaban). File xlsx tidak perlu karena SetCellStr selalu menyimpan sel sebagai teks.
+func escapeAttendeeCSV(table [][]string) [][]string {
+	for _, line := range table {
+		for i := range line {
+			if (i >= 2 && i <= 6) || i >= attendeeFixedColumns {
+				line[i] = csvCell(line[i])
+			}
+		}
+	}
+	return table
+}
+
+// attendeeReportTable menyusun header dan baris laporan attendee untuk Excel/CSV
+func attendeeReportTable(report *dto.AttendeeReportResponse) [][]string {
+	header := []string{"Ticket ID", "Pass", "Attendee Name", "Attendee Email", "Buyer Name", "Buyer Email",
+		"Ticket Type", "Booking Date (" + report.Timezone + ")", "Checked In"}
+	for _, q := range report.Questions {
+		header = append(header, q.Label)
+	}
+
+	// Tanggal booking ditulis dalam waktu lokal event
+	loc, err := time.LoadLocation(report.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	table := [][]string{header}
+	for _, row := range report.Rows {
+		line := []string{
+			strconv.FormatUint(uint64(row.TicketID), 10),
+			strconv.Itoa(row.PassSeq),
+			row.AttendeeName,
+			row.AttendeeEmail,
+			row.BuyerName,
+			row.BuyerEmail,
+			row.TicketType,
+			row.BookingDate.In(loc).Format("2006-01-02 15:04:05"),
+			strconv.FormatBool(row.CheckedIn),
+		}
+		for _, q := range report.Questions {
+			line = append(line, row.Answers[q.Key])
+		}
+		table = append(table, line)
+	}
+	return table
+}
+
+func (s *reportService) GenerateAttendeeReportExcel(db *gorm.DB, eventID uint) ([]byte, error) {
+	report, err := s.GetAttendeeReport(db, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	f := excelize.NewFile()
+	sheet := "Attendees"
+	index, err := f.NewSheet(sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, line := range attendeeReportTable(report) {
+		for col, value := range line {
+			cell, _ := excelize.CoordinatesToCellName(col+1, i+1)
+			f.SetCellStr(sheet, cell, value)
+		}
+	}
+	f.SetActiveSheet(index)
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		log.Printf("failed to write Excel to buffer: %v", err)
+		return nil, err
+	}
+
+	fileName := "AttendeeReport-" + strconv.FormatUint(uint64(eventID), 10) + ".xlsx"
+	if err := utils.SaveFileToReportFolder(fileName, buf.Bytes()); err != nil {
+		log.Printf("failed to save Excel file: %v", err)
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *reportService) GenerateAttendeeReportCSV(db *gorm.DB, eventID uint) ([]byte, error) {
+	report, err := s.GetAttendeeReport(db, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(escapeAttendeeCSV(attendeeReportTable(report))); err != nil {
+		return nil, err
+	}
+
+	fileName := "AttendeeReport-" + strconv.FormatUint(uint64(eventID), 10) + ".csv"
+	if err := utils.SaveFileToReportFolder(fileName, buf.Bytes()); err != nil {
+		log.Printf("failed to save CSV file: %v", err)
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 func (s *reportService) GenerateSummaryReportExcel(db *gorm.DB) error {
 	// Ambil data laporan
 	summaryReport, err := s.GetSummaryReport(db)
@@ -57,7 +255,7 @@ func (s *reportService) GenerateSummaryReportExcel(db *gorm.DB) error {
 	}
 
 	// Menulis header
-	headers := []string{"Total Events", "Total Tickets", "Total Revenue", "Upcoming Events", "Ongoing Events", "Completed Events"}
+	headers := []string{"Total Events", "Total Tickets", "Total Revenue", "Upcoming Events", "Ongoing Events", "Completed Events", "Gross Revenue", "Total Refunded"}
 	for col, header := range headers {
 		cell, _ := excelize.ColumnNumberToName(col + 1) // Start from 1
 		f.SetCellValue(sheet, cell+"1", header)
@@ -70,6 +268,8 @@ func (s *reportService) GenerateSummaryReportExcel(db *gorm.DB) error {
 	f.SetCellValue(sheet, "D2", summaryReport.Upcoming)
 	f.SetCellValue(sheet, "E2", summaryReport.Ongoing)
 	f.SetCellValue(sheet, "F2", summaryReport.Completed)
+	f.SetCellValue(sheet, "G2", summaryReport.GrossRevenue)
+	f.SetCellValue(sheet, "H2", summaryReport.TotalRefunded)
 
 	// Set sheet as active
 	f.SetActiveSheet(index)
@@ -157,6 +357,10 @@ func (s *reportService) GenerateSummaryReportPDF(db *gorm.DB) ([]byte, error) {
 	pdf.Ln(10)
 	pdf.Cell(40, 10, "Total Tickets: "+strconv.Itoa(summaryReport.TotalTickets))
 	pdf.Ln(10)
+	pdf.Cell(40, 10, "Gross Revenue: $"+strconv.FormatFloat(summaryReport.GrossRevenue, 'f', 2, 64))
+	pdf.Ln(10)
+	pdf.Cell(40, 10, "Total Refunded: $"+strconv.FormatFloat(summaryReport.TotalRefunded, 'f', 2, 64))
+	pdf.Ln(10)
 	pdf.Cell(40, 10, "Total Revenue: $"+strconv.FormatFloat(summaryReport.TotalRevenue, 'f', 2, 64))
 	pdf.Ln(10)
 	pdf.Cell(40, 10, "Upcoming Events: "+strconv.Itoa(summaryReport.Upcoming))
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"ticketing/dto"
+)
+
+func TestEscapeAttendeeCSVEscapesFormulas(t *testing.T) {
+	report := &dto.AttendeeReportResponse{
+		Timezone:  "UTC",
+		Questions: []dto.RegistrationQuestionResponse{{Key: "company", Label: "=Company"}, {Key: "phone", Label: "Phone"}},
+		Rows: []dto.AttendeeReportRow{{
+			TicketID:      7,
+			PassSeq:       1,
+			AttendeeName:  `=HYPERLINK("http://evil.example","x")`,
+			AttendeeEmail: "@SUM(A1)",
+			BuyerName:     "+cmd|' /C calc'!A0",
+			BuyerEmail:    "-2+3",
+			TicketType:    "\tVIP",
+			BookingDate:   time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC),
+			Answers:       map[string]string{"company": "\rACME", "phone": "+62 812-3456-7890"},
+		}},
+	}
+
+	raw := attendeeReportTable(report)
+	if got := raw[1][2]; got != report.Rows[0].AttendeeName {
+		t.Errorf("xlsx attendee name = %q, want unescaped", got)
+	}
+
+	table := escapeAttendeeCSV(attendeeReportTable(report))
+	if got := table[0][9]; got != "'=Company" {
+		t.Errorf("question header = %q, want escaped", got)
+	}
+	want := []string{"7", "1", `'=HYPERLINK("http://evil.example","x")`, "'@SUM(A1)", "'+cmd|' /C calc'!A0",
+		"'-2+3", "'\tVIP", "2026-11-01 12:00:00", "false", "'\rACME", "+62 812-3456-7890"}
+	for i, cell := range table[1] {
+		if cell != want[i] {
+			t.Errorf("cell %d = %q, want %q", i, cell, want[i])
+		}
+	}
+}
+
+func TestCSVCell(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"", ""},
+		{"Budi", "Budi"},
+		{"-5", "-5"},
+		{"+62 812 3456 7890", "+62 812 3456 7890"},
+		{"-1.5", "-1.5"},
+		{"=1+1", "'=1+1"},
+		{"-A1", "'-A1"},
+		{"+SUM(A1:A2)", "'+SUM(A1:A2)"},
+		{"@cmd", "'@cmd"},
+	}
+	for _, tt := range tests {
+		if got := csvCell(tt.value); got != tt.want {
+			t.Errorf("csvCell(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
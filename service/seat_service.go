@@ -0,0 +1,136 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"ticketing/dto"
+	"ticketing/model"
+	"ticketing/repository"
+)
+
+type SeatService interface {
+	ImportSeatMap(eventID uint, req dto.SeatMapRequest) (*dto.SeatMapResponse, error)
+	GetSeatMap(eventID uint) (*dto.SeatMapResponse, error)
+}
+
+type seatService struct {
+	seatRepo  repository.SeatRepository
+	eventRepo repository.EventRepository
+}
+
+func NewSeatService(seatRepo repository.SeatRepository, eventRepo repository.EventRepository) SeatService {
+	return &seatService{
+		seatRepo:  seatRepo,
+		eventRepo: eventRepo,
+	}
+}
+
+func (s *seatService) ImportSeatMap(eventID uint, req dto.SeatMapRequest) (*dto.SeatMapResponse, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	var seats []model.Seat
+	positions := make(map[string]bool)
+	for _, section := range req.Sections {
+		var ticketTypeID *uint
+		if section.TicketTypeID != 0 {
+			ticketType := findTicketType(event, section.TicketTypeID)
+			if ticketType == nil {
+				return nil, fmt.Errorf("section %s references an unknown ticket type", section.Name)
+			}
+			ticketTypeID = &ticketType.ID
+		}
+
+		for _, row := range section.Rows {
+			for _, number := range row.Seats {
+				seat := model.Seat{
+					EventID:      event.ID,
+					Section:      section.Name,
+					Row:          row.Name,
+					Number:       number,
+					TicketTypeID: ticketTypeID,
+					Status:       model.SeatAvailable,
+				}
+				if positions[seat.Label()] {
+					return nil, fmt.Errorf("duplicate seat %s in seat map", seat.Label())
+				}
+				positions[seat.Label()] = true
+				seats = append(seats, seat)
+			}
+		}
+	}
+
+	if len(seats) > event.Capacity {
+		return nil, errors.New("seat map has more seats than the event capacity")
+	}
+
+	if err := s.seatRepo.ReplaceLayout(event.ID, seats); err != nil {
+		return nil, err
+	}
+
+	return s.GetSeatMap(event.ID)
+}
+
+func (s *seatService) GetSeatMap(eventID uint) (*dto.SeatMapResponse, error) {
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	seats, err := s.seatRepo.FindByEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.SeatMapResponse{EventID: eventID, Total: len(seats)}
+
+	// Susun ulang kursi menjadi section -> row -> seat dengan urutan sesuai import
+	sectionIndex := make(map[string]int)
+	rowIndex := make(map[string]int)
+	for i := range seats {
+		seat := &seats[i]
+		if seat.Status == model.SeatAvailable {
+			response.Available++
+		}
+
+		si, ok := sectionIndex[seat.Section]
+		if !ok {
+			si = len(response.Sections)
+			sectionIndex[seat.Section] = si
+			response.Sections = append(response.Sections, dto.SeatSectionResponse{Name: seat.Section})
+		}
+		section := &response.Sections[si]
+
+		rowKey := seat.Section + "/" + seat.Row
+		ri, ok := rowIndex[rowKey]
+		if !ok {
+			ri = len(section.Rows)
+			rowIndex[rowKey] = ri
+			section.Rows = append(section.Rows, dto.SeatRowResponse{Name: seat.Row})
+		}
+
+		section.Rows[ri].Seats = append(section.Rows[ri].Seats, *mapSeatToResponse(seat))
+	}
+
+	return response, nil
+}
+
+func mapSeatToResponse(seat *model.Seat) *dto.SeatResponse {
+	return &dto.SeatResponse{
+		ID:           seat.ID,
+		Label:        seat.Label(),
+		Number:       seat.Number,
+		TicketTypeID: seat.TicketTypeID,
+		Status:       string(seat.Status),
+	}
+}
+
+func mapSeatsToResponse(seats []model.Seat) []dto.SeatResponse {
+	var responses []dto.SeatResponse
+	for i := range seats {
+		responses = append(responses, *mapSeatToResponse(&seats[i]))
+	}
+	return responses
+}
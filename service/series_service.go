@@ -0,0 +1,463 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"ticketing/dto"
+	"ticketing/model"
+	"ticketing/repository"
+	"ticketing/utils"
+)
+
+// maxSeriesOccurrences membatasi jumlah kejadian yang dibuat dari satu RRULE
+const maxSeriesOccurrences = 366
+
+// Cakupan perubahan untuk kejadian seri: hanya kejadian ini, kejadian ini dan sesudahnya,
+// atau seluruh kejadian yang masih upcoming
+const (
+	EditScopeThis      = "this"
+	EditScopeFollowing = "following"
+	EditScopeAll       = "all"
+)
+
+type SeriesService interface {
+	CreateSeries(req dto.SeriesRequest) (*dto.SeriesResponse, error)
+	GetAllSeries(page, limit int) ([]dto.SeriesResponse, *dto.Pagination, error)
+	GetSeriesByID(id uint) (*dto.SeriesResponse, error)
+	UpdateOccurrences(eventID uint, req dto.EventRequest, scope string) ([]dto.EventResponse, error)
+	PurchasePass(userID, seriesID uint, req dto.SeriesPassRequest) (*dto.OrderResponse, error)
+}
+
+type seriesService struct {
+	seriesRepo      repository.SeriesRepository
+	eventRepo       repository.EventRepository
+	venueRepo       repository.VenueRepository
+	categoryRepo    repository.CategoryRepository
+	tagRepo         repository.TagRepository
+	seatRepo        repository.SeatRepository
+	orderRepo       repository.OrderRepository
+	waitlistService WaitlistService
+	statusScheduler *EventStatusScheduler
+	defaultTimezone string
+}
+
+func NewSeriesService(seriesRepo repository.SeriesRepository, eventRepo repository.EventRepository,
+	venueRepo repository.VenueRepository, categoryRepo repository.CategoryRepository, tagRepo repository.TagRepository,
+	seatRepo repository.SeatRepository, orderRepo repository.OrderRepository,
+	waitlistService WaitlistService, statusScheduler *EventStatusScheduler, defaultTimezone string) SeriesService {
+	return &seriesService{
+		seriesRepo:      seriesRepo,
+		eventRepo:       eventRepo,
+		venueRepo:       venueRepo,
+		categoryRepo:    categoryRepo,
+		tagRepo:         tagRepo,
+		seatRepo:        seatRepo,
+		orderRepo:       orderRepo,
+		waitlistService: waitlistService,
+		statusScheduler: statusScheduler,
+		defaultTimezone: defaultTimezone,
+	}
+}
+
+// CreateSeries membuat seri dan langsung membuat setiap kejadiannya sebagai event
+func (s *seriesService) CreateSeries(req dto.SeriesRequest) (*dto.SeriesResponse, error) {
+	rule, err := utils.ParseRRule(req.RRule)
+	if err != nil {
+		return nil, errors.New("rrule: " + err.Error())
+	}
+
+	venue, err := findVenue(s.venueRepo, req.VenueID)
+	if err != nil {
+		return nil, err
+	}
+	location := req.Location
+	timezone := req.Timezone
+	if venue != nil {
+		if location == "" {
+			location = venueLocation(venue)
+		}
+		if timezone == "" {
+			timezone = venue.Timezone
+		}
+	}
+	if timezone == "" {
+		timezone = s.defaultTimezone
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, errors.New("invalid timezone " + timezone)
+	}
+
+	dtstart, err := utils.ParseTimeIn(req.DateTime, loc)
+	if err != nil {
+		return nil, errors.New("date_time: " + err.Error())
+	}
+	starts, err := rule.Occurrences(dtstart.In(loc), maxSeriesOccurrences)
+	if err != nil {
+		return nil, errors.New("rrule: " + err.Error())
+	}
+
+	excluded, err := parseExDates(req.ExDates, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := s.statusScheduler.DefaultDuration()
+	if req.DurationMinutes > 0 {
+		duration = time.Duration(req.DurationMinutes) * time.Minute
+	}
+	holdMinutes := req.HoldMinutes
+	if holdMinutes == 0 {
+		holdMinutes = defaultHoldMinutes
+	}
+
+	series := &model.EventSeries{
+		Name:             req.Name,
+		Description:      req.Description,
+		Location:         location,
+		Timezone:         timezone,
+		RRule:            strings.ToUpper(strings.TrimPrefix(strings.TrimSpace(req.RRule), "RRULE:")),
+		ExDates:          req.ExDates,
+		DurationMinutes:  int(duration.Minutes()),
+		Capacity:         req.Capacity,
+		Price:            req.Price,
+		HoldMinutes:      holdMinutes,
+		TransfersAllowed: req.TransfersAllowed,
+		PassPrice:        req.PassPrice,
+		VenueID:          req.VenueID,
+	}
+
+	now := time.Now()
+	for _, start := range starts {
+		if excluded(start) {
+			continue
+		}
+		event := model.Event{
+			Name:             series.Name,
+			Description:      series.Description,
+			Location:         series.Location,
+			Timezone:         timezone,
+			StartsAt:         start.UTC(),
+			EndsAt:           start.Add(duration).UTC(),
+			Capacity:         series.Capacity,
+			Price:            series.Price,
+			HoldMinutes:      holdMinutes,
+			TransfersAllowed: series.TransfersAllowed,
+			VenueID:          series.VenueID,
+		}
+		event.Status = scheduledStatus(&event, now)
+		series.Events = append(series.Events, event)
+	}
+	if len(series.Events) == 0 {
+		return nil, errors.New("recurrence produces no occurrences")
+	}
+
+	if err := s.seriesRepo.Create(series); err != nil {
+		return nil, err
+	}
+	return s.GetSeriesByID(series.ID)
+}
+
+func (s *seriesService) GetAllSeries(page, limit int) ([]dto.SeriesResponse, *dto.Pagination, error) {
+	series, total, err := s.seriesRepo.FindAll(page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var responses []dto.SeriesResponse
+	for i := range series {
+		responses = append(responses, *mapSeriesToResponse(&series[i]))
+	}
+
+	pagination := utils.GeneratePagination(page, limit, total)
+	return responses, &pagination, nil
+}
+
+func (s *seriesService) GetSeriesByID(id uint) (*dto.SeriesResponse, error) {
+	series, err := s.seriesRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("series not found")
+	}
+	return mapSeriesToResponse(series), nil
+}
+
+// UpdateOccurrences menerapkan perubahan pada satu kejadian ke kejadian ini dan sesudahnya
+// (following) atau ke semua kejadian yang masih upcoming (all). Perubahan jadwal diterapkan
+// sebagai pergeseran jam dinding: tanggal bergeser sama banyak dan jam mulai mengikuti
+// kejadian yang diubah, sehingga tetap benar melewati DST. Kejadian yang sudah dimulai tidak diubah.
+func (s *seriesService) UpdateOccurrences(eventID uint, req dto.EventRequest, scope string) ([]dto.EventResponse, error) {
+	if scope != EditScopeFollowing && scope != EditScopeAll {
+		return nil, errors.New("scope must be this, following or all")
+	}
+
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+	if event.SeriesID == nil {
+		return nil, errors.New("event is not part of a series")
+	}
+	if event.Status != model.Upcoming {
+		return nil, errors.New("cannot update event that is not upcoming")
+	}
+
+	venue, err := findVenue(s.venueRepo, req.VenueID)
+	if err != nil {
+		return nil, err
+	}
+	location := req.Location
+	if venue != nil && location == "" {
+		location = venueLocation(venue)
+	}
+	categories, tags, err := resolveTaxonomy(s.categoryRepo, s.tagRepo, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Jadwal baru kejadian yang diubah menjadi acuan pergeseran kejadian lain
+	edited := *event
+	if venue != nil && req.Timezone == "" && (event.VenueID == nil || *event.VenueID != venue.ID) {
+		edited.Timezone = venue.Timezone
+	}
+	duration := s.statusScheduler.DefaultDuration()
+	if event.EndsAt.After(event.StartsAt) {
+		duration = event.EndsAt.Sub(event.StartsAt)
+	}
+	if err := applySchedule(&edited, req, duration); err != nil {
+		return nil, err
+	}
+	newLoc := edited.TimeLocation()
+	newStart := edited.StartsAt.In(newLoc)
+	newDuration := edited.EndsAt.Sub(edited.StartsAt)
+	dayShift := daysBetween(event.StartsAt.In(event.TimeLocation()), newStart)
+	hour, minute, second := newStart.Clock()
+
+	from := event.StartsAt
+	if scope == EditScopeAll {
+		from = time.Time{}
+	}
+	targets, err := s.seriesRepo.FindUpcoming(*event.SeriesID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	var increased []uint
+	for i := range targets {
+		target := &targets[i]
+		local := target.StartsAt.In(target.TimeLocation())
+		// Batas bawah kapasitas tiap kejadian dicek ulang di repository dengan baris event terkunci
+		if req.Capacity > target.Capacity {
+			increased = append(increased, target.ID)
+		}
+
+		start := time.Date(local.Year(), local.Month(), local.Day()+dayShift, hour, minute, second, 0, newLoc)
+		target.Name = req.Name
+		target.Description = req.Description
+		target.Location = location
+		target.VenueID = req.VenueID
+		if categories != nil {
+			target.Categories = categories
+		}
+		if tags != nil {
+			target.Tags = tags
+		}
+		target.Timezone = edited.Timezone
+		target.StartsAt = start.UTC()
+		target.EndsAt = start.Add(newDuration).UTC()
+		target.Capacity = req.Capacity
+		target.Price = req.Price
+		if req.HoldMinutes > 0 {
+			target.HoldMinutes = req.HoldMinutes
+		}
+		if req.TransfersAllowed != nil {
+			target.TransfersAllowed = req.TransfersAllowed
+		}
+	}
+
+	if err := s.seriesRepo.UpdateOccurrences(targets); err != nil {
+		return nil, err
+	}
+
+	// Template seri ikut diperbarui agar data seri sesuai dengan kejadiannya
+	if scope == EditScopeAll {
+		if err := s.updateTemplate(*event.SeriesID, req, location, edited.Timezone, newDuration); err != nil {
+			return nil, err
+		}
+	}
+
+	// Kapasitas tambahan ditawarkan lebih dulu ke antrean waitlist
+	for _, id := range increased {
+		s.waitlistService.Release(id)
+	}
+
+	responses := []dto.EventResponse{}
+	for i := range targets {
+		responses = append(responses, *mapEventToResponse(&targets[i], targets[i].Available()))
+	}
+	return responses, nil
+}
+
+// PurchasePass membuat order pending berisi satu tiket untuk setiap sesi seri yang belum
+// dimulai. Total order adalah PassPrice × qty yang dibagi rata ke tiket-tiketnya; order
+// dibayar lewat PATCH /orders/:id/payment seperti checkout biasa.
+func (s *seriesService) PurchasePass(userID, seriesID uint, req dto.SeriesPassRequest) (*dto.OrderResponse, error) {
+	series, err := s.seriesRepo.FindByID(seriesID)
+	if err != nil {
+		return nil, errors.New("series not found")
+	}
+	if series.PassPrice == nil {
+		return nil, errors.New("series passes are not sold for this series")
+	}
+
+	now := time.Now()
+	events, err := s.seriesRepo.FindUpcoming(series.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, errors.New("series has no upcoming sessions")
+	}
+
+	var expiresAt time.Time
+	tickets := make([]*model.Ticket, 0, len(events))
+	for i := range events {
+		event := &events[i]
+		session := event.StartsAt.In(event.TimeLocation()).Format("2006-01-02 15:04")
+
+		seatCount, err := s.seatRepo.CountByEvent(event.ID)
+		if err != nil {
+			return nil, err
+		}
+		if seatCount > 0 || len(event.TicketTypes) > 0 {
+			return nil, fmt.Errorf("session %s uses reserved seating or ticket types and cannot be bought with a series pass", session)
+		}
+
+		answers, err := validateAnswers(event.Questions, req.Answers)
+		if err != nil {
+			return nil, fmt.Errorf("session %s: %w", session, err)
+		}
+
+		// Seluruh order memakai batas hold tersingkat di antara sesinya
+		lineExpiry := now.Add(time.Duration(event.HoldMinutes) * time.Minute)
+		if expiresAt.IsZero() || lineExpiry.Before(expiresAt) {
+			expiresAt = lineExpiry
+		}
+
+		tickets = append(tickets, &model.Ticket{
+			EventID:  event.ID,
+			UserID:   userID,
+			Status:   model.Available,
+			Qty:      req.Qty,
+			BookedAt: now,
+			Answers:  buildTicketAnswers(event.Questions, answers),
+		})
+	}
+
+	order := &model.Order{UserID: userID, SeriesID: &series.ID}
+	shares := splitAmount(*series.PassPrice*float64(req.Qty), len(tickets))
+	if err := s.orderRepo.CheckoutSeriesPass(order, tickets, shares, expiresAt); err != nil {
+		return nil, err
+	}
+
+	created, err := s.orderRepo.FindByID(order.ID)
+	if err != nil {
+		return nil, err
+	}
+	return mapOrderToResponse(created), nil
+}
+
+func (s *seriesService) updateTemplate(seriesID uint, req dto.EventRequest, location, timezone string, duration time.Duration) error {
+	series, err := s.seriesRepo.FindByID(seriesID)
+	if err != nil {
+		return err
+	}
+
+	series.Name = req.Name
+	series.Description = req.Description
+	series.Location = location
+	series.VenueID = req.VenueID
+	series.Timezone = timezone
+	series.DurationMinutes = int(duration.Minutes())
+	series.Capacity = req.Capacity
+	series.Price = req.Price
+	if req.HoldMinutes > 0 {
+		series.HoldMinutes = req.HoldMinutes
+	}
+	if req.TransfersAllowed != nil {
+		series.TransfersAllowed = req.TransfersAllowed
+	}
+	return s.seriesRepo.Update(series)
+}
+
+// parseExDates membaca daftar pengecualian: tanggal ("2006-01-02") melewati semua kejadian
+// di tanggal lokal itu, sedangkan waktu lengkap hanya melewati kejadian yang mulai tepat saat itu
+func parseExDates(values []string, loc *time.Location) (func(time.Time) bool, error) {
+	dates := make(map[string]bool)
+	instants := make(map[int64]bool)
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if day, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+			dates[day.Format("2006-01-02")] = true
+			continue
+		}
+		instant, err := utils.ParseTimeIn(value, loc)
+		if err != nil {
+			return nil, errors.New("exdates: " + err.Error())
+		}
+		instants[instant.Unix()] = true
+	}
+
+	return func(start time.Time) bool {
+		return dates[start.In(loc).Format("2006-01-02")] || instants[start.Unix()]
+	}, nil
+}
+
+// daysBetween menghitung selisih hari kalender antara tanggal lokal from dan to
+func daysBetween(from, to time.Time) int {
+	fromDate := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toDate.Sub(fromDate).Hours() / 24)
+}
+
+// splitAmount membagi total ke n bagian dalam satuan sen; sisa pembulatan masuk ke bagian terakhir
+func splitAmount(total float64, n int) []float64 {
+	share := math.Floor(total*100/float64(n)) / 100
+	shares := make([]float64, n)
+	for i := range shares {
+		shares[i] = share
+	}
+	shares[n-1] = math.Round((total-share*float64(n-1))*100) / 100
+	return shares
+}
+
+func mapSeriesToResponse(series *model.EventSeries) *dto.SeriesResponse {
+	response := &dto.SeriesResponse{
+		ID:               series.ID,
+		Name:             series.Name,
+		Description:      series.Description,
+		Location:         series.Location,
+		Timezone:         series.Timezone,
+		RRule:            series.RRule,
+		ExDates:          series.ExDates,
+		DurationMinutes:  series.DurationMinutes,
+		Capacity:         series.Capacity,
+		Price:            series.Price,
+		HoldMinutes:      series.HoldMinutes,
+		TransfersAllowed: series.TransfersAllowed == nil || *series.TransfersAllowed,
+		PassPrice:        series.PassPrice,
+		VenueID:          series.VenueID,
+	}
+	if response.ExDates == nil {
+		response.ExDates = []string{}
+	}
+
+	for i := range series.Events {
+		event := &series.Events[i]
+		response.Occurrences = append(response.Occurrences, *mapEventToResponse(event, event.Available()))
+	}
+	return response
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"ticketing/dto"
+	"ticketing/model"
+	"ticketing/repository"
+	"ticketing/utils"
+)
+
+type TagService interface {
+	CreateTag(req dto.TagRequest) (*dto.TagResponse, error)
+	GetTags() ([]dto.TagResponse, error)
+	UpdateTag(id uint, req dto.TagRequest) (*dto.TagResponse, error)
+	DeleteTag(id uint) error
+}
+
+type tagService struct {
+	tagRepo repository.TagRepository
+}
+
+func NewTagService(tagRepo repository.TagRepository) TagService {
+	return &tagService{tagRepo: tagRepo}
+}
+
+func (s *tagService) CreateTag(req dto.TagRequest) (*dto.TagResponse, error) {
+	tag := &model.Tag{}
+	if err := s.applyTagRequest(tag, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.tagRepo.Create(tag); err != nil {
+		return nil, err
+	}
+	return mapTagToResponse(tag), nil
+}
+
+func (s *tagService) GetTags() ([]dto.TagResponse, error) {
+	tags, err := s.tagRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	return mapTagsToResponse(tags), nil
+}
+
+func (s *tagService) UpdateTag(id uint, req dto.TagRequest) (*dto.TagResponse, error) {
+	tag, err := s.tagRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("tag not found")
+	}
+
+	if err := s.applyTagRequest(tag, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.tagRepo.Update(tag); err != nil {
+		return nil, err
+	}
+	return mapTagToResponse(tag), nil
+}
+
+func (s *tagService) DeleteTag(id uint) error {
+	if _, err := s.tagRepo.FindByID(id); err != nil {
+		return errors.New("tag not found")
+	}
+	return s.tagRepo.Delete(id)
+}
+
+func (s *tagService) applyTagRequest(tag *model.Tag, req dto.TagRequest) error {
+	slug := utils.Slugify(req.Slug)
+	if req.Slug == "" {
+		slug = utils.Slugify(req.Name)
+	}
+	if slug == "" {
+		return errors.New("slug must contain letters or digits")
+	}
+	if existing, err := s.tagRepo.FindBySlugs([]string{slug}); err == nil && len(existing) > 0 && existing[0].ID != tag.ID {
+		return fmt.Errorf("tag slug %q already exists", slug)
+	}
+
+	tag.Name = req.Name
+	tag.Slug = slug
+	return nil
+}
+
+// resolveTags mengubah nama tag bebas dari request event menjadi tag tersimpan;
+// nama dengan slug yang sama digabung dan tag yang belum ada dibuat
+func resolveTags(tagRepo repository.TagRepository, names []string) ([]model.Tag, error) {
+	var tags []model.Tag
+	seen := make(map[string]bool)
+	for _, name := range names {
+		slug := utils.Slugify(name)
+		if slug == "" {
+			return nil, fmt.Errorf("tag %q must contain letters or digits", name)
+		}
+		if !seen[slug] {
+			seen[slug] = true
+			tags = append(tags, model.Tag{Name: name, Slug: slug})
+		}
+	}
+	if len(tags) == 0 {
+		return []model.Tag{}, nil
+	}
+	return tagRepo.FindOrCreate(tags)
+}
+
+func mapTagToResponse(tag *model.Tag) *dto.TagResponse {
+	return &dto.TagResponse{
+		ID:   tag.ID,
+		Name: tag.Name,
+		Slug: tag.Slug,
+	}
+}
+
+func mapTagsToResponse(tags []model.Tag) []dto.TagResponse {
+	responses := []dto.TagResponse{}
+	for i := range tags {
+		responses = append(responses, *mapTagToResponse(&tags[i]))
+	}
+	return responses
+}
@@ -2,11 +2,13 @@ package service
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"ticketing/dto"
 	"ticketing/model"
 	"ticketing/repository"
+	"ticketing/utils"
 )
 
 type TicketService interface {
@@ -14,66 +16,189 @@ type TicketService interface {
 	GetAllTickets(page, limit int) ([]dto.TicketResponse, *dto.Pagination, error)
 	GetUserTickets(userID uint, page, limit int) ([]dto.TicketResponse, *dto.Pagination, error)
 	GetTicketByID(userID, ticketID uint) (*dto.TicketResponse, error)
-	CancelTicket(userID, ticketID uint) error
+	CancelTicket(userID, ticketID uint, reason string) (*dto.RefundResponse, error)
 	UpdatePayment(userID, ticketID uint) (*dto.PaymentUpdateResponse, error)
 	CancelPayment(userID, ticketID uint) (*dto.PaymentUpdateResponse, error)
+	AcceptWaitlistOffer(userID, entryID uint, req dto.WaitlistAcceptRequest) (*dto.TicketResponse, error)
+	GetTicketPDF(userID uint, isAdmin bool, ticketID uint) ([]byte, error)
 }
 
 type ticketService struct {
-	ticketRepo repository.TicketRepository
-	eventRepo  repository.EventRepository
+	ticketRepo      repository.TicketRepository
+	eventRepo       repository.EventRepository
+	seatRepo        repository.SeatRepository
+	paymentService  PaymentService
+	refundService   RefundService
+	promoRepo       repository.PromoCodeRepository
+	waitlistRepo    repository.WaitlistRepository
+	waitlistService WaitlistService
+
+	credentialService CredentialService
 }
 
-func NewTicketService(ticketRepo repository.TicketRepository, eventRepo repository.EventRepository) TicketService {
+func NewTicketService(ticketRepo repository.TicketRepository, eventRepo repository.EventRepository,
+	seatRepo repository.SeatRepository, paymentService PaymentService, refundService RefundService,
+	promoRepo repository.PromoCodeRepository, waitlistRepo repository.WaitlistRepository, waitlistService WaitlistService,
+	credentialService CredentialService) TicketService {
 	return &ticketService{
-		ticketRepo: ticketRepo,
-		eventRepo:  eventRepo,
+		ticketRepo:      ticketRepo,
+		eventRepo:       eventRepo,
+		seatRepo:        seatRepo,
+		paymentService:  paymentService,
+		refundService:   refundService,
+		promoRepo:       promoRepo,
+		waitlistRepo:    waitlistRepo,
+		waitlistService: waitlistService,
+
+		credentialService: credentialService,
 	}
 }
 
 func (s *ticketService) PurchaseTicket(userID uint, req dto.TicketRequest) (*dto.TicketResponse, error) {
+	ticket, seatIDs, err := s.prepareTicket(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ticketRepo.Purchase(ticket, seatIDs); err != nil {
+		return nil, err
+	}
+
+	return s.GetTicketByID(userID, ticket.ID)
+}
+
+// AcceptWaitlistOffer membeli tiket dari tawaran waitlist memakai kuota yang sudah dicadangkan
+func (s *ticketService) AcceptWaitlistOffer(userID, entryID uint, req dto.WaitlistAcceptRequest) (*dto.TicketResponse, error) {
+	entry, err := s.waitlistRepo.FindByID(entryID)
+	if err != nil || entry.UserID != userID {
+		return nil, errors.New("waitlist entry not found")
+	}
+	if entry.Status != model.WaitlistOffered {
+		return nil, repository.ErrOfferNotActive
+	}
+
+	ticket, seatIDs, err := s.prepareTicket(userID, dto.TicketRequest{
+		EventID:      entry.EventID,
+		TicketTypeID: req.TicketTypeID,
+		Qty:          entry.Qty,
+		SeatIDs:      req.SeatIDs,
+		PromoCode:    req.PromoCode,
+		Answers:      req.Answers,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.waitlistRepo.AcceptOffer(entry.ID, ticket, seatIDs); err != nil {
+		return nil, err
+	}
+
+	return s.GetTicketByID(userID, ticket.ID)
+}
+
+// prepareTicket memvalidasi request pembelian dan menyiapkan tiket yang belum disimpan.
+// Pengecekan kuota dan insert dilakukan atomik di repository.
+func (s *ticketService) prepareTicket(userID uint, req dto.TicketRequest) (*model.Ticket, []uint, error) {
 	// Cek ketersediaan event
 	event, err := s.eventRepo.FindByID(req.EventID)
 	if err != nil {
-		return nil, errors.New("event not found")
+		return nil, nil, errors.New("event not found")
 	}
 
 	if event.Status != model.Upcoming {
-		return nil, errors.New("event is not available for ticket purchase")
+		return nil, nil, errors.New("event is not available for ticket purchase")
 	}
 
-	available, err := s.eventRepo.GetAvailableTickets(event.ID)
+	// Event dengan reserved seating wajib memilih kursi; qty mengikuti jumlah kursi
+	seatIDs := uniqueIDs(req.SeatIDs)
+	seatCount, err := s.seatRepo.CountByEvent(event.ID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if seatCount > 0 {
+		if len(seatIDs) == 0 {
+			return nil, nil, errors.New("seat_ids are required for this event")
+		}
+		if req.Qty != 0 && req.Qty != len(seatIDs) {
+			return nil, nil, errors.New("qty must match the number of selected seats")
+		}
+		req.Qty = len(seatIDs)
+	} else if len(seatIDs) > 0 {
+		return nil, nil, errors.New("this event does not use reserved seating")
+	}
+	if req.Qty < 1 {
+		return nil, nil, errors.New("qty must be at least 1")
 	}
 
-	if available <= 0 {
-		return nil, errors.New("no available tickets for this event")
+	now := time.Now()
+
+	// Event yang punya tier wajib menyebutkan ticket_type_id
+	var ticketTypeID *uint
+	if len(event.TicketTypes) > 0 || req.TicketTypeID != 0 {
+		ticketType := findTicketType(event, req.TicketTypeID)
+		if ticketType == nil {
+			return nil, nil, errors.New("a valid ticket_type_id is required for this event")
+		}
+		if err := validateTicketTypeOrder(ticketType, req.Qty, now); err != nil {
+			return nil, nil, err
+		}
+		ticketTypeID = &ticketType.ID
 	}
 
-	// Hitung SubTotal berdasarkan harga tiket dan jumlah (qty)
-	subTotal := event.Price * float64(req.Qty)
+	// Kode promo divalidasi di sini; diskon dan batas pemakaian diterapkan atomik saat pembelian
+	var promoCodeID *uint
+	if code := strings.ToUpper(strings.TrimSpace(req.PromoCode)); code != "" {
+		promo, err := s.promoRepo.FindByCode(code)
+		if err != nil {
+			return nil, nil, errors.New("invalid promo code")
+		}
+		if err := validatePromoCode(promo, event.ID, ticketTypeID, req.Qty, now); err != nil {
+			return nil, nil, err
+		}
+		promoCodeID = &promo.ID
+	}
 
-	// Buat tiket baru
-	ticket := &model.Ticket{
-		EventID:     event.ID,
-		UserID:      userID,
-		Status:      model.Available, // Status awal Available
-		Qty:         req.Qty,
-		SubTotal:    subTotal,
-		BookingDate: time.Now().Format("2006-01-02 15:04:05"),
+	// Jawaban formulir registrasi divalidasi terhadap pertanyaan event dan disimpan bersama tiket
+	answers, err := validateAnswers(event.Questions, req.Answers)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if err := s.ticketRepo.Create(ticket); err != nil {
-		return nil, err
+	// Buat tiket baru dengan hold sesuai HoldMinutes event
+	expiresAt := now.Add(time.Duration(event.HoldMinutes) * time.Minute)
+	ticket := &model.Ticket{
+		EventID:      event.ID,
+		TicketTypeID: ticketTypeID,
+		PromoCodeID:  promoCodeID,
+		UserID:       userID,
+		Status:       model.Available, // Status awal Available
+		Qty:          req.Qty,
+		BookedAt:     now,
+		ExpiresAt:    &expiresAt,
+		Answers:      buildTicketAnswers(event.Questions, answers),
 	}
 
-	event.Capacity -= req.Qty
-	if err := s.eventRepo.Update(event); err != nil {
-		return nil, errors.New("failed to update event capacity")
+	return ticket, seatIDs, nil
+}
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	var result []uint
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
 	}
+	return result
+}
 
-	return s.mapTicketToResponse(ticket, event), nil
+func findTicketType(event *model.Event, id uint) *model.TicketType {
+	for i := range event.TicketTypes {
+		if event.TicketTypes[i].ID == id {
+			return &event.TicketTypes[i]
+		}
+	}
+	return nil
 }
 
 func (s *ticketService) GetUserTickets(userID uint, page, limit int) ([]dto.TicketResponse, *dto.Pagination, error) {
@@ -84,7 +209,7 @@ func (s *ticketService) GetUserTickets(userID uint, page, limit int) ([]dto.Tick
 
 	var responses []dto.TicketResponse
 	for _, ticket := range tickets {
-		responses = append(responses, *s.mapTicketToResponse(&ticket, &ticket.Event))
+		responses = append(responses, *mapTicketToResponse(&ticket, &ticket.Event))
 	}
 
 	pagination := &dto.Pagination{
@@ -107,52 +232,93 @@ func (s *ticketService) GetTicketByID(userID, ticketID uint) (*dto.TicketRespons
 		return nil, errors.New("unauthorized to view this ticket")
 	}
 
-	return s.mapTicketToResponse(ticket, &ticket.Event), nil
+	return mapTicketToResponse(ticket, &ticket.Event), nil
 }
 
-func (s *ticketService) CancelTicket(userID, ticketID uint) error {
+func (s *ticketService) CancelTicket(userID, ticketID uint, reason string) (*dto.RefundResponse, error) {
 	ticket, err := s.ticketRepo.FindByID(ticketID)
 	if err != nil {
-		return errors.New("ticket not found")
+		return nil, errors.New("ticket not found")
 	}
 
 	if ticket.UserID != userID {
-		return errors.New("unauthorized to cancel this ticket")
+		return nil, errors.New("unauthorized to cancel this ticket")
 	}
 
 	if ticket.Status != model.Booked {
-		return errors.New("only booked tickets can be cancelled")
+		return nil, errors.New("only booked tickets can be cancelled")
+	}
+
+	if ticket.CheckedIn > 0 {
+		return nil, errors.New("cannot cancel a ticket that has already been checked in")
 	}
 
 	// Check if event has already started
 	event, err := s.eventRepo.FindByID(ticket.EventID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	eventTime, err := time.Parse("2006-01-02 15:04:05", event.DateTime)
+	if time.Now().After(event.StartsAt) {
+		return nil, errors.New("cannot cancel ticket for event that has already started")
+	}
+
+	// Nilai refund dihitung sebelum tiket dibatalkan, sama dengan yang dilihat user di preview
+	quote, err := s.refundService.PreviewRefund(userID, ticketID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if time.Now().After(eventTime) {
-		return errors.New("cannot cancel ticket for event that has already started")
+	if err := s.ticketRepo.Cancel(ticketID); err != nil {
+		return nil, err
 	}
+	s.credentialService.Revoke(ticketID, "cancelled")
+	s.waitlistService.Release(ticket.EventID)
 
-	return s.ticketRepo.Cancel(ticketID)
+	return s.refundService.RefundCancelledTicket(ticket, quote, reason)
 }
 
-func (s *ticketService) mapTicketToResponse(ticket *model.Ticket, event *model.Event) *dto.TicketResponse {
+func mapTicketToResponse(ticket *model.Ticket, event *model.Event) *dto.TicketResponse {
+	price := event.Price
+	ticketTypeName := ""
+	if ticket.TicketType != nil {
+		price = ticket.TicketType.Price
+		ticketTypeName = ticket.TicketType.Name
+	}
+
+	// Tiket lama belum punya original_total
+	originalTotal := ticket.OriginalTotal
+	if originalTotal == 0 {
+		originalTotal = ticket.SubTotal + ticket.Discount
+	}
+	promoCode := ""
+	if ticket.PromoCode != nil {
+		promoCode = ticket.PromoCode.Code
+	}
+
 	return &dto.TicketResponse{
-		ID:          ticket.ID,
-		EventName:   event.Name,
-		EventDate:   event.DateTime,
-		Location:    event.Location,
-		Price:       event.Price,
-		Status:      string(ticket.Status),
-		BookingDate: ticket.BookingDate,
-		Qty:         ticket.Qty,      // Menyertakan Quantity
-		SubTotal:    ticket.SubTotal, // Menyertakan SubTotal
+		ID:               ticket.ID,
+		OrderID:          ticket.OrderID,
+		EventName:        event.Name,
+		EventDate:        event.StartsAt.UTC(),
+		EventDateLocal:   utils.FormatLocal(event.StartsAt, event.TimeLocation()),
+		Location:         event.Location,
+		TicketType:       ticketTypeName,
+		Price:            price,
+		Status:           string(ticket.Status),
+		PaymentStatus:    string(ticket.PaymentStatus),
+		BookingDate:      ticket.BookedAt.UTC(),
+		BookingDateLocal: utils.FormatLocal(ticket.BookedAt, event.TimeLocation()),
+		Qty:              ticket.Qty, // Menyertakan Quantity
+		OriginalTotal:    originalTotal,
+		Discount:         ticket.Discount,
+		PromoCode:        promoCode,
+		SubTotal:         ticket.SubTotal, // Menyertakan SubTotal
+		ExpiresAt:        ticket.ExpiresAt,
+		CheckedIn:        ticket.CheckedIn,
+		Seats:            mapSeatsToResponse(ticket.Seats),
+		Passes:           mapPassesToResponse(ticket.Passes),
+		Answers:          mapAnswersToResponse(ticket.Answers),
 	}
 }
 
@@ -164,30 +330,41 @@ func (s *ticketService) UpdatePayment(userID, ticketID uint) (*dto.PaymentUpdate
 	if ticket.UserID != userID {
 		return nil, errors.New("unauthorized access to ticket")
 	}
+	if ticket.OrderID != nil {
+		return nil, errors.New("ticket belongs to an order, pay the order instead")
+	}
 	if ticket.Status != model.Available || ticket.PaymentStatus != model.Pending {
 		return nil, errors.New("ticket is not available for payment")
 	}
+	if ticket.ExpiresAt != nil && time.Now().After(*ticket.ExpiresAt) {
+		return nil, repository.ErrHoldExpired
+	}
 
 	// ⬇️ Tambahkan validasi waktu event di sini
 	event, err := s.eventRepo.FindByID(ticket.EventID)
 	if err != nil {
 		return nil, errors.New("event not found")
 	}
-	eventTime, err := time.Parse("2006-01-02 15:04:05", event.DateTime)
-	if err != nil || time.Now().After(eventTime) {
-		return nil, errors.New("event already started or invalid date")
+	if time.Now().After(event.StartsAt) {
+		return nil, errors.New("event already started")
 	}
 
-	// ⬇️ Update status pembayaran jika semua valid
-	err = s.ticketRepo.UpdatePaymentStatus(ticketID, model.Success, model.Booked)
+	// ⬇️ Pembayaran diproses lewat provider; tiket hanya dibooking jika provider mengonfirmasi
+	payment, err := s.paymentService.PayTicket(ticket)
+	if err != nil {
+		return nil, err
+	}
+
+	ticket, err = s.ticketRepo.FindByID(ticketID)
 	if err != nil {
 		return nil, err
 	}
 
 	return &dto.PaymentUpdateResponse{
 		ID:            ticket.ID,
-		Status:        string(model.Booked),
-		PaymentStatus: string(model.Success),
+		Status:        string(ticket.Status),
+		PaymentStatus: string(ticket.PaymentStatus),
+		Payment:       mapPaymentToResponse(payment),
 	}, nil
 }
 
@@ -196,10 +373,22 @@ func (s *ticketService) CancelPayment(userID, ticketID uint) (*dto.PaymentUpdate
 	if err != nil || ticket.UserID != userID {
 		return nil, errors.New("unauthorized or ticket not found")
 	}
+	// Tiket yang sudah dibayar dibatalkan lewat CancelTicket agar aturan refund tetap berlaku
+	if ticket.Status == model.Booked {
+		return nil, errors.New("ticket is already paid, use cancel to request a refund")
+	}
+	if ticket.Status != model.Available || ticket.PaymentStatus != model.Pending {
+		return nil, errors.New("only unpaid tickets can have their payment cancelled")
+	}
+	if ticket.OrderID != nil {
+		return nil, errors.New("ticket belongs to an order, cancel the order instead")
+	}
 
-	if err := s.ticketRepo.UpdatePaymentStatus(ticketID, model.Cancel, model.Cancelled); err != nil {
+	if err := s.ticketRepo.CancelHold(ticketID); err != nil {
 		return nil, err
 	}
+	s.credentialService.Revoke(ticketID, "cancelled")
+	s.waitlistService.Release(ticket.EventID)
 
 	return &dto.PaymentUpdateResponse{
 		ID:            ticket.ID,
@@ -216,7 +405,7 @@ func (s *ticketService) GetAllTickets(page, limit int) ([]dto.TicketResponse, *d
 
 	var responses []dto.TicketResponse
 	for _, ticket := range tickets {
-		responses = append(responses, *s.mapTicketToResponse(&ticket, &ticket.Event))
+		responses = append(responses, *mapTicketToResponse(&ticket, &ticket.Event))
 	}
 
 	pagination := &dto.Pagination{
@@ -228,3 +417,22 @@ func (s *ticketService) GetAllTickets(page, limit int) ([]dto.TicketResponse, *d
 
 	return responses, pagination, nil
 }
+
+// GetTicketPDF membuat e-ticket PDF untuk tiket yang sudah booked; hanya pemilik atau admin
+func (s *ticketService) GetTicketPDF(userID uint, isAdmin bool, ticketID uint) ([]byte, error) {
+	ticket, err := s.ticketRepo.FindByID(ticketID)
+	if err != nil || (!isAdmin && ticket.UserID != userID) {
+		return nil, errors.New("ticket not found")
+	}
+	if ticket.Status != model.Booked {
+		return nil, errors.New("e-ticket is only available for booked tickets")
+	}
+
+	// QR code berisi kredensial Ed25519 yang sama dengan yang diverifikasi scanner
+	issued, err := s.credentialService.GetCredential(userID, isAdmin, ticket.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderETicket(ticket, issued.Token)
+}
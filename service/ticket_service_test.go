@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+
+	"ticketing/model"
+	"ticketing/repository"
+
+	"gorm.io/gorm"
+)
+
+// fakeTicketRepo hanya mengimplementasikan method yang dipakai test; method lain panic
+type fakeTicketRepo struct {
+	repository.TicketRepository
+	ticket    *model.Ticket
+	cancelled bool
+}
+
+func (r *fakeTicketRepo) FindByID(id uint) (*model.Ticket, error) {
+	return r.ticket, nil
+}
+
+func (r *fakeTicketRepo) CancelHold(id uint) error {
+	r.cancelled = true
+	return nil
+}
+
+func TestCancelPaymentRejectsPaidTickets(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        model.TicketStatus
+		paymentStatus model.PaymentStatus
+		wantErr       string
+	}{
+		{name: "booked", status: model.Booked, paymentStatus: model.Success,
+			wantErr: "ticket is already paid, use cancel to request a refund"},
+		{name: "already cancelled", status: model.Cancelled, paymentStatus: model.Cancel,
+			wantErr: "only unpaid tickets can have their payment cancelled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeTicketRepo{ticket: &model.Ticket{Model: gorm.Model{ID: 1}, UserID: 2, Status: tt.status, PaymentStatus: tt.paymentStatus}}
+			svc := &ticketService{ticketRepo: repo}
+
+			_, err := svc.CancelPayment(2, 1)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+			if repo.cancelled {
+				t.Error("CancelHold called for a ticket that is not an unpaid hold")
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"ticketing/dto"
+	"ticketing/model"
+	"ticketing/repository"
+)
+
+type TicketTypeService interface {
+	CreateTicketType(eventID uint, req dto.TicketTypeRequest) (*dto.TicketTypeResponse, error)
+	GetTicketTypes(eventID uint) ([]dto.TicketTypeResponse, error)
+	UpdateTicketType(eventID, id uint, req dto.TicketTypeRequest) (*dto.TicketTypeResponse, error)
+	DeleteTicketType(eventID, id uint) error
+}
+
+type ticketTypeService struct {
+	ticketTypeRepo repository.TicketTypeRepository
+	eventRepo      repository.EventRepository
+}
+
+func NewTicketTypeService(ticketTypeRepo repository.TicketTypeRepository, eventRepo repository.EventRepository) TicketTypeService {
+	return &ticketTypeService{
+		ticketTypeRepo: ticketTypeRepo,
+		eventRepo:      eventRepo,
+	}
+}
+
+func (s *ticketTypeService) CreateTicketType(eventID uint, req dto.TicketTypeRequest) (*dto.TicketTypeResponse, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	if err := validateTicketTypeRequest(event, req); err != nil {
+		return nil, err
+	}
+
+	ticketType := &model.TicketType{EventID: event.ID}
+	applyTicketTypeRequest(ticketType, req)
+
+	if err := s.ticketTypeRepo.Create(ticketType); err != nil {
+		return nil, err
+	}
+
+	return mapTicketTypeToResponse(ticketType), nil
+}
+
+func (s *ticketTypeService) GetTicketTypes(eventID uint) ([]dto.TicketTypeResponse, error) {
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	ticketTypes, err := s.ticketTypeRepo.FindByEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapTicketTypesToResponse(ticketTypes), nil
+}
+
+func (s *ticketTypeService) UpdateTicketType(eventID, id uint, req dto.TicketTypeRequest) (*dto.TicketTypeResponse, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	ticketType, err := s.ticketTypeRepo.FindByID(eventID, id)
+	if err != nil {
+		return nil, errors.New("ticket type not found")
+	}
+
+	if err := validateTicketTypeRequest(event, req); err != nil {
+		return nil, err
+	}
+
+	if req.Quota < ticketType.Sold+ticketType.Held {
+		return nil, errors.New("quota cannot be lower than tickets already sold or held")
+	}
+
+	applyTicketTypeRequest(ticketType, req)
+
+	if err := s.ticketTypeRepo.Update(ticketType); err != nil {
+		return nil, err
+	}
+
+	return mapTicketTypeToResponse(ticketType), nil
+}
+
+func (s *ticketTypeService) DeleteTicketType(eventID, id uint) error {
+	ticketType, err := s.ticketTypeRepo.FindByID(eventID, id)
+	if err != nil {
+		return errors.New("ticket type not found")
+	}
+
+	if ticketType.Sold > 0 || ticketType.Held > 0 {
+		return errors.New("cannot delete ticket type with existing tickets")
+	}
+
+	return s.ticketTypeRepo.Delete(ticketType.ID)
+}
+
+func validateTicketTypeRequest(event *model.Event, req dto.TicketTypeRequest) error {
+	if req.Quota > event.Capacity {
+		return errors.New("ticket type quota cannot exceed event capacity")
+	}
+	if req.MaxPerOrder > 0 && req.MinPerOrder > req.MaxPerOrder {
+		return errors.New("min_per_order cannot be greater than max_per_order")
+	}
+	if req.SalesStart != nil && req.SalesEnd != nil && !req.SalesEnd.After(*req.SalesStart) {
+		return errors.New("sales_end must be after sales_start")
+	}
+	return nil
+}
+
+func applyTicketTypeRequest(ticketType *model.TicketType, req dto.TicketTypeRequest) {
+	ticketType.Name = req.Name
+	ticketType.Price = req.Price
+	ticketType.Quota = req.Quota
+	ticketType.MinPerOrder = req.MinPerOrder
+	if ticketType.MinPerOrder == 0 {
+		ticketType.MinPerOrder = 1
+	}
+	ticketType.MaxPerOrder = req.MaxPerOrder
+	ticketType.SalesStart = req.SalesStart
+	ticketType.SalesEnd = req.SalesEnd
+}
+
+// validateTicketTypeOrder mengecek qty dan waktu pembelian terhadap aturan tier
+func validateTicketTypeOrder(ticketType *model.TicketType, qty int, now time.Time) error {
+	if !ticketType.OnSale(now) {
+		return errors.New("ticket type is not on sale")
+	}
+	if qty < ticketType.MinPerOrder {
+		return errors.New("quantity is below the minimum per order for this ticket type")
+	}
+	if ticketType.MaxPerOrder > 0 && qty > ticketType.MaxPerOrder {
+		return errors.New("quantity exceeds the maximum per order for this ticket type")
+	}
+	return nil
+}
+
+func mapTicketTypeToResponse(ticketType *model.TicketType) *dto.TicketTypeResponse {
+	return &dto.TicketTypeResponse{
+		ID:          ticketType.ID,
+		EventID:     ticketType.EventID,
+		Name:        ticketType.Name,
+		Price:       ticketType.Price,
+		Quota:       ticketType.Quota,
+		Sold:        ticketType.Sold,
+		Held:        ticketType.Held,
+		Available:   ticketType.Available(),
+		MinPerOrder: ticketType.MinPerOrder,
+		MaxPerOrder: ticketType.MaxPerOrder,
+		SalesStart:  ticketType.SalesStart,
+		SalesEnd:    ticketType.SalesEnd,
+		OnSale:      ticketType.OnSale(time.Now()),
+	}
+}
+
+func mapTicketTypesToResponse(ticketTypes []model.TicketType) []dto.TicketTypeResponse {
+	var responses []dto.TicketTypeResponse
+	for i := range ticketTypes {
+		responses = append(responses, *mapTicketTypeToResponse(&ticketTypes[i]))
+	}
+	return responses
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"ticketing/dto"
+	"ticketing/model"
+	"ticketing/repository"
+	"ticketing/utils"
+)
+
+type TransferService interface {
+	InitiateTransfer(userID, ticketID uint, req dto.TransferRequest) (*dto.TransferResponse, error)
+	CancelTransfer(userID, ticketID uint) error
+	GetIncomingTransfers(userID uint) ([]dto.TransferResponse, error)
+	AcceptTransfer(userID, transferID uint) (*dto.TicketResponse, error)
+	GetTransfers(page, limit int, ticketID uint) ([]dto.TransferResponse, *dto.Pagination, error)
+}
+
+type transferService struct {
+	transferRepo repository.TransferRepository
+	ticketRepo   repository.TicketRepository
+	userRepo     repository.UserRepository
+
+	credentialService CredentialService
+}
+
+func NewTransferService(transferRepo repository.TransferRepository, ticketRepo repository.TicketRepository,
+	userRepo repository.UserRepository, credentialService CredentialService) TransferService {
+	return &transferService{
+		transferRepo:      transferRepo,
+		ticketRepo:        ticketRepo,
+		userRepo:          userRepo,
+		credentialService: credentialService,
+	}
+}
+
+func (s *transferService) InitiateTransfer(userID, ticketID uint, req dto.TransferRequest) (*dto.TransferResponse, error) {
+	ticket, err := s.ticketRepo.FindByID(ticketID)
+	if err != nil || ticket.UserID != userID {
+		return nil, errors.New("ticket not found")
+	}
+	if ticket.Status != model.Booked {
+		return nil, errors.New("only booked tickets can be transferred")
+	}
+	if ticket.CheckedIn > 0 {
+		return nil, errors.New("cannot transfer a ticket that has already been checked in")
+	}
+	if err := checkTransferable(&ticket.Event); err != nil {
+		return nil, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if strings.EqualFold(email, ticket.User.Email) {
+		return nil, errors.New("cannot transfer a ticket to yourself")
+	}
+
+	if _, err := s.transferRepo.FindPendingByTicket(ticket.ID); err == nil {
+		return nil, errors.New("ticket already has a pending transfer")
+	}
+
+	transfer := &model.TicketTransfer{
+		TicketID:   ticket.ID,
+		FromUserID: userID,
+		ToEmail:    email,
+		Status:     model.TransferPending,
+	}
+	if err := s.transferRepo.Create(transfer); err != nil {
+		return nil, err
+	}
+
+	return mapTransferToResponse(transfer), nil
+}
+
+func (s *transferService) CancelTransfer(userID, ticketID uint) error {
+	transfer, err := s.transferRepo.FindPendingByTicket(ticketID)
+	if err != nil || transfer.FromUserID != userID {
+		return errors.New("no pending transfer for this ticket")
+	}
+	return s.transferRepo.Cancel(transfer.ID)
+}
+
+func (s *transferService) GetIncomingTransfers(userID uint) ([]dto.TransferResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	transfers, err := s.transferRepo.FindIncoming(strings.ToLower(user.Email))
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []dto.TransferResponse
+	for i := range transfers {
+		responses = append(responses, *mapTransferToResponse(&transfers[i]))
+	}
+	return responses, nil
+}
+
+func (s *transferService) AcceptTransfer(userID, transferID uint) (*dto.TicketResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	transfer, err := s.transferRepo.FindByID(transferID)
+	if err != nil || !strings.EqualFold(transfer.ToEmail, user.Email) {
+		return nil, errors.New("transfer not found")
+	}
+	if transfer.Status != model.TransferPending {
+		return nil, repository.ErrTransferNotPending
+	}
+
+	ticket, err := s.ticketRepo.FindByID(transfer.TicketID)
+	if err != nil {
+		return nil, errors.New("ticket not found")
+	}
+	if err := checkTransferable(&ticket.Event); err != nil {
+		return nil, err
+	}
+
+	if err := s.transferRepo.Accept(transfer.ID, userID); err != nil {
+		if errors.Is(err, repository.ErrInvalidTransition) {
+			return nil, errors.New("ticket can no longer be transferred")
+		}
+		return nil, err
+	}
+
+	// Kredensial pemilik lama dicabut dan diganti kredensial versi baru untuk penerima
+	s.credentialService.Revoke(transfer.TicketID, "transferred")
+	if _, err := s.credentialService.Issue(transfer.TicketID); err != nil {
+		log.Printf("Failed to issue credential for transferred ticket %d: %v", transfer.TicketID, err)
+	}
+
+	ticket, err = s.ticketRepo.FindByID(transfer.TicketID)
+	if err != nil {
+		return nil, err
+	}
+	return mapTicketToResponse(ticket, &ticket.Event), nil
+}
+
+func (s *transferService) GetTransfers(page, limit int, ticketID uint) ([]dto.TransferResponse, *dto.Pagination, error) {
+	transfers, total, err := s.transferRepo.FindAll(page, limit, ticketID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var responses []dto.TransferResponse
+	for i := range transfers {
+		responses = append(responses, *mapTransferToResponse(&transfers[i]))
+	}
+
+	pagination := utils.GeneratePagination(page, limit, total)
+	return responses, &pagination, nil
+}
+
+// checkTransferable memastikan event mengizinkan transfer dan belum dimulai
+func checkTransferable(event *model.Event) error {
+	if !event.AllowsTransfers() {
+		return errors.New("tickets for this event cannot be transferred")
+	}
+
+	if !time.Now().Before(event.StartsAt) {
+		return errors.New("cannot transfer ticket for event that has already started")
+	}
+	return nil
+}
+
+func mapTransferToResponse(transfer *model.TicketTransfer) *dto.TransferResponse {
+	return &dto.TransferResponse{
+		ID:          transfer.ID,
+		TicketID:    transfer.TicketID,
+		FromUserID:  transfer.FromUserID,
+		FromEmail:   transfer.FromUser.Email,
+		ToEmail:     transfer.ToEmail,
+		ToUserID:    transfer.ToUserID,
+		Status:      string(transfer.Status),
+		CreatedAt:   transfer.CreatedAt,
+		RespondedAt: transfer.RespondedAt,
+	}
+}
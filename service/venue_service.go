@@ -0,0 +1,133 @@
+package service
+
+import (
+	"errors"
+
+	"ticketing/dto"
+	"ticketing/model"
+	"ticketing/repository"
+	"ticketing/utils"
+)
+
+type VenueService interface {
+	CreateVenue(req dto.VenueRequest) (*dto.VenueResponse, error)
+	GetAllVenues(page, limit int, city string) ([]dto.VenueResponse, *dto.Pagination, error)
+	GetVenueByID(id uint) (*dto.VenueResponse, error)
+	UpdateVenue(id uint, req dto.VenueRequest) (*dto.VenueResponse, error)
+	DeleteVenue(id uint) error
+}
+
+type venueService struct {
+	venueRepo       repository.VenueRepository
+	defaultTimezone string
+}
+
+func NewVenueService(venueRepo repository.VenueRepository, defaultTimezone string) VenueService {
+	return &venueService{
+		venueRepo:       venueRepo,
+		defaultTimezone: defaultTimezone,
+	}
+}
+
+func (s *venueService) CreateVenue(req dto.VenueRequest) (*dto.VenueResponse, error) {
+	venue := &model.Venue{Timezone: s.defaultTimezone}
+	applyVenueRequest(venue, req)
+
+	if err := s.venueRepo.Create(venue); err != nil {
+		return nil, err
+	}
+	return mapVenueToResponse(venue), nil
+}
+
+func (s *venueService) GetAllVenues(page, limit int, city string) ([]dto.VenueResponse, *dto.Pagination, error) {
+	venues, total, err := s.venueRepo.FindAll(page, limit, city)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var responses []dto.VenueResponse
+	for i := range venues {
+		responses = append(responses, *mapVenueToResponse(&venues[i]))
+	}
+
+	pagination := utils.GeneratePagination(page, limit, total)
+	return responses, &pagination, nil
+}
+
+func (s *venueService) GetVenueByID(id uint) (*dto.VenueResponse, error) {
+	venue, err := s.venueRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("venue not found")
+	}
+	return mapVenueToResponse(venue), nil
+}
+
+// UpdateVenue tidak mengubah zona waktu atau lokasi event yang sudah ada; event tetap
+// memakai Timezone dan Location yang tersimpan saat dibuat
+func (s *venueService) UpdateVenue(id uint, req dto.VenueRequest) (*dto.VenueResponse, error) {
+	venue, err := s.venueRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("venue not found")
+	}
+
+	applyVenueRequest(venue, req)
+
+	if err := s.venueRepo.Update(venue); err != nil {
+		return nil, err
+	}
+	return mapVenueToResponse(venue), nil
+}
+
+func (s *venueService) DeleteVenue(id uint) error {
+	if _, err := s.venueRepo.FindByID(id); err != nil {
+		return errors.New("venue not found")
+	}
+	return s.venueRepo.Delete(id)
+}
+
+func applyVenueRequest(venue *model.Venue, req dto.VenueRequest) {
+	if req.Timezone != "" {
+		venue.Timezone = req.Timezone
+	}
+
+	venue.Name = req.Name
+	venue.Address = req.Address
+	venue.City = req.City
+	venue.Country = req.Country
+	venue.Latitude = req.Latitude
+	venue.Longitude = req.Longitude
+	venue.MaxCapacity = req.MaxCapacity
+	venue.AccessibilityNotes = req.AccessibilityNotes
+}
+
+// findVenue memuat venue yang dipilih request; nil tanpa error jika venueID kosong
+func findVenue(venueRepo repository.VenueRepository, venueID *uint) (*model.Venue, error) {
+	if venueID == nil {
+		return nil, nil
+	}
+	venue, err := venueRepo.FindByID(*venueID)
+	if err != nil {
+		return nil, errors.New("venue not found")
+	}
+	return venue, nil
+}
+
+// venueLocation adalah Location default untuk event di venue jika admin tidak mengisinya
+func venueLocation(venue *model.Venue) string {
+	return venue.Name + ", " + venue.City
+}
+
+func mapVenueToResponse(venue *model.Venue) *dto.VenueResponse {
+	return &dto.VenueResponse{
+		ID:                 venue.ID,
+		Name:               venue.Name,
+		Address:            venue.Address,
+		City:               venue.City,
+		Country:            venue.Country,
+		Latitude:           venue.Latitude,
+		Longitude:          venue.Longitude,
+		MaxCapacity:        venue.MaxCapacity,
+		Timezone:           venue.Timezone,
+		AccessibilityNotes: venue.AccessibilityNotes,
+	}
+}
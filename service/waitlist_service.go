@@ -0,0 +1,195 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"ticketing/dto"
+	"ticketing/model"
+	"ticketing/repository"
+
+	"gorm.io/gorm"
+)
+
+// waitlistExpireBatch membatasi jumlah tawaran yang diakhiri dalam satu putaran
+const waitlistExpireBatch = 100
+
+type WaitlistService interface {
+	Join(userID, eventID uint, req dto.WaitlistJoinRequest) (*dto.WaitlistEntryResponse, error)
+	Leave(userID, eventID uint) error
+	GetPosition(userID, eventID uint) (*dto.WaitlistEntryResponse, error)
+	GetQueue(eventID uint) ([]dto.WaitlistEntryResponse, error)
+	Release(eventID uint)
+	ExpireOffers()
+	ProcessQueues()
+}
+
+type waitlistService struct {
+	waitlistRepo repository.WaitlistRepository
+	eventRepo    repository.EventRepository
+	offerWindow  time.Duration
+}
+
+func NewWaitlistService(waitlistRepo repository.WaitlistRepository, eventRepo repository.EventRepository,
+	offerWindow time.Duration) WaitlistService {
+	return &waitlistService{
+		waitlistRepo: waitlistRepo,
+		eventRepo:    eventRepo,
+		offerWindow:  offerWindow,
+	}
+}
+
+func (s *waitlistService) Join(userID, eventID uint, req dto.WaitlistJoinRequest) (*dto.WaitlistEntryResponse, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+	if event.Status != model.Upcoming {
+		return nil, errors.New("event is not available for ticket purchase")
+	}
+	if req.Qty > event.Capacity {
+		return nil, errors.New("qty exceeds event capacity")
+	}
+	if event.Available() >= req.Qty {
+		return nil, errors.New("tickets are still available, purchase them directly")
+	}
+
+	if _, err := s.waitlistRepo.FindActive(eventID, userID); err == nil {
+		return nil, errors.New("already on the waitlist for this event")
+	}
+
+	entry := &model.WaitlistEntry{
+		EventID: eventID,
+		UserID:  userID,
+		Qty:     req.Qty,
+		Status:  model.WaitlistWaiting,
+	}
+	if err := s.waitlistRepo.Create(entry); err != nil {
+		return nil, err
+	}
+
+	return s.GetPosition(userID, eventID)
+}
+
+func (s *waitlistService) Leave(userID, eventID uint) error {
+	entry, err := s.waitlistRepo.FindActive(eventID, userID)
+	if err != nil {
+		return errors.New("not on the waitlist for this event")
+	}
+
+	wasOffered := entry.Status == model.WaitlistOffered
+	if err := s.waitlistRepo.Leave(entry.ID); err != nil {
+		return err
+	}
+
+	// Kuota dari tawaran yang ditolak langsung diteruskan ke antrean berikutnya
+	if wasOffered {
+		s.Release(eventID)
+	}
+	return nil
+}
+
+func (s *waitlistService) GetPosition(userID, eventID uint) (*dto.WaitlistEntryResponse, error) {
+	entry, err := s.waitlistRepo.FindActive(eventID, userID)
+	if err != nil {
+		return nil, errors.New("not on the waitlist for this event")
+	}
+
+	response := mapWaitlistEntryToResponse(entry)
+	if entry.Status == model.WaitlistWaiting {
+		position, err := s.waitlistRepo.Position(entry)
+		if err != nil {
+			return nil, err
+		}
+		response.Position = position
+	}
+	return response, nil
+}
+
+func (s *waitlistService) GetQueue(eventID uint) ([]dto.WaitlistEntryResponse, error) {
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	entries, err := s.waitlistRepo.FindByEvent(eventID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []dto.WaitlistEntryResponse
+	var position int64
+	for i := range entries {
+		response := mapWaitlistEntryToResponse(&entries[i])
+		response.UserEmail = entries[i].User.Email
+		if entries[i].Status == model.WaitlistWaiting {
+			position++
+			response.Position = position
+		}
+		responses = append(responses, *response)
+	}
+	return responses, nil
+}
+
+// Release menawarkan kuota event yang baru dilepas ke antrean waitlist. Dipanggil
+// setelah pembatalan, hold kedaluwarsa, atau penambahan kapasitas; kegagalan hanya
+// dicatat karena sweeper akan mencoba lagi di putaran berikutnya.
+func (s *waitlistService) Release(eventID uint) {
+	offered, err := s.waitlistRepo.OfferReleased(eventID, time.Now(), s.offerWindow)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("Failed to process waitlist for event %d: %v", eventID, err)
+		return
+	}
+
+	for _, entry := range offered {
+		log.Printf("Offered %d ticket(s) for event %d to waitlisted user %d until %s",
+			entry.Qty, entry.EventID, entry.UserID, entry.OfferExpiresAt.Format(time.RFC3339))
+	}
+}
+
+// ExpireOffers mengakhiri tawaran yang lewat batas waktu lalu meneruskan kuotanya
+func (s *waitlistService) ExpireOffers() {
+	for {
+		expired, err := s.waitlistRepo.ExpireOffers(time.Now(), waitlistExpireBatch)
+		if err != nil {
+			log.Printf("Failed to expire waitlist offers: %v", err)
+			return
+		}
+
+		for _, entry := range expired {
+			log.Printf("Expired waitlist offer %d (event %d, qty %d)", entry.ID, entry.EventID, entry.Qty)
+		}
+
+		if len(expired) < waitlistExpireBatch {
+			return
+		}
+	}
+}
+
+// ProcessQueues menjalankan Release untuk semua event yang masih punya antrean,
+// sebagai jaring pengaman untuk pelepasan kuota yang tidak memanggil Release langsung
+// (mis. pembayaran gagal lewat webhook).
+func (s *waitlistService) ProcessQueues() {
+	eventIDs, err := s.waitlistRepo.EventsWithWaiting()
+	if err != nil {
+		log.Printf("Failed to list waitlisted events: %v", err)
+		return
+	}
+
+	for _, eventID := range eventIDs {
+		s.Release(eventID)
+	}
+}
+
+func mapWaitlistEntryToResponse(entry *model.WaitlistEntry) *dto.WaitlistEntryResponse {
+	return &dto.WaitlistEntryResponse{
+		ID:             entry.ID,
+		EventID:        entry.EventID,
+		UserID:         entry.UserID,
+		Qty:            entry.Qty,
+		Status:         string(entry.Status),
+		OfferedAt:      entry.OfferedAt,
+		OfferExpiresAt: entry.OfferExpiresAt,
+		TicketID:       entry.TicketID,
+	}
+}
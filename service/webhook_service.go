@@ -0,0 +1,136 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"ticketing/dto"
+	"ticketing/model"
+	"ticketing/payment"
+	"ticketing/repository"
+	"ticketing/utils"
+
+	"gorm.io/gorm"
+)
+
+var ErrUnknownProvider = errors.New("unknown payment provider")
+
+type WebhookService interface {
+	ReceivePaymentWebhook(provider string, payload []byte, signature string) (*model.WebhookDelivery, error)
+	GetDeliveries(page, limit int, status string) ([]model.WebhookDelivery, *dto.Pagination, error)
+	Replay(id uint) (*model.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	providers      map[string]payment.Provider
+	webhookRepo    repository.WebhookRepository
+	paymentService PaymentService
+}
+
+func NewWebhookService(providers map[string]payment.Provider, webhookRepo repository.WebhookRepository, paymentService PaymentService) WebhookService {
+	return &webhookService{
+		providers:      providers,
+		webhookRepo:    webhookRepo,
+		paymentService: paymentService,
+	}
+}
+
+// ReceivePaymentWebhook memverifikasi signature, menyimpan delivery, lalu memprosesnya.
+// Delivery dengan event ID yang sudah pernah diterima dikembalikan apa adanya tanpa diproses ulang.
+func (s *webhookService) ReceivePaymentWebhook(provider string, payload []byte, signature string) (*model.WebhookDelivery, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	delivery := &model.WebhookDelivery{
+		Provider:  provider,
+		Signature: signature,
+		Payload:   string(payload),
+		Status:    model.WebhookReceived,
+	}
+
+	event, err := p.VerifyWebhook(payload, signature)
+	if err != nil {
+		delivery.Status = model.WebhookRejected
+		delivery.Error = err.Error()
+		if createErr := s.webhookRepo.Create(delivery); createErr != nil {
+			return nil, createErr
+		}
+		return delivery, payment.ErrInvalidSignature
+	}
+	delivery.Verified = true
+	delivery.EventID = &event.ID
+
+	if existing, err := s.webhookRepo.FindByEventID(provider, event.ID); err == nil {
+		return existing, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err := s.webhookRepo.Create(delivery); err != nil {
+		// Delivery yang sama bisa datang bersamaan; unique index memastikan hanya satu yang diproses
+		if existing, findErr := s.webhookRepo.FindByEventID(provider, event.ID); findErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+
+	return delivery, s.process(delivery, *event)
+}
+
+func (s *webhookService) GetDeliveries(page, limit int, status string) ([]model.WebhookDelivery, *dto.Pagination, error) {
+	deliveries, total, err := s.webhookRepo.FindAll(page, limit, status)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := utils.GeneratePagination(page, limit, total)
+	return deliveries, &pagination, nil
+}
+
+// Replay memproses ulang delivery terverifikasi yang sebelumnya gagal
+func (s *webhookService) Replay(id uint) (*model.WebhookDelivery, error) {
+	delivery, err := s.webhookRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("webhook delivery not found")
+	}
+
+	if !delivery.Verified {
+		return nil, errors.New("cannot replay an unverified webhook")
+	}
+	if delivery.Status == model.WebhookProcessed {
+		return nil, errors.New("webhook has already been processed")
+	}
+
+	var event payment.WebhookEvent
+	if err := json.Unmarshal([]byte(delivery.Payload), &event); err != nil {
+		return nil, err
+	}
+
+	return delivery, s.process(delivery, event)
+}
+
+func (s *webhookService) process(delivery *model.WebhookDelivery, event payment.WebhookEvent) error {
+	now := time.Now()
+	delivery.Attempts++
+	delivery.ProcessedAt = &now
+
+	processErr := s.paymentService.HandleProviderEvent(delivery.Provider, event)
+	if errors.Is(processErr, ErrPaymentMismatch) {
+		delivery.Status = model.WebhookRejected
+		delivery.Error = processErr.Error()
+	} else if processErr != nil {
+		delivery.Status = model.WebhookFailed
+		delivery.Error = processErr.Error()
+	} else {
+		delivery.Status = model.WebhookProcessed
+		delivery.Error = ""
+	}
+
+	if err := s.webhookRepo.Update(delivery); err != nil {
+		return err
+	}
+	return processErr
+}
@@ -0,0 +1,52 @@
+package utils
+
+import "math"
+
+// earthRadiusKm adalah jari-jari rata-rata bumi yang dipakai rumus haversine
+const earthRadiusKm = 6371.0
+
+// HaversineKm menghitung jarak lingkaran besar antara dua koordinat (derajat) dalam kilometer
+func HaversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := radians(lat2 - lat1)
+	dLng := radians(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// BoundingBox mengembalikan kotak lintang/bujur yang memuat semua titik dalam radiusKm dari
+// (lat, lng). Jika kotak melewati garis bujur 180°, minLng > maxLng; di dekat kutub
+// rentang bujur menjadi penuh (-180..180).
+func BoundingBox(lat, lng, radiusKm float64) (minLat, maxLat, minLng, maxLng float64) {
+	angular := radiusKm / earthRadiusKm
+	minLat = lat - degrees(angular)
+	maxLat = lat + degrees(angular)
+	if minLat <= -90 || maxLat >= 90 {
+		return math.Max(minLat, -90), math.Min(maxLat, 90), -180, 180
+	}
+
+	ratio := math.Sin(angular) / math.Cos(radians(lat))
+	if ratio >= 1 {
+		return minLat, maxLat, -180, 180
+	}
+	dLng := degrees(math.Asin(ratio))
+	return minLat, maxLat, normalizeLng(lng - dLng), normalizeLng(lng + dLng)
+}
+
+func normalizeLng(lng float64) float64 {
+	if lng < -180 {
+		return lng + 360
+	}
+	if lng > 180 {
+		return lng - 360
+	}
+	return lng
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHaversineKm(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lng1, lat2, lng2 float64
+		wantKm                 float64
+	}{
+		{"same point", -6.1754, 106.8272, -6.1754, 106.8272, 0},
+		{"Jakarta-Bandung", -6.2088, 106.8456, -6.9175, 107.6191, 116.2},
+		{"London-Paris", 51.5074, -0.1278, 48.8566, 2.3522, 343.6},
+		{"New York-Los Angeles", 40.7128, -74.0060, 34.0522, -118.2437, 3935.7},
+		{"Suva-Apia across the antimeridian", -18.1248, 178.4501, -13.8333, -171.7667, 1149.2},
+		{"antipodes", 0, 0, 0, 180, math.Pi * earthRadiusKm},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := HaversineKm(tt.lat1, tt.lng1, tt.lat2, tt.lng2)
+			if math.Abs(got-tt.wantKm) > 0.5 {
+				t.Errorf("HaversineKm = %.2f km, want %.1f km", got, tt.wantKm)
+			}
+			// Jarak harus simetris
+			if back := HaversineKm(tt.lat2, tt.lng2, tt.lat1, tt.lng1); math.Abs(back-got) > 1e-9 {
+				t.Errorf("reverse distance = %.6f, want %.6f", back, got)
+			}
+		})
+	}
+}
+
+func TestBoundingBox(t *testing.T) {
+	tests := []struct {
+		name          string
+		lat, lng      float64
+		radiusKm      float64
+		wrapsLng      bool // minLng > maxLng
+		fullLngRange  bool
+		clampedToPole bool
+	}{
+		{name: "Jakarta 10 km", lat: -6.1754, lng: 106.8272, radiusKm: 10},
+		{name: "London 500 km", lat: 51.5074, lng: -0.1278, radiusKm: 500},
+		{name: "east of the antimeridian", lat: 0, lng: 179.9, radiusKm: 50, wrapsLng: true},
+		{name: "west of the antimeridian", lat: -17, lng: -179.95, radiusKm: 50, wrapsLng: true},
+		{name: "north pole", lat: 89.9, lng: 10, radiusKm: 50, fullLngRange: true, clampedToPole: true},
+		{name: "south pole", lat: -89.8, lng: -120, radiusKm: 100, fullLngRange: true, clampedToPole: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minLat, maxLat, minLng, maxLng := BoundingBox(tt.lat, tt.lng, tt.radiusKm)
+
+			if !(minLat < tt.lat && tt.lat < maxLat) {
+				t.Errorf("latitude range [%f, %f] does not contain %f", minLat, maxLat, tt.lat)
+			}
+			if minLat < -90 || maxLat > 90 {
+				t.Errorf("latitude range [%f, %f] exceeds the poles", minLat, maxLat)
+			}
+			if tt.clampedToPole && minLat != -90 && maxLat != 90 {
+				t.Errorf("latitude range [%f, %f] should reach the pole", minLat, maxLat)
+			}
+			if full := minLng == -180 && maxLng == 180; full != tt.fullLngRange {
+				t.Errorf("full longitude range = %v, want %v", full, tt.fullLngRange)
+			}
+			if wraps := minLng > maxLng; wraps != tt.wrapsLng {
+				t.Errorf("minLng %f > maxLng %f = %v, want %v", minLng, maxLng, wraps, tt.wrapsLng)
+			}
+
+			// Setiap titik di tepi radius harus berada di dalam kotak
+			for bearing := 0.0; bearing < 360; bearing += 5 {
+				lat, lng := destination(tt.lat, tt.lng, bearing, tt.radiusKm*0.999)
+				if !inBox(lat, lng, minLat, maxLat, minLng, maxLng) {
+					t.Errorf("point (%f, %f) at bearing %.0f is outside the box", lat, lng, bearing)
+				}
+			}
+		})
+	}
+}
+
+// destination menghitung titik sejauh distKm dari (lat, lng) ke arah bearing (derajat)
+func destination(lat, lng, bearing, distKm float64) (float64, float64) {
+	angular := distKm / earthRadiusKm
+	lat1, lng1, theta := radians(lat), radians(lng), radians(bearing)
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(angular) + math.Cos(lat1)*math.Sin(angular)*math.Cos(theta))
+	lng2 := lng1 + math.Atan2(math.Sin(theta)*math.Sin(angular)*math.Cos(lat1),
+		math.Cos(angular)-math.Sin(lat1)*math.Sin(lat2))
+	return degrees(lat2), normalizeLng(degrees(lng2))
+}
+
+func inBox(lat, lng, minLat, maxLat, minLng, maxLng float64) bool {
+	if lat < minLat || lat > maxLat {
+		return false
+	}
+	if minLng <= maxLng {
+		return lng >= minLng && lng <= maxLng
+	}
+	return lng >= minLng || lng <= maxLng
+}
@@ -0,0 +1,248 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frekuensi RRULE yang didukung
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+)
+
+// maxRRulePeriods membatasi jumlah periode yang diperiksa agar aturan yang tidak pernah
+// menghasilkan kejadian (mis. BYMONTHDAY=31 setiap Februari) tidak berputar selamanya
+const maxRRulePeriods = 10000
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// RRule adalah subset RRULE RFC 5545: FREQ=DAILY/WEEKLY/MONTHLY dengan INTERVAL,
+// COUNT atau UNTIL, BYDAY (DAILY/WEEKLY) dan BYMONTHDAY (MONTHLY, boleh negatif).
+type RRule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      string // nilai UNTIL apa adanya; dibaca saat ekspansi karena bisa bergantung zona waktu
+	ByDay      []time.Weekday
+	ByMonthDay []int
+}
+
+// ParseRRule membaca string RRULE seperti "FREQ=WEEKLY;BYDAY=TU,TH;COUNT=10".
+// Awalan "RRULE:" boleh ada. Salah satu COUNT atau UNTIL wajib agar seri terbatas.
+func ParseRRule(value string) (*RRule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, errors.New("rrule is empty")
+	}
+
+	rule := &RRule{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ";") {
+		name, val, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		val = strings.ToUpper(strings.TrimSpace(val))
+		if !ok || name == "" || val == "" {
+			return nil, fmt.Errorf("invalid rrule part %q", part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate rrule part %s", name)
+		}
+		seen[name] = true
+
+		switch name {
+		case "FREQ":
+			if val != FreqDaily && val != FreqWeekly && val != FreqMonthly {
+				return nil, fmt.Errorf("unsupported FREQ %s, use DAILY, WEEKLY or MONTHLY", val)
+			}
+			rule.Freq = val
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, errors.New("INTERVAL must be a positive integer")
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, errors.New("COUNT must be a positive integer")
+			}
+			rule.Count = n
+		case "UNTIL":
+			if _, err := parseUntil(val, time.UTC); err != nil {
+				return nil, err
+			}
+			rule.Until = val
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				weekday, ok := rruleWeekdays[day]
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY value %q (ordinals like 1MO are not supported)", day)
+				}
+				if !containsWeekday(rule.ByDay, weekday) {
+					rule.ByDay = append(rule.ByDay, weekday)
+				}
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(day)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("invalid BYMONTHDAY value %q", day)
+				}
+				if !containsInt(rule.ByMonthDay, n) {
+					rule.ByMonthDay = append(rule.ByMonthDay, n)
+				}
+			}
+		case "WKST":
+			if val != "MO" {
+				return nil, errors.New("only WKST=MO is supported")
+			}
+		default:
+			return nil, fmt.Errorf("unsupported rrule part %s", name)
+		}
+	}
+
+	switch {
+	case rule.Freq == "":
+		return nil, errors.New("FREQ is required")
+	case rule.Count > 0 && rule.Until != "":
+		return nil, errors.New("COUNT and UNTIL cannot be used together")
+	case rule.Count == 0 && rule.Until == "":
+		return nil, errors.New("either COUNT or UNTIL is required")
+	case len(rule.ByDay) > 0 && rule.Freq == FreqMonthly:
+		return nil, errors.New("BYDAY is only supported with FREQ=DAILY or WEEKLY")
+	case len(rule.ByMonthDay) > 0 && rule.Freq != FreqMonthly:
+		return nil, errors.New("BYMONTHDAY is only supported with FREQ=MONTHLY")
+	}
+	return rule, nil
+}
+
+// Occurrences menghasilkan waktu mulai setiap kejadian sejak dtstart (termasuk dtstart
+// jika cocok dengan aturan). Perhitungan memakai jam dinding di zona waktu dtstart,
+// sehingga jam mulai tetap sama melewati pergantian DST. Error jika lebih dari max kejadian.
+func (r *RRule) Occurrences(dtstart time.Time, max int) ([]time.Time, error) {
+	loc := dtstart.Location()
+	var until time.Time
+	if r.Until != "" {
+		var err error
+		if until, err = parseUntil(r.Until, loc); err != nil {
+			return nil, err
+		}
+	}
+
+	var occurrences []time.Time
+	for period := 0; period < maxRRulePeriods; period++ {
+		for _, candidate := range r.candidates(dtstart, period) {
+			if candidate.Before(dtstart) {
+				continue
+			}
+			if !until.IsZero() && candidate.After(until) {
+				return occurrences, nil
+			}
+			occurrences = append(occurrences, candidate)
+			if r.Count > 0 && len(occurrences) == r.Count {
+				return occurrences, nil
+			}
+			if len(occurrences) > max {
+				return nil, fmt.Errorf("recurrence produces more than %d occurrences", max)
+			}
+		}
+	}
+	return occurrences, nil
+}
+
+// candidates mengembalikan kandidat kejadian berurutan untuk periode ke-n
+func (r *RRule) candidates(dtstart time.Time, period int) []time.Time {
+	y, m, d := dtstart.Date()
+	h, mi, s := dtstart.Clock()
+	loc := dtstart.Location()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, h, mi, s, 0, loc)
+	}
+
+	switch r.Freq {
+	case FreqDaily:
+		day := at(y, m, d+period*r.Interval)
+		if len(r.ByDay) > 0 && !containsWeekday(r.ByDay, day.Weekday()) {
+			return nil
+		}
+		return []time.Time{day}
+
+	case FreqWeekly:
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []time.Weekday{dtstart.Weekday()}
+		}
+		// Minggu dimulai hari Senin (WKST=MO)
+		monday := d - (int(dtstart.Weekday())+6)%7 + period*7*r.Interval
+		var result []time.Time
+		for _, weekday := range days {
+			result = append(result, at(y, m, monday+(int(weekday)+6)%7))
+		}
+		sort.Slice(result, func(i, j int) bool { return result[i].Before(result[j]) })
+		return result
+
+	default: // FreqMonthly
+		first := time.Date(y, m+time.Month(period*r.Interval), 1, 0, 0, 0, 0, loc)
+		lastDay := first.AddDate(0, 1, -1).Day()
+		monthDays := r.ByMonthDay
+		if len(monthDays) == 0 {
+			monthDays = []int{d}
+		}
+
+		var result []time.Time
+		for _, day := range monthDays {
+			if day < 0 {
+				day = lastDay + day + 1
+			}
+			// Tanggal yang tidak ada di bulan itu (mis. 31 Februari) dilewati, sesuai RFC 5545
+			if day < 1 || day > lastDay {
+				continue
+			}
+			result = append(result, at(first.Year(), first.Month(), day))
+		}
+		sort.Slice(result, func(i, j int) bool { return result[i].Before(result[j]) })
+		return result
+	}
+}
+
+// parseUntil membaca UNTIL berbentuk tanggal (inklusif sampai akhir hari di loc),
+// waktu lokal, atau waktu UTC berakhiran Z
+func parseUntil(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, loc); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102", value, loc); err == nil {
+		return t.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid UNTIL %q, use YYYYMMDD or YYYYMMDDTHHMMSSZ", value)
+}
+
+func containsWeekday(days []time.Weekday, day time.Weekday) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
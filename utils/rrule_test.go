@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestRRuleOccurrences(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		tz      string
+		dtstart string // waktu lokal di tz, format 2006-01-02 15:04
+		want    []string
+	}{
+		{name: "daily count", rule: "FREQ=DAILY;COUNT=3", tz: "Asia/Jakarta", dtstart: "2026-01-05 09:00",
+			want: []string{"2026-01-05 09:00 WIB", "2026-01-06 09:00 WIB", "2026-01-07 09:00 WIB"}},
+		{name: "daily weekdays only", rule: "FREQ=DAILY;BYDAY=MO,FR;COUNT=3", tz: "Asia/Jakarta", dtstart: "2026-01-05 09:00",
+			want: []string{"2026-01-05 09:00 WIB", "2026-01-09 09:00 WIB", "2026-01-12 09:00 WIB"}},
+		{name: "weekly by day", rule: "FREQ=WEEKLY;BYDAY=TU,TH;COUNT=4", tz: "Asia/Jakarta", dtstart: "2026-01-06 19:30",
+			want: []string{"2026-01-06 19:30 WIB", "2026-01-08 19:30 WIB", "2026-01-13 19:30 WIB", "2026-01-15 19:30 WIB"}},
+		{name: "weekly interval 2", rule: "FREQ=WEEKLY;INTERVAL=2;COUNT=3", tz: "Asia/Jakarta", dtstart: "2026-01-05 09:00",
+			want: []string{"2026-01-05 09:00 WIB", "2026-01-19 09:00 WIB", "2026-02-02 09:00 WIB"}},
+		{name: "weekly interval 2 starting mid-week", rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=3", tz: "Asia/Jakarta",
+			dtstart: "2026-01-07 09:00",
+			want:    []string{"2026-01-07 09:00 WIB", "2026-01-19 09:00 WIB", "2026-01-21 09:00 WIB"}},
+		{name: "until date is inclusive", rule: "FREQ=DAILY;UNTIL=20260107", tz: "Asia/Jakarta", dtstart: "2026-01-05 09:00",
+			want: []string{"2026-01-05 09:00 WIB", "2026-01-06 09:00 WIB", "2026-01-07 09:00 WIB"}},
+		{name: "until UTC time equal to last start", rule: "FREQ=DAILY;UNTIL=20260107T020000Z", tz: "Asia/Jakarta",
+			dtstart: "2026-01-05 09:00",
+			want:    []string{"2026-01-05 09:00 WIB", "2026-01-06 09:00 WIB", "2026-01-07 09:00 WIB"}},
+		{name: "until UTC time before last start", rule: "FREQ=DAILY;UNTIL=20260107T015959Z", tz: "Asia/Jakarta",
+			dtstart: "2026-01-05 09:00",
+			want:    []string{"2026-01-05 09:00 WIB", "2026-01-06 09:00 WIB"}},
+		{name: "count limits occurrences", rule: "FREQ=DAILY;COUNT=2", tz: "Asia/Jakarta", dtstart: "2026-01-05 09:00",
+			want: []string{"2026-01-05 09:00 WIB", "2026-01-06 09:00 WIB"}},
+		{name: "last day of month across February", rule: "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=4", tz: "UTC",
+			dtstart: "2026-01-31 18:00",
+			want:    []string{"2026-01-31 18:00 UTC", "2026-02-28 18:00 UTC", "2026-03-31 18:00 UTC", "2026-04-30 18:00 UTC"}},
+		{name: "last day of February in a leap year", rule: "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=2", tz: "UTC",
+			dtstart: "2028-01-31 18:00",
+			want:    []string{"2028-01-31 18:00 UTC", "2028-02-29 18:00 UTC"}},
+		{name: "day 31 skips short months", rule: "FREQ=MONTHLY;COUNT=3", tz: "UTC", dtstart: "2026-01-31 18:00",
+			want: []string{"2026-01-31 18:00 UTC", "2026-03-31 18:00 UTC", "2026-05-31 18:00 UTC"}},
+		{name: "wall clock kept across DST start", rule: "FREQ=WEEKLY;COUNT=3", tz: "America/New_York",
+			dtstart: "2026-03-01 19:00",
+			want:    []string{"2026-03-01 19:00 EST", "2026-03-08 19:00 EDT", "2026-03-15 19:00 EDT"}},
+		{name: "wall clock kept across DST end", rule: "FREQ=DAILY;COUNT=3", tz: "Europe/Amsterdam",
+			dtstart: "2026-10-24 10:00",
+			want:    []string{"2026-10-24 10:00 CEST", "2026-10-25 10:00 CET", "2026-10-26 10:00 CET"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRRule(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRRule(%q): %v", tt.rule, err)
+			}
+			loc, err := time.LoadLocation(tt.tz)
+			if err != nil {
+				t.Fatalf("load location: %v", err)
+			}
+			dtstart, err := time.ParseInLocation("2006-01-02 15:04", tt.dtstart, loc)
+			if err != nil {
+				t.Fatalf("parse dtstart: %v", err)
+			}
+
+			occurrences, err := rule.Occurrences(dtstart, 100)
+			if err != nil {
+				t.Fatalf("Occurrences: %v", err)
+			}
+			if len(occurrences) != len(tt.want) {
+				t.Fatalf("got %d occurrences %v, want %d", len(occurrences), occurrences, len(tt.want))
+			}
+			for i, got := range occurrences {
+				if s := got.Format("2006-01-02 15:04 MST"); s != tt.want[i] {
+					t.Errorf("occurrence %d = %s, want %s", i, s, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRRuleOccurrencesLimit(t *testing.T) {
+	rule, err := ParseRRule("FREQ=DAILY;COUNT=10")
+	if err != nil {
+		t.Fatalf("ParseRRule: %v", err)
+	}
+	if _, err := rule.Occurrences(time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC), 5); err == nil {
+		t.Error("expected an error when the rule produces more than max occurrences")
+	}
+}
+
+func TestParseRRuleErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+	}{
+		{"empty", ""},
+		{"missing FREQ", "COUNT=3"},
+		{"unbounded", "FREQ=DAILY"},
+		{"COUNT and UNTIL together", "FREQ=DAILY;COUNT=3;UNTIL=20260107"},
+		{"unsupported FREQ", "FREQ=YEARLY;COUNT=3"},
+		{"zero INTERVAL", "FREQ=DAILY;INTERVAL=0;COUNT=3"},
+		{"invalid UNTIL", "FREQ=DAILY;UNTIL=2026-01-07"},
+		{"BYDAY ordinal", "FREQ=WEEKLY;BYDAY=1MO;COUNT=3"},
+		{"BYDAY with MONTHLY", "FREQ=MONTHLY;BYDAY=MO;COUNT=3"},
+		{"BYMONTHDAY with WEEKLY", "FREQ=WEEKLY;BYMONTHDAY=1;COUNT=3"},
+		{"BYMONTHDAY out of range", "FREQ=MONTHLY;BYMONTHDAY=32;COUNT=3"},
+		{"duplicate part", "FREQ=DAILY;COUNT=3;COUNT=4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseRRule(tt.rule); err == nil {
+				t.Errorf("ParseRRule(%q) succeeded, want an error", tt.rule)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package utils
+
+import "strings"
+
+// Slugify mengubah nama menjadi slug huruf kecil: huruf dan angka ASCII dipertahankan,
+// karakter lain menjadi satu tanda "-" (mis. "Rock & Roll" -> "rock-roll")
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
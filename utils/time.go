@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// localTimeLayouts adalah format waktu tanpa offset yang diterima; dibaca sebagai waktu
+// lokal di zona waktu yang diberikan
+var localTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+}
+
+// ParseTimeIn membaca waktu ISO-8601 dengan offset (RFC 3339), atau waktu lokal tanpa
+// offset di loc, dan mengembalikannya dalam UTC
+func ParseTimeIn(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	for _, layout := range localTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, use ISO-8601 (e.g. 2006-01-02T15:04:05+07:00) or 2006-01-02 15:04:05", value)
+}
+
+// FormatLocal menampilkan t sebagai ISO-8601 dengan offset zona waktu loc
+func FormatLocal(t time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(loc).Format(time.RFC3339)
+}